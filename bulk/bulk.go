@@ -0,0 +1,213 @@
+// Package bulk imports and exports movies, halls and repertoires as CSV or
+// NDJSON (one JSON document per line).
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tijanadmi/movieginmongoapi/repository"
+)
+
+// Supported formats
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// Supported kinds of records
+const (
+	KindMovies      = "movies"
+	KindHalls       = "halls"
+	KindRepertoires = "repertoires"
+)
+
+var (
+	ErrUnknownFormat = errors.New("format must be csv or ndjson")
+	ErrUnknownKind   = errors.New("kind must be movies, halls or repertoires")
+)
+
+// RowError is the validation or insert error of one input row
+type RowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// Report describes the outcome of an import. Row numbers are the line numbers
+// of the input, so the CSV header is row 1.
+type Report struct {
+	Kind     string     `json:"kind"`
+	DryRun   bool       `json:"dryRun"`
+	Rows     int        `json:"rows"`
+	Valid    int        `json:"valid"`
+	Imported int        `json:"imported"`
+	Errors   []RowError `json:"errors"`
+}
+
+// HasErrors reports whether any row failed validation or could not be inserted
+func (r *Report) HasErrors() bool {
+	return len(r.Errors) > 0
+}
+
+func (r *Report) addError(row int, err error) {
+	r.Errors = append(r.Errors, RowError{Row: row, Error: err.Error()})
+}
+
+// row is one input record: the CSV fields by column name, or an NDJSON line
+type row struct {
+	line   int
+	fields map[string]string
+	raw    []byte
+}
+
+// field returns the trimmed value of a CSV column
+func (r row) field(name string) string {
+	return strings.TrimSpace(r.fields[name])
+}
+
+// kind validates and inserts the records of one collection
+type kind interface {
+	// load reads what validation needs from the store
+	load(ctx context.Context, store repository.Store) error
+	// parse decodes and validates one row, keeping the record for insert
+	parse(r row) error
+	// insert stores the parsed records; it stops at the first row that cannot
+	// be inserted, reports it and returns its error
+	insert(ctx context.Context, store repository.Store, report *Report) error
+}
+
+func newKind(name string) (kind, error) {
+	switch name {
+	case KindMovies:
+		return &movieKind{}, nil
+	case KindHalls:
+		return &hallKind{}, nil
+	case KindRepertoires:
+		return &repertoireKind{}, nil
+	default:
+		return nil, ErrUnknownKind
+	}
+}
+
+// Import reads records of the given kind and validates every row. Unless
+// dryRun is set, the records are inserted, but only when all rows are valid.
+// They are inserted in one transaction: when a row cannot be inserted, the
+// report names it and none of the records are imported.
+func Import(ctx context.Context, store repository.Store, kindName string, format string, input io.Reader, dryRun bool) (*Report, error) {
+	k, err := newKind(kindName)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := readRows(input, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := k.load(ctx, store); err != nil {
+		return nil, err
+	}
+
+	report := &Report{Kind: kindName, DryRun: dryRun, Errors: make([]RowError, 0)}
+	for _, r := range rows {
+		report.Rows++
+		if err := k.parse(r); err != nil {
+			report.addError(r.line, err)
+			continue
+		}
+		report.Valid++
+	}
+
+	if dryRun || report.HasErrors() {
+		return report, nil
+	}
+
+	err = store.WithTransaction(ctx, func(ctx context.Context) error {
+		// a retried transaction inserts all the records again
+		report.Imported = 0
+		report.Errors = report.Errors[:0]
+		return k.insert(ctx, store, report)
+	})
+	if err != nil {
+		report.Imported = 0
+		if !report.HasErrors() {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// readRows reads all rows of the input
+func readRows(input io.Reader, format string) ([]row, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(input)
+	case FormatNDJSON:
+		return readNDJSON(input)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+func readCSV(input io.Reader) ([]row, error) {
+	reader := csv.NewReader(input)
+	reader.FieldsPerRecord = 0
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("missing CSV header")
+		}
+		return nil, fmt.Errorf("could not read CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	rows := make([]row, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		fields := make(map[string]string, len(header))
+		for i, name := range header {
+			fields[name] = record[i]
+		}
+		rows = append(rows, row{line: line, fields: fields})
+	}
+
+	return rows, nil
+}
+
+func readNDJSON(input io.Reader) ([]row, error) {
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	rows := make([]row, 0)
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		rows = append(rows, row{line: line, raw: append([]byte(nil), raw...)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read NDJSON: %w", err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,269 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/tijanadmi/movieginmongoapi/db/mock"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// expectTransaction lets the mock store run the inserts of an import
+func expectTransaction(store *mockdb.MockStore) {
+	store.EXPECT().
+		WithTransaction(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+func TestImportMoviesCSV(t *testing.T) {
+	input := `title,duration,genre,actors,screening,ageRating,title.en
+Ko to tamo peva,86,"komedija, drama","Pavle Vujisić, Dragan Nikolić",1980-12-25,12,Who's Singin' Over There?
+,90,drama,,,,
+Maratonci trče počasni krug,92,komedija,,1982-01-01,15,
+ko to tamo peva,86,,,,,
+Poseban tretman,94,drama,,not a date,,
+`
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListMovies(gomock.Any()).Times(1).Return([]models.Movie{}, nil)
+	store.EXPECT().AddMovie(gomock.Any(), gomock.Any()).Times(0)
+
+	report, err := Import(context.Background(), store, KindMovies, FormatCSV, strings.NewReader(input), false)
+	require.NoError(t, err)
+	require.Equal(t, 5, report.Rows)
+	require.Equal(t, 1, report.Valid)
+	require.Zero(t, report.Imported)
+	require.Equal(t, []RowError{
+		{Row: 3, Error: "title is required"},
+		{Row: 4, Error: "invalid ageRating 15"},
+		{Row: 5, Error: `duplicate title "ko to tamo peva", already on row 2`},
+		{Row: 6, Error: "screening: invalid date, should be YYYY-MM-DD or RFC 3339"},
+	}, report.Errors)
+}
+
+func TestImportMoviesNDJSON(t *testing.T) {
+	input := `{"title":"Ko to tamo peva","duration":86,"genre":["komedija"],"translations":{"en":{"title":"Who's Singin' Over There?"}}}
+
+{"title":"Maratonci trče počasni krug","duration":92,"genre":"komedija, drama","score":5,"avgRating":5,"numOfRatings":100,"deleted_at":"2024-06-01T00:00:00Z","deleted_by":"admin"}
+`
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListMovies(gomock.Any()).Times(1).Return([]models.Movie{}, nil)
+	store.EXPECT().
+		AddMovie(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(ctx context.Context, movie *models.Movie) (*models.Movie, error) {
+			// the ratings and the deletion marks are never imported
+			require.Zero(t, movie.Score)
+			require.Zero(t, movie.AvgRating)
+			require.Zero(t, movie.NumOfRatings)
+			require.Nil(t, movie.DeletedAt)
+			require.Empty(t, movie.DeletedBy)
+			return movie, nil
+		})
+	expectTransaction(store)
+
+	report, err := Import(context.Background(), store, KindMovies, FormatNDJSON, strings.NewReader(input), false)
+	require.NoError(t, err)
+	require.False(t, report.HasErrors())
+	require.Equal(t, 2, report.Rows)
+	require.Equal(t, 2, report.Imported)
+}
+
+func TestImportDryRun(t *testing.T) {
+	cinema := models.Cinema{ID: primitive.NewObjectID(), Name: "Beograd"}
+	input := "cinemaId,name,rows,cols\n" + cinema.ID.Hex() + ",Sala 1,\"A, B, C\",\"1, 2, 3, 4\"\n"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListCinemas(gomock.Any()).Times(1).Return([]models.Cinema{cinema}, nil)
+	store.EXPECT().ListHalls(gomock.Any()).Times(1).Return([]models.Hall{}, nil)
+	store.EXPECT().InsertHall(gomock.Any(), gomock.Any()).Times(0)
+
+	report, err := Import(context.Background(), store, KindHalls, FormatCSV, strings.NewReader(input), true)
+	require.NoError(t, err)
+	require.True(t, report.DryRun)
+	require.Equal(t, 1, report.Valid)
+	require.Zero(t, report.Imported)
+}
+
+func TestImportHallsCSV(t *testing.T) {
+	cinema := models.Cinema{ID: primitive.NewObjectID(), Name: "Beograd"}
+	id := cinema.ID.Hex()
+	unknown := primitive.NewObjectID().Hex()
+	input := `cinemaId,name,rows,cols
+` + id + `,Sala 1,"A, B","1, 2"
+` + id + `,Sala 2,"A, A","1, 2"
+` + id + `,Sala 3,A,"1, x"
+` + id + `,Sala 4,,1
+,Sala 5,A,1
+` + unknown + `,Sala 6,A,1
+`
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListCinemas(gomock.Any()).Times(1).Return([]models.Cinema{cinema}, nil)
+	store.EXPECT().ListHalls(gomock.Any()).Times(1).Return([]models.Hall{{CinemaID: cinema.ID, Name: "Sala 1"}, {Name: "Sala 2"}}, nil)
+
+	report, err := Import(context.Background(), store, KindHalls, FormatCSV, strings.NewReader(input), false)
+	require.NoError(t, err)
+	require.Equal(t, []RowError{
+		{Row: 2, Error: `hall "Sala 1" already exists`},
+		{Row: 3, Error: `duplicate row "A"`},
+		{Row: 4, Error: `invalid column "x"`},
+		{Row: 5, Error: "rows are required"},
+		{Row: 6, Error: "cinemaId is required"},
+		{Row: 7, Error: "cinema " + unknown + " does not exist"},
+	}, report.Errors)
+}
+
+func TestImportRollsBack(t *testing.T) {
+	cinema := models.Cinema{ID: primitive.NewObjectID(), Name: "Beograd"}
+	input := `{"cinemaId":"` + cinema.ID.Hex() + `","name":"Sala 1","rows":["A"],"cols":[1]}
+{"cinemaId":"` + cinema.ID.Hex() + `","name":"Sala 2","rows":["A"],"cols":[1]}
+`
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListCinemas(gomock.Any()).Times(1).Return([]models.Cinema{cinema}, nil)
+	store.EXPECT().ListHalls(gomock.Any()).Times(1).Return([]models.Hall{}, nil)
+	gomock.InOrder(
+		store.EXPECT().InsertHall(gomock.Any(), gomock.Any()).Times(1).Return(&models.Hall{}, nil),
+		store.EXPECT().InsertHall(gomock.Any(), gomock.Any()).Times(1).Return(nil, repository.ErrHallNameTaken),
+	)
+	expectTransaction(store)
+
+	report, err := Import(context.Background(), store, KindHalls, FormatNDJSON, strings.NewReader(input), false)
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Valid)
+	require.Zero(t, report.Imported)
+	require.Equal(t, []RowError{{Row: 2, Error: repository.ErrHallNameTaken.Error()}}, report.Errors)
+}
+
+func TestImportRepertoiresCSV(t *testing.T) {
+	movie := models.Movie{ID: primitive.NewObjectID(), Title: "Ko to tamo peva"}
+	hall := models.Hall{ID: primitive.NewObjectID(), Name: "Sala 1", Rows: []string{"A", "B"}, Cols: []int{1, 2, 3}}
+	scheduled := models.Repertoire{
+		MovieID: movie.ID,
+		Date:    time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+		Time:    "20:00",
+		HallID:  hall.ID,
+		Hall:    hall.Name,
+	}
+	// halls of different cinemas may share a name
+	sameName := []models.Hall{
+		{ID: primitive.NewObjectID(), CinemaID: primitive.NewObjectID(), Name: "Sala 2", Rows: []string{"A"}, Cols: []int{1}},
+		{ID: primitive.NewObjectID(), CinemaID: primitive.NewObjectID(), Name: "Sala 2", Rows: []string{"A"}, Cols: []int{1}},
+	}
+
+	input := `movieId,movieTitle,date,time,hallId,hall,numOfTickets
+,ko to tamo peva,2024-06-01,18:00,,Sala 1,
+` + movie.ID.Hex() + `,,2024-06-01,20:00,,Sala 1,
+,Nepoznat film,2024-06-02,18:00,,Sala 1,
+,Ko to tamo peva,2024-06-02,25:00,,Sala 1,
+,Ko to tamo peva,2024-06-02,18:00,,Sala 9,
+,Ko to tamo peva,2024-06-02,18:00,,Sala 1,7
+,Ko to tamo peva,2024-06-02,21:00,` + hall.ID.Hex() + `,,4
+,Ko to tamo peva,2024-06-02,21:00,,Sala 1,
+,Ko to tamo peva,2024-06-03,18:00,,Sala 2,
+`
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListMovies(gomock.Any()).Times(1).Return([]models.Movie{movie}, nil)
+	store.EXPECT().ListHalls(gomock.Any()).Times(1).Return(append([]models.Hall{hall}, sameName...), nil)
+	store.EXPECT().ListRepertoires(gomock.Any()).Times(1).Return([]models.Repertoire{scheduled}, nil)
+
+	report, err := Import(context.Background(), store, KindRepertoires, FormatCSV, strings.NewReader(input), true)
+	require.NoError(t, err)
+	require.Equal(t, 9, report.Rows)
+	require.Equal(t, 2, report.Valid)
+	require.Equal(t, []RowError{
+		{Row: 3, Error: `hall "Sala 1" is already scheduled on 2024-06-01 at 20:00`},
+		{Row: 4, Error: `movie "Nepoznat film" does not exist`},
+		{Row: 5, Error: "time must be in HH:MM format"},
+		{Row: 6, Error: `hall "Sala 9" does not exist`},
+		{Row: 7, Error: "numOfTickets must be between 1 and 6"},
+		{Row: 9, Error: `hall "Sala 1" is scheduled twice on 2024-06-02 at 21:00, already on row 8`},
+		{Row: 10, Error: `hall "Sala 2" is ambiguous, use hallId`},
+	}, report.Errors)
+}
+
+func TestImportUnknownKindAndFormat(t *testing.T) {
+	_, err := Import(context.Background(), nil, "users", FormatCSV, strings.NewReader(""), true)
+	require.ErrorIs(t, err, ErrUnknownKind)
+
+	_, err = Import(context.Background(), nil, KindMovies, "xml", strings.NewReader(""), true)
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestExportHallsRoundTrip(t *testing.T) {
+	cinema := models.Cinema{ID: primitive.NewObjectID(), Name: "Beograd"}
+	halls := []models.Hall{
+		{ID: primitive.NewObjectID(), CinemaID: cinema.ID, Name: "Sala 1", Rows: []string{"A", "B"}, Cols: []int{1, 2}},
+		{ID: primitive.NewObjectID(), CinemaID: cinema.ID, Name: "Sala 2", Rows: []string{"A"}, Cols: []int{1, 2, 3}},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	for _, format := range []string{FormatCSV, FormatNDJSON} {
+		t.Run(format, func(t *testing.T) {
+			gomock.InOrder(
+				store.EXPECT().ListHalls(gomock.Any()).Times(1).Return(halls, nil),
+				store.EXPECT().ListHalls(gomock.Any()).Times(1).Return([]models.Hall{}, nil),
+			)
+			store.EXPECT().ListCinemas(gomock.Any()).Times(1).Return([]models.Cinema{cinema}, nil)
+
+			var buf bytes.Buffer
+			require.NoError(t, Export(context.Background(), store, KindHalls, format, &buf))
+
+			var inserted []models.Hall
+			store.EXPECT().
+				InsertHall(gomock.Any(), gomock.Any()).
+				Times(len(halls)).
+				DoAndReturn(func(ctx context.Context, hall *models.Hall) (*models.Hall, error) {
+					inserted = append(inserted, *hall)
+					return hall, nil
+				})
+			expectTransaction(store)
+
+			report, err := Import(context.Background(), store, KindHalls, format, &buf, false)
+			require.NoError(t, err)
+			require.False(t, report.HasErrors())
+			require.Equal(t, len(halls), report.Imported)
+			for i := range halls {
+				require.Equal(t, halls[i].CinemaID, inserted[i].CinemaID)
+				require.Equal(t, halls[i].Name, inserted[i].Name)
+				require.Equal(t, halls[i].Rows, inserted[i].Rows)
+				require.Equal(t, halls[i].Cols, inserted[i].Cols)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package bulk
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	"github.com/tijanadmi/movieginmongoapi/repository"
+)
+
+// Export writes all records of the given kind in the given format. The CSV
+// columns are the ones Import reads, so an export can be edited and imported
+// into another database.
+func Export(ctx context.Context, store repository.Store, kindName string, format string, output io.Writer) error {
+	if format != FormatCSV && format != FormatNDJSON {
+		return ErrUnknownFormat
+	}
+
+	var header []string
+	var records [][]string
+	var documents []any
+
+	switch kindName {
+	case KindMovies:
+		movies, err := store.ListMovies(ctx)
+		if err != nil {
+			return err
+		}
+		header = movieHeader()
+		for _, movie := range movies {
+			records = append(records, movieRecord(movie))
+			documents = append(documents, movie)
+		}
+	case KindHalls:
+		halls, err := store.ListHalls(ctx)
+		if err != nil {
+			return err
+		}
+		header = hallColumns
+		for _, hall := range halls {
+			records = append(records, hallRecord(hall))
+			documents = append(documents, hall)
+		}
+	case KindRepertoires:
+		repertoires, err := store.ListRepertoires(ctx)
+		if err != nil {
+			return err
+		}
+		movies, err := store.ListMovies(ctx)
+		if err != nil {
+			return err
+		}
+		titles := make(map[string]string, len(movies))
+		for _, movie := range movies {
+			titles[movie.ID.Hex()] = movie.Title
+		}
+
+		header = repertoireColumns
+		for _, repertoire := range repertoires {
+			title := titles[repertoire.MovieID.Hex()]
+			repertoire.DateSt = repertoire.Date.Format("2006-01-02")
+			records = append(records, repertoireRecordFields(repertoire, title))
+			documents = append(documents, repertoireDocument{Repertoire: repertoire, MovieTitle: title})
+		}
+	default:
+		return ErrUnknownKind
+	}
+
+	if format == FormatNDJSON {
+		encoder := json.NewEncoder(output)
+		for _, document := range documents {
+			if err := encoder.Encode(document); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	writer := csv.NewWriter(output)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+	return writer.Error()
+}
@@ -0,0 +1,156 @@
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// hallColumns are the CSV columns of a hall; rows and cols are comma-separated
+// lists. The id is ignored on import. CSV only describes rectangular halls,
+// seat maps are imported and exported with NDJSON.
+var hallColumns = []string{"id", "cinemaId", "name", "rows", "cols"}
+
+// hallImport holds the hall fields read from NDJSON; the id and the deletion
+// marks of an export are not imported
+type hallImport struct {
+	CinemaID primitive.ObjectID `json:"cinemaId"`
+	Name     string             `json:"name"`
+	Rows     []string           `json:"rows"`
+	Cols     []int              `json:"cols"`
+	Seats    []models.Seat      `json:"seats"`
+}
+
+type parsedHall struct {
+	line int
+	hall *models.Hall
+}
+
+type hallKind struct {
+	cinemas  map[primitive.ObjectID]bool
+	existing map[string]bool
+	names    map[string]int
+	halls    []parsedHall
+}
+
+func (k *hallKind) load(ctx context.Context, store repository.Store) error {
+	cinemas, err := store.ListCinemas(ctx)
+	if err != nil {
+		return err
+	}
+	k.cinemas = make(map[primitive.ObjectID]bool, len(cinemas))
+	for _, cinema := range cinemas {
+		k.cinemas[cinema.ID] = true
+	}
+
+	halls, err := store.ListHalls(ctx)
+	if err != nil {
+		return err
+	}
+
+	k.existing = make(map[string]bool, len(halls))
+	for _, hall := range halls {
+		k.existing[hallKey(hall.CinemaID, hall.Name)] = true
+	}
+	k.names = make(map[string]int)
+	return nil
+}
+
+func (k *hallKind) parse(r row) error {
+	hall, err := parseHall(r)
+	if err != nil {
+		return err
+	}
+	if err := models.ValidateHall(hall); err != nil {
+		return err
+	}
+	if !k.cinemas[hall.CinemaID] {
+		return fmt.Errorf("cinema %s does not exist", hall.CinemaID.Hex())
+	}
+
+	key := hallKey(hall.CinemaID, hall.Name)
+	if k.existing[key] {
+		return fmt.Errorf("hall %q already exists", hall.Name)
+	}
+	if line, ok := k.names[key]; ok {
+		return fmt.Errorf("duplicate hall %q, already on row %d", hall.Name, line)
+	}
+	k.names[key] = r.line
+
+	k.halls = append(k.halls, parsedHall{line: r.line, hall: hall})
+	return nil
+}
+
+// hallKey identifies a hall by name; names are unique within a cinema
+func hallKey(cinemaID primitive.ObjectID, name string) string {
+	return cinemaID.Hex() + "|" + strings.ToLower(name)
+}
+
+func (k *hallKind) insert(ctx context.Context, store repository.Store, report *Report) error {
+	for _, parsed := range k.halls {
+		if _, err := store.InsertHall(ctx, parsed.hall); err != nil {
+			report.addError(parsed.line, err)
+			return err
+		}
+		report.Imported++
+	}
+	return nil
+}
+
+func parseHall(r row) (*models.Hall, error) {
+	if r.raw != nil {
+		var record hallImport
+		if err := json.Unmarshal(r.raw, &record); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return &models.Hall{
+			CinemaID: record.CinemaID,
+			Name:     record.Name,
+			Rows:     record.Rows,
+			Cols:     record.Cols,
+			Seats:    record.Seats,
+		}, nil
+	}
+
+	hall := &models.Hall{}
+
+	if value := r.field("cinemaId"); value != "" {
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cinemaId %q", value)
+		}
+		hall.CinemaID = id
+	}
+	hall.Name = r.field("name")
+	hall.Rows = models.SplitStringList(r.field("rows"))
+	for _, value := range models.SplitStringList(r.field("cols")) {
+		col, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid column %q", value)
+		}
+		hall.Cols = append(hall.Cols, col)
+	}
+
+	return hall, nil
+}
+
+func hallRecord(hall models.Hall) []string {
+	cols := make([]string, len(hall.Cols))
+	for i, col := range hall.Cols {
+		cols[i] = strconv.Itoa(col)
+	}
+
+	return []string{
+		hall.ID.Hex(),
+		hall.CinemaID.Hex(),
+		hall.Name,
+		strings.Join(hall.Rows, ", "),
+		strings.Join(cols, ", "),
+	}
+}
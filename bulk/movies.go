@@ -0,0 +1,249 @@
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/util"
+)
+
+// movieColumns are the CSV columns of a movie. Translations use additional
+// title.<lang> and plot.<lang> columns, e.g. title.en. The id is ignored on import.
+var movieColumns = []string{"id", "title", "duration", "genre", "directors", "actors", "screening", "plot", "poster", "ageRating"}
+
+// translationLanguages are the languages exported as translation columns
+func translationLanguages() []string {
+	langs := make([]string, 0, len(models.SupportedLanguages))
+	for _, lang := range models.SupportedLanguages {
+		if lang != models.DefaultLanguage {
+			langs = append(langs, lang)
+		}
+	}
+	return langs
+}
+
+func movieHeader() []string {
+	header := append([]string(nil), movieColumns...)
+	for _, lang := range translationLanguages() {
+		header = append(header, "title."+lang, "plot."+lang)
+	}
+	return header
+}
+
+// movieImport holds the movie fields read from NDJSON; the id, the poster
+// sizes, the ratings and the deletion marks of an export are not imported
+type movieImport struct {
+	Title        string                             `json:"title"`
+	Duration     int32                              `json:"duration"`
+	Genre        models.StringList                  `json:"genre"`
+	Directors    models.StringList                  `json:"directors"`
+	Actors       models.StringList                  `json:"actors"`
+	Screening    time.Time                          `json:"screening"`
+	Plot         string                             `json:"plot"`
+	Translations map[string]models.MovieTranslation `json:"translations"`
+	Poster       string                             `json:"poster"`
+	Screenings   []models.Screening                 `json:"screenings"`
+	AgeRating    int                                `json:"ageRating"`
+}
+
+type parsedMovie struct {
+	line  int
+	movie *models.Movie
+}
+
+type movieKind struct {
+	existing map[string]bool
+	titles   map[string]int
+	movies   []parsedMovie
+}
+
+func (k *movieKind) load(ctx context.Context, store repository.Store) error {
+	movies, err := store.ListMovies(ctx)
+	if err != nil {
+		return err
+	}
+
+	k.existing = make(map[string]bool, len(movies))
+	for _, movie := range movies {
+		k.existing[strings.ToLower(movie.Title)] = true
+	}
+	k.titles = make(map[string]int)
+	return nil
+}
+
+func (k *movieKind) parse(r row) error {
+	movie, err := parseMovie(r)
+	if err != nil {
+		return err
+	}
+	if err := validateMovie(movie); err != nil {
+		return err
+	}
+
+	key := strings.ToLower(movie.Title)
+	if k.existing[key] {
+		return fmt.Errorf("movie %q already exists", movie.Title)
+	}
+	if line, ok := k.titles[key]; ok {
+		return fmt.Errorf("duplicate title %q, already on row %d", movie.Title, line)
+	}
+	k.titles[key] = r.line
+
+	k.movies = append(k.movies, parsedMovie{line: r.line, movie: movie})
+	return nil
+}
+
+func (k *movieKind) insert(ctx context.Context, store repository.Store, report *Report) error {
+	for _, parsed := range k.movies {
+		if _, err := store.AddMovie(ctx, parsed.movie); err != nil {
+			report.addError(parsed.line, err)
+			return err
+		}
+		report.Imported++
+	}
+	return nil
+}
+
+func parseMovie(r row) (*models.Movie, error) {
+	if r.raw != nil {
+		var record movieImport
+		if err := json.Unmarshal(r.raw, &record); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return &models.Movie{
+			Title:        record.Title,
+			Duration:     record.Duration,
+			Genre:        record.Genre,
+			Directors:    record.Directors,
+			Actors:       record.Actors,
+			Screening:    record.Screening,
+			Plot:         record.Plot,
+			Translations: record.Translations,
+			Poster:       record.Poster,
+			Screenings:   record.Screenings,
+			AgeRating:    record.AgeRating,
+		}, nil
+	}
+
+	movie := &models.Movie{}
+
+	movie.Title = r.field("title")
+	movie.Genre = models.SplitStringList(r.field("genre"))
+	movie.Directors = models.SplitStringList(r.field("directors"))
+	movie.Actors = models.SplitStringList(r.field("actors"))
+	movie.Plot = r.field("plot")
+	movie.Poster = r.field("poster")
+
+	if value := r.field("duration"); value != "" {
+		duration, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, errors.New("duration must be a number of minutes")
+		}
+		movie.Duration = int32(duration)
+	}
+
+	if value := r.field("screening"); value != "" {
+		screening, err := parseDateTime(value)
+		if err != nil {
+			return nil, fmt.Errorf("screening: %w", err)
+		}
+		movie.Screening = screening
+	}
+
+	if value := r.field("ageRating"); value != "" {
+		ageRating, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.New("ageRating must be a number")
+		}
+		movie.AgeRating = ageRating
+	}
+
+	for column := range r.fields {
+		field, lang, ok := strings.Cut(column, ".")
+		if !ok || (field != "title" && field != "plot") {
+			continue
+		}
+		value := r.field(column)
+		if value == "" {
+			continue
+		}
+		if movie.Translations == nil {
+			movie.Translations = make(map[string]models.MovieTranslation)
+		}
+		translation := movie.Translations[lang]
+		if field == "title" {
+			translation.Title = value
+		} else {
+			translation.Plot = value
+		}
+		movie.Translations[lang] = translation
+	}
+
+	return movie, nil
+}
+
+// parseDateTime accepts a date (YYYY-MM-DD) or an RFC 3339 timestamp
+func parseDateTime(value string) (time.Time, error) {
+	if date, err := util.ParseDate(value); err == nil {
+		return date, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("invalid date, should be YYYY-MM-DD or RFC 3339")
+	}
+	return t.UTC(), nil
+}
+
+func validateMovie(movie *models.Movie) error {
+	if movie.Title == "" {
+		return errors.New("title is required")
+	}
+	if movie.Duration <= 0 {
+		return errors.New("duration must be positive")
+	}
+	if !models.IsValidAgeRating(movie.AgeRating) {
+		return fmt.Errorf("invalid ageRating %d", movie.AgeRating)
+	}
+	for lang := range movie.Translations {
+		if !models.IsSupportedLanguage(lang) {
+			return fmt.Errorf("unsupported translation language %q", lang)
+		}
+	}
+	return nil
+}
+
+func movieRecord(movie models.Movie) []string {
+	screening := ""
+	if !movie.Screening.IsZero() {
+		screening = movie.Screening.UTC().Format(time.RFC3339)
+	}
+	ageRating := ""
+	if movie.AgeRating != 0 {
+		ageRating = strconv.Itoa(movie.AgeRating)
+	}
+
+	record := []string{
+		movie.ID.Hex(),
+		movie.Title,
+		strconv.Itoa(int(movie.Duration)),
+		strings.Join(movie.Genre, ", "),
+		strings.Join(movie.Directors, ", "),
+		strings.Join(movie.Actors, ", "),
+		screening,
+		movie.Plot,
+		movie.Poster,
+		ageRating,
+	}
+	for _, lang := range translationLanguages() {
+		translation := movie.Translations[lang]
+		record = append(record, translation.Title, translation.Plot)
+	}
+	return record
+}
@@ -0,0 +1,279 @@
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// repertoireColumns are the CSV columns of a repertoire. On import the movie is
+// given either by movieId or by movieTitle, the hall by hallId or by its name,
+// and numOfTickets defaults to the number of sellable seats in the hall.
+var repertoireColumns = []string{"id", "movieId", "movieTitle", "date", "time", "hallId", "hall", "numOfTickets"}
+
+// repertoireDocument is an exported repertoire with the title of its movie
+type repertoireDocument struct {
+	models.Repertoire
+	MovieTitle string `json:"movieTitle,omitempty"`
+}
+
+// repertoireRecord holds the schedule of a repertoire, whose movie may be
+// given by title; the sold, blocked and held seats and the deletion marks of
+// an export are not imported
+type repertoireRecord struct {
+	MovieID      primitive.ObjectID `json:"movieId"`
+	MovieTitle   string             `json:"movieTitle"`
+	DateSt       string             `json:"dateSt"`
+	Date         time.Time          `json:"date"`
+	Time         string             `json:"time"`
+	CinemaID     primitive.ObjectID `json:"cinemaId"`
+	HallID       primitive.ObjectID `json:"hallId"`
+	Hall         string             `json:"hall"`
+	NumOfTickets int                `json:"numOfTickets"`
+}
+
+func (record *repertoireRecord) repertoire() *models.Repertoire {
+	return &models.Repertoire{
+		MovieID:      record.MovieID,
+		DateSt:       record.DateSt,
+		Date:         record.Date,
+		Time:         record.Time,
+		CinemaID:     record.CinemaID,
+		HallID:       record.HallID,
+		Hall:         record.Hall,
+		NumOfTickets: record.NumOfTickets,
+	}
+}
+
+type parsedRepertoire struct {
+	line       int
+	repertoire *models.Repertoire
+}
+
+type repertoireKind struct {
+	movies      map[primitive.ObjectID]models.Movie
+	titles      map[string][]primitive.ObjectID
+	halls       map[primitive.ObjectID]models.Hall
+	hallNames   map[string][]models.Hall
+	slots       map[string]int
+	repertoires []parsedRepertoire
+}
+
+func (k *repertoireKind) load(ctx context.Context, store repository.Store) error {
+	movies, err := store.ListMovies(ctx)
+	if err != nil {
+		return err
+	}
+	k.movies = make(map[primitive.ObjectID]models.Movie, len(movies))
+	k.titles = make(map[string][]primitive.ObjectID)
+	for _, movie := range movies {
+		k.movies[movie.ID] = movie
+		key := strings.ToLower(movie.Title)
+		k.titles[key] = append(k.titles[key], movie.ID)
+	}
+
+	halls, err := store.ListHalls(ctx)
+	if err != nil {
+		return err
+	}
+	k.halls = make(map[primitive.ObjectID]models.Hall, len(halls))
+	k.hallNames = make(map[string][]models.Hall, len(halls))
+	for _, hall := range halls {
+		k.halls[hall.ID] = hall
+		k.hallNames[hall.Name] = append(k.hallNames[hall.Name], hall)
+	}
+
+	repertoires, err := store.ListRepertoires(ctx)
+	if err != nil {
+		return err
+	}
+	k.slots = make(map[string]int)
+	for _, repertoire := range repertoires {
+		k.slots[slotKey(repertoire.HallID, repertoire.Date, repertoire.Time)] = 0
+	}
+	return nil
+}
+
+// slotKey identifies a screening slot; a hall shows one movie at a time
+func slotKey(hallID primitive.ObjectID, date time.Time, timeValue string) string {
+	return hallID.Hex() + "|" + date.Format("2006-01-02") + "|" + timeValue
+}
+
+func (k *repertoireKind) parse(r row) error {
+	record, err := parseRepertoire(r)
+	if err != nil {
+		return err
+	}
+
+	if err := k.resolveMovie(record); err != nil {
+		return err
+	}
+	repertoire := record.repertoire()
+
+	if repertoire.DateSt != "" {
+		date, err := util.ParseDate(repertoire.DateSt)
+		if err != nil {
+			return err
+		}
+		repertoire.Date = date
+	}
+	if repertoire.Date.IsZero() {
+		return errors.New("date is required")
+	}
+	repertoire.DateSt = repertoire.Date.Format("2006-01-02")
+
+	if _, err := time.Parse("15:04", repertoire.Time); err != nil {
+		return errors.New("time must be in HH:MM format")
+	}
+
+	hall, err := k.resolveHall(repertoire)
+	if err != nil {
+		return err
+	}
+	capacity := len(hall.SellableSeats())
+	if repertoire.NumOfTickets == 0 {
+		repertoire.NumOfTickets = capacity
+	}
+	if repertoire.NumOfTickets < 0 || repertoire.NumOfTickets > capacity {
+		return fmt.Errorf("numOfTickets must be between 1 and %d", capacity)
+	}
+
+	key := slotKey(repertoire.HallID, repertoire.Date, repertoire.Time)
+	if line, ok := k.slots[key]; ok {
+		if line == 0 {
+			return fmt.Errorf("hall %q is already scheduled on %s at %s", repertoire.Hall, repertoire.DateSt, repertoire.Time)
+		}
+		return fmt.Errorf("hall %q is scheduled twice on %s at %s, already on row %d", repertoire.Hall, repertoire.DateSt, repertoire.Time, line)
+	}
+	k.slots[key] = r.line
+
+	k.repertoires = append(k.repertoires, parsedRepertoire{line: r.line, repertoire: repertoire})
+	return nil
+}
+
+func (k *repertoireKind) resolveMovie(record *repertoireRecord) error {
+	if !record.MovieID.IsZero() {
+		if _, ok := k.movies[record.MovieID]; !ok {
+			return fmt.Errorf("movie %s does not exist", record.MovieID.Hex())
+		}
+		return nil
+	}
+
+	if record.MovieTitle == "" {
+		return errors.New("movieId or movieTitle is required")
+	}
+	ids := k.titles[strings.ToLower(record.MovieTitle)]
+	switch len(ids) {
+	case 0:
+		return fmt.Errorf("movie %q does not exist", record.MovieTitle)
+	case 1:
+		record.MovieID = ids[0]
+		return nil
+	default:
+		return fmt.Errorf("movie title %q is ambiguous, use movieId", record.MovieTitle)
+	}
+}
+
+// resolveHall finds the hall of a repertoire by hallId or else by name, within
+// the cinema of the repertoire when it has one, and sets both on the repertoire
+func (k *repertoireKind) resolveHall(repertoire *models.Repertoire) (models.Hall, error) {
+	if repertoire.HallID.IsZero() {
+		if repertoire.Hall == "" {
+			return models.Hall{}, errors.New("hallId or hall is required")
+		}
+
+		var ids []primitive.ObjectID
+		for _, hall := range k.hallNames[repertoire.Hall] {
+			if repertoire.CinemaID.IsZero() || hall.CinemaID == repertoire.CinemaID {
+				ids = append(ids, hall.ID)
+			}
+		}
+		switch len(ids) {
+		case 0:
+			return models.Hall{}, fmt.Errorf("hall %q does not exist", repertoire.Hall)
+		case 1:
+			repertoire.HallID = ids[0]
+		default:
+			return models.Hall{}, fmt.Errorf("hall %q is ambiguous, use hallId", repertoire.Hall)
+		}
+	}
+
+	hall, ok := k.halls[repertoire.HallID]
+	if !ok {
+		return models.Hall{}, fmt.Errorf("hall %s does not exist", repertoire.HallID.Hex())
+	}
+	repertoire.Hall = hall.Name
+	return hall, nil
+}
+
+func (k *repertoireKind) insert(ctx context.Context, store repository.Store, report *Report) error {
+	for _, parsed := range k.repertoires {
+		if _, err := store.AddRepertoire(ctx, parsed.repertoire); err != nil {
+			report.addError(parsed.line, err)
+			return err
+		}
+		report.Imported++
+	}
+	return nil
+}
+
+func parseRepertoire(r row) (*repertoireRecord, error) {
+	record := &repertoireRecord{}
+	if r.raw != nil {
+		if err := json.Unmarshal(r.raw, record); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return record, nil
+	}
+
+	if value := r.field("movieId"); value != "" {
+		movieID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid movieId %q", value)
+		}
+		record.MovieID = movieID
+	}
+	record.MovieTitle = r.field("movieTitle")
+	record.DateSt = r.field("date")
+	record.Time = r.field("time")
+	if value := r.field("hallId"); value != "" {
+		hallID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hallId %q", value)
+		}
+		record.HallID = hallID
+	}
+	record.Hall = r.field("hall")
+
+	if value := r.field("numOfTickets"); value != "" {
+		numOfTickets, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.New("numOfTickets must be a number")
+		}
+		record.NumOfTickets = numOfTickets
+	}
+
+	return record, nil
+}
+
+func repertoireRecordFields(repertoire models.Repertoire, movieTitle string) []string {
+	return []string{
+		repertoire.ID.Hex(),
+		repertoire.MovieID.Hex(),
+		movieTitle,
+		repertoire.Date.Format("2006-01-02"),
+		repertoire.Time,
+		repertoire.HallID.Hex(),
+		repertoire.Hall,
+		strconv.Itoa(repertoire.NumOfTickets),
+	}
+}
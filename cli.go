@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/tijanadmi/movieginmongoapi/bulk"
+	db "github.com/tijanadmi/movieginmongoapi/repository"
+)
+
+// runCommand runs a command line subcommand and returns the process exit code.
+// It reports false when args do not name a subcommand.
+func runCommand(ctx context.Context, store db.Store, tenants []string, args []string) (int, bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+
+	switch args[0] {
+	case "import":
+		return runImport(ctx, store, tenants, args[1:]), true
+	case "export":
+		return runExport(ctx, store, tenants, args[1:]), true
+	default:
+		return 0, false
+	}
+}
+
+// runImport imports movies, halls or repertoires from a CSV or NDJSON file:
+//
+//	movieginmongoapi import -kind movies -file season.csv [-format csv] [-dry-run] [-tenant acme]
+func runImport(ctx context.Context, store db.Store, tenants []string, args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	kind := flags.String("kind", "", "movies, halls or repertoires")
+	file := flags.String("file", "", "input file, - for stdin")
+	format := flags.String("format", "", "csv or ndjson (default from the file extension)")
+	dryRun := flags.Bool("dry-run", false, "only validate the rows")
+	tenant := flags.String("tenant", "", "tenant to import into, required with TENANTS")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *kind == "" || *file == "" {
+		flags.Usage()
+		return 2
+	}
+
+	ctx, err := tenantContext(ctx, tenants, *tenant)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if *format == "" {
+		*format = formatFromExtension(*file)
+	}
+
+	var input io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot open %s: %v\n", *file, err)
+			return 1
+		}
+		defer f.Close()
+		input = f
+	}
+
+	report, err := bulk.Import(ctx, store, *kind, *format, input, *dryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import failed: %v\n", err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot write report: %v\n", err)
+		return 1
+	}
+
+	if report.HasErrors() {
+		return 1
+	}
+	return 0
+}
+
+// runExport writes all movies, halls or repertoires as CSV or NDJSON:
+//
+//	movieginmongoapi export -kind halls [-format ndjson] [-file halls.ndjson] [-tenant acme]
+func runExport(ctx context.Context, store db.Store, tenants []string, args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	kind := flags.String("kind", "", "movies, halls or repertoires")
+	file := flags.String("file", "-", "output file, - for stdout")
+	format := flags.String("format", "", "csv or ndjson (default from the file extension, else csv)")
+	tenant := flags.String("tenant", "", "tenant to export from, required with TENANTS")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *kind == "" {
+		flags.Usage()
+		return 2
+	}
+
+	ctx, err := tenantContext(ctx, tenants, *tenant)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if *format == "" {
+		*format = formatFromExtension(*file)
+		if *format == "" {
+			*format = bulk.FormatCSV
+		}
+	}
+
+	var output io.Writer = os.Stdout
+	if *file != "-" {
+		f, err := os.Create(*file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot create %s: %v\n", *file, err)
+			return 1
+		}
+		defer f.Close()
+		output = f
+	}
+
+	if err := bulk.Export(ctx, store, *kind, *format, output); err != nil {
+		fmt.Fprintf(os.Stderr, "export failed: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// tenantContext scopes ctx to the tenant given with -tenant, which must be one
+// of the configured tenants when there are any
+func tenantContext(ctx context.Context, tenants []string, tenant string) (context.Context, error) {
+	if len(tenants) == 0 {
+		if tenant != "" {
+			return nil, errors.New("-tenant needs TENANTS to be configured")
+		}
+		return ctx, nil
+	}
+	if !slices.Contains(tenants, tenant) {
+		return nil, fmt.Errorf("-tenant must be one of %s", strings.Join(tenants, ", "))
+	}
+	return db.WithTenant(ctx, tenant), nil
+}
+
+// formatFromExtension guesses the bulk format from a file name
+func formatFromExtension(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return bulk.FormatCSV
+	case ".ndjson", ".jsonl":
+		return bulk.FormatNDJSON
+	default:
+		return ""
+	}
+}
@@ -38,10 +38,12 @@ type apiResponse struct {
 }
 
 type reservationRequest struct {
-	Username    string   `json:"username" binding:"required"`
+	Username    string   `json:"username"`
 	MovieID     string   `json:"movieId" binding:"required"`
 	Date        string   `json:"date" binding:"required"`
 	Time        string   `json:"time" binding:"required"`
-	Hall        string   `json:"hall" binding:"required"`
+	HallID      string   `json:"hallId" binding:"required_without=Hall"`
+	Hall        string   `json:"hall" binding:"required_without=HallID"`
 	ReservSeats []string `json:"reservSeats" binding:"required"`
+	AgeOverride bool     `json:"ageOverride"`
 }
\ No newline at end of file
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tijanadmi/movieginmongoapi/bulk"
+)
+
+// maxImportSize limits the size of an uploaded import file
+const maxImportSize = 10 << 20
+
+// bulkFormat returns the format from the format query parameter, or else from
+// the Content-Type of the request
+func bulkFormat(ctx *gin.Context) string {
+	if format := ctx.Query("format"); format != "" {
+		return format
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(ctx.GetHeader("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return bulk.FormatCSV
+	case "application/x-ndjson", "application/jsonl":
+		return bulk.FormatNDJSON
+	}
+	return ""
+}
+
+// importRecords godoc
+// @Security bearerAuth
+// @Summary Import movies, halls or repertoires
+// @Description Import records from a CSV or NDJSON request body (admin only). Every row is validated; unless dryRun is set, the records are inserted only if all rows are valid, in one transaction, so a row that cannot be inserted is reported and nothing is imported.
+// @ID importRecords
+// @Accept  text/csv
+// @Accept  application/x-ndjson
+// @Produce  json
+// @Param  kind path string true "movies, halls or repertoires"
+// @Param  format query string false "csv or ndjson (default from Content-Type)"
+// @Param  dryRun query bool false "Only validate the rows"
+// @Success 200 {object} bulk.Report
+// @Success 201 {object} bulk.Report
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 422 {object} bulk.Report
+// @Router /import/{kind} [post]
+func (server *Server) importRecords(ctx *gin.Context) {
+	dryRun := false
+	if value := ctx.Query("dryRun"); value != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: "invalid dryRun parameter"})
+			return
+		}
+	}
+
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize)
+	report, err := bulk.Import(ctx, server.store, ctx.Param("kind"), bulkFormat(ctx), body, dryRun)
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesError):
+			ctx.JSON(http.StatusRequestEntityTooLarge, apiErrorResponse{Error: fmt.Sprintf("import file must not exceed %d bytes", maxImportSize)})
+		case errors.Is(err, bulk.ErrUnknownKind), errors.Is(err, bulk.ErrUnknownFormat):
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	switch {
+	case report.HasErrors():
+		ctx.JSON(http.StatusUnprocessableEntity, report)
+	case dryRun:
+		ctx.JSON(http.StatusOK, report)
+	default:
+		ctx.JSON(http.StatusCreated, report)
+	}
+}
+
+// exportRecords godoc
+// @Security bearerAuth
+// @Summary Export movies, halls or repertoires
+// @Description Export all records as CSV or NDJSON (admin only), in the format accepted by the import
+// @ID exportRecords
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Param  kind path string true "movies, halls or repertoires"
+// @Param  format query string false "csv (default) or ndjson"
+// @Success 200 {string} string
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Router /export/{kind} [get]
+func (server *Server) exportRecords(ctx *gin.Context) {
+	kind := ctx.Param("kind")
+	format := ctx.DefaultQuery("format", bulk.FormatCSV)
+
+	contentType := "text/csv; charset=utf-8"
+	if format == bulk.FormatNDJSON {
+		contentType = "application/x-ndjson"
+	}
+
+	// render into a buffer first, so errors can still be reported as JSON
+	var buf bytes.Buffer
+	if err := bulk.Export(ctx, server.store, kind, format, &buf); err != nil {
+		if errors.Is(err, bulk.ErrUnknownKind) || errors.Is(err, bulk.ErrUnknownFormat) {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", kind+"."+format))
+	ctx.Data(http.StatusOK, contentType, buf.Bytes())
+}
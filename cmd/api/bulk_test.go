@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/tijanadmi/movieginmongoapi/db/mock"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/token"
+	"github.com/tijanadmi/movieginmongoapi/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestImportRecordsAPI(t *testing.T) {
+	admin := util.RandomOwner()
+	cinema := models.Cinema{ID: primitive.NewObjectID(), Name: "Beograd"}
+	validCSV := "cinemaId,name,rows,cols\n" + cinema.ID.Hex() + ",Sala 1,\"A, B\",\"1, 2\"\n"
+	invalidCSV := "cinemaId,name,rows,cols\n" + cinema.ID.Hex() + ",Sala 1,,\n"
+
+	testCases := []struct {
+		name          string
+		kind          string
+		query         string
+		contentType   string
+		body          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "Commit",
+			kind:        "halls",
+			contentType: "text/csv",
+			body:        validCSV,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListCinemas(gomock.Any()).Times(1).Return([]models.Cinema{cinema}, nil)
+				store.EXPECT().ListHalls(gomock.Any()).Times(1).Return([]models.Hall{}, nil)
+				store.EXPECT().InsertHall(gomock.Any(), gomock.Any()).Times(1).Return(&models.Hall{}, nil)
+				store.EXPECT().
+					WithTransaction(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"imported":1`)
+			},
+		},
+		{
+			name:  "DryRun",
+			kind:  "halls",
+			query: "?format=csv&dryRun=true",
+			body:  validCSV,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListCinemas(gomock.Any()).Times(1).Return([]models.Cinema{cinema}, nil)
+				store.EXPECT().ListHalls(gomock.Any()).Times(1).Return([]models.Hall{}, nil)
+				store.EXPECT().InsertHall(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"valid":1`)
+			},
+		},
+		{
+			name:        "InvalidRows",
+			kind:        "halls",
+			contentType: "text/csv",
+			body:        invalidCSV,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListCinemas(gomock.Any()).Times(1).Return([]models.Cinema{cinema}, nil)
+				store.EXPECT().ListHalls(gomock.Any()).Times(1).Return([]models.Hall{}, nil)
+				store.EXPECT().InsertHall(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"row":2`)
+			},
+		},
+		{
+			name:        "UnknownKind",
+			kind:        "users",
+			contentType: "text/csv",
+			body:        validCSV,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListHalls(gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "UnknownFormat",
+			kind:        "halls",
+			contentType: "application/xml",
+			body:        validCSV,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListHalls(gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "NotAdmin",
+			kind:        "halls",
+			contentType: "text/csv",
+			body:        validCSV,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.UserRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListHalls(gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := "/import/" + tc.kind + tc.query
+			request, err := http.NewRequest(http.MethodPost, url, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			if tc.contentType != "" {
+				request.Header.Set("Content-Type", tc.contentType)
+			}
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestExportRecordsAPI(t *testing.T) {
+	admin := util.RandomOwner()
+	halls := []models.Hall{randomHall()}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListHalls(gomock.Any()).Times(1).Return(halls, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/export/halls?format=ndjson", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "application/x-ndjson", recorder.Header().Get("Content-Type"))
+	require.Contains(t, recorder.Body.String(), halls[0].Name)
+}
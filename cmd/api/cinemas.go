@@ -0,0 +1,219 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/token"
+)
+
+// ListCinemas godoc
+// @Security bearerAuth
+// @Summary List cinemas
+// @Description Get all cinemas of the chain sorted by name
+// @ID ListCinemas
+// @Produce  json
+// @Success 200 {array} models.Cinema
+// @Failure 401 {object} apiErrorResponse
+// @Router /cinemas [get]
+func (server *Server) ListCinemas(ctx *gin.Context) {
+	cinemas, err := server.store.ListCinemas(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cinemas)
+}
+
+// GetCinema godoc
+// @Security bearerAuth
+// @Summary Get a cinema
+// @Description Get a cinema with its time zone and pricing
+// @ID GetCinema
+// @Produce  json
+// @Param  id path string true "cinema ID"
+// @Success 200 {object} models.Cinema
+// @Failure 401 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Router /cinemas/{id} [get]
+func (server *Server) GetCinema(ctx *gin.Context) {
+	cinema, err := server.store.GetCinema(ctx, ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrCinemaNotFound) {
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, cinema)
+}
+
+// AddCinema godoc
+// @Security bearerAuth
+// @Summary Add a cinema
+// @Description Add a new cinema with its time zone and pricing (admin only)
+// @ID AddCinema
+// @Accept  json
+// @Produce  json
+// @Param cinema body models.Cinema true "Create cinema"
+// @Success 201 {object} models.Cinema
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 409 {object} apiErrorResponse
+// @Router /cinemas [post]
+func (server *Server) AddCinema(ctx *gin.Context) {
+	var cinema models.Cinema
+	if err := ctx.ShouldBindJSON(&cinema); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := models.ValidateCinema(&cinema); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	created, err := server.store.AddCinema(ctx, &cinema)
+	if err != nil {
+		if errors.Is(err, repository.ErrCinemaNameTaken) {
+			ctx.JSON(http.StatusConflict, apiErrorResponse{Error: err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// UpdateCinema godoc
+// @Security bearerAuth
+// @Summary Update a cinema
+// @Description Update the name, address, time zone and pricing of a cinema (admins of the cinema)
+// @ID UpdateCinema
+// @Accept  json
+// @Produce  json
+// @Param  id path string true "cinema ID"
+// @Param cinema body models.Cinema true "Update cinema"
+// @Success 200 {object} models.Cinema
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Failure 409 {object} apiErrorResponse
+// @Router /cinemas/{id} [put]
+func (server *Server) UpdateCinema(ctx *gin.Context) {
+	var cinema models.Cinema
+	if err := ctx.ShouldBindJSON(&cinema); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+	if err := models.ValidateCinema(&cinema); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	updated, err := server.store.UpdateCinema(ctx, ctx.Param("id"), cinema)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCinemaNotFound):
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+		case errors.Is(err, repository.ErrCinemaNameTaken):
+			ctx.JSON(http.StatusConflict, apiErrorResponse{Error: err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// DeleteCinema godoc
+// @Security bearerAuth
+// @Summary Delete a cinema
+// @Description Delete a cinema without halls; its admins lose their role for it (admin only)
+// @ID DeleteCinema
+// @Produce  json
+// @Param  id path string true "cinema ID"
+// @Success 200 {object} apiResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Failure 409 {object} apiErrorResponse
+// @Router /cinemas/{id} [delete]
+func (server *Server) DeleteCinema(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	err := server.store.DeleteCinema(ctx, ctx.Param("id"), authPayload.Username)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCinemaNotFound):
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+		case errors.Is(err, repository.ErrCinemaHasHalls):
+			ctx.JSON(http.StatusConflict, apiErrorResponse{Error: err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apiResponse{Message: "cinema has been deleted"})
+}
+
+// AddCinemaAdmin godoc
+// @Security bearerAuth
+// @Summary Make a user admin of a cinema
+// @Description Grant a user the admin role for one cinema (admin only)
+// @ID AddCinemaAdmin
+// @Produce  json
+// @Param  id path string true "cinema ID"
+// @Param  username path string true "username"
+// @Success 200 {object} apiResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Router /cinemas/{id}/admins/{username} [put]
+func (server *Server) AddCinemaAdmin(ctx *gin.Context) {
+	server.setCinemaAdmin(ctx, true, "user is now an admin of the cinema")
+}
+
+// RemoveCinemaAdmin godoc
+// @Security bearerAuth
+// @Summary Revoke the admin role of a user for a cinema
+// @Description Revoke the admin role of a user for one cinema (admin only)
+// @ID RemoveCinemaAdmin
+// @Produce  json
+// @Param  id path string true "cinema ID"
+// @Param  username path string true "username"
+// @Success 200 {object} apiResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Router /cinemas/{id}/admins/{username} [delete]
+func (server *Server) RemoveCinemaAdmin(ctx *gin.Context) {
+	server.setCinemaAdmin(ctx, false, "user is no longer an admin of the cinema")
+}
+
+func (server *Server) setCinemaAdmin(ctx *gin.Context, admin bool, message string) {
+	err := server.store.SetCinemaAdmin(ctx, ctx.Param("id"), ctx.Param("username"), admin)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCinemaNotFound):
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: "user not found"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apiResponse{Message: message})
+}
@@ -0,0 +1,415 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/tijanadmi/movieginmongoapi/db/mock"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAddCinemaAPI(t *testing.T) {
+	username := util.RandomOwner()
+	cinema := randomCinema()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name":     cinema.Name,
+				"timeZone": cinema.TimeZone,
+				"pricing":  cinema.Pricing,
+			},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := &models.Cinema{
+					Name:     cinema.Name,
+					TimeZone: cinema.TimeZone,
+					Pricing:  cinema.Pricing,
+				}
+				store.EXPECT().
+					AddCinema(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(&cinema, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				requireBodyMatchCinema(t, recorder.Body, cinema)
+			},
+		},
+		{
+			name: "NotAdmin",
+			body: gin.H{
+				"name":     cinema.Name,
+				"timeZone": cinema.TimeZone,
+			},
+			role: util.UserRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddCinema(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownTimeZone",
+			body: gin.H{
+				"name":     cinema.Name,
+				"timeZone": "Europe/Atlantis",
+			},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddCinema(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NameTaken",
+			body: gin.H{
+				"name":     cinema.Name,
+				"timeZone": cinema.TimeZone,
+			},
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddCinema(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repository.ErrCinemaNameTaken)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/cinemas", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUpdateCinemaAPI(t *testing.T) {
+	username := util.RandomOwner()
+	cinema := randomCinema()
+	body := gin.H{
+		"name":     cinema.Name,
+		"timeZone": cinema.TimeZone,
+		"pricing":  cinema.Pricing,
+	}
+
+	testCases := []struct {
+		name          string
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Admin",
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateCinema(gomock.Any(), gomock.Eq(cinema.ID.Hex()), gomock.Any()).
+					Times(1).
+					Return(&cinema, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchCinema(t, recorder.Body, cinema)
+			},
+		},
+		{
+			name: "CinemaAdmin",
+			role: util.UserRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				user := &models.User{
+					Username: username,
+					Roles:    []string{util.UserRole, util.CinemaRole(util.CinemaAdminRole, cinema.ID.Hex())},
+				}
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					UpdateCinema(gomock.Any(), gomock.Eq(cinema.ID.Hex()), gomock.Any()).
+					Times(1).
+					Return(&cinema, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "AdminOfOtherCinema",
+			role: util.UserRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				user := &models.User{
+					Username: username,
+					Roles:    []string{util.UserRole, util.CinemaRole(util.CinemaAdminRole, primitive.NewObjectID().Hex())},
+				}
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					UpdateCinema(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateCinema(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repository.ErrCinemaNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/cinemas/%s", cinema.ID.Hex())
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDeleteCinemaAPI(t *testing.T) {
+	username := util.RandomOwner()
+	cinema := randomCinema()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteCinema(gomock.Any(), gomock.Eq(cinema.ID.Hex()), gomock.Eq(username)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "HasHalls",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteCinema(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repository.ErrCinemaHasHalls)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteCinema(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repository.ErrCinemaNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/cinemas/%s", cinema.ID.Hex())
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, username, util.AdminRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestSetCinemaAdminAPI(t *testing.T) {
+	admin := util.RandomOwner()
+	username := util.RandomOwner()
+	cinema := randomCinema()
+
+	testCases := []struct {
+		name          string
+		method        string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Grant",
+			method: http.MethodPut,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SetCinemaAdmin(gomock.Any(), gomock.Eq(cinema.ID.Hex()), gomock.Eq(username), gomock.Eq(true)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Revoke",
+			method: http.MethodDelete,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SetCinemaAdmin(gomock.Any(), gomock.Eq(cinema.ID.Hex()), gomock.Eq(username), gomock.Eq(false)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "UserNotFound",
+			method: http.MethodPut,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SetCinemaAdmin(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(repository.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchErrorResponse(t, recorder.Body, apiErrorResponse{Error: "user not found"})
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/cinemas/%s/admins/%s", cinema.ID.Hex(), username)
+			request, err := http.NewRequest(tc.method, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func randomCinema() models.Cinema {
+	return models.Cinema{
+		ID:       primitive.NewObjectID(),
+		Name:     util.RandomString(8),
+		TimeZone: "Europe/Belgrade",
+		Pricing: models.Pricing{
+			Currency: "RSD",
+			Prices:   map[string]int64{models.SeatStandard: 50000, models.SeatVIP: 80000},
+		},
+	}
+}
+
+func requireBodyMatchCinema(t *testing.T, body *bytes.Buffer, cinema models.Cinema) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotCinema models.Cinema
+	err = json.Unmarshal(data, &gotCinema)
+	require.NoError(t, err)
+	require.Equal(t, cinema, gotCinema)
+}
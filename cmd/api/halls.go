@@ -89,7 +89,7 @@ func (server *Server) searchHall(ctx *gin.Context) {
 // InsertHall godoc
 // @Security bearerAuth
 // @Summary Insert new hall
-// @Description Insert new hall
+// @Description Insert new hall. The seat map is given as seats or generated from rows and cols.
 // @ID InsertHall
 // @Accept  json
 // @Produce  json
@@ -97,6 +97,8 @@ func (server *Server) searchHall(ctx *gin.Context) {
 // @Success 201 {array} models.Hall
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 409 {object} apiErrorResponse
 // @Router /halls [post]
 func (server *Server) InsertHall(ctx *gin.Context) {
 	var hall *models.Hall
@@ -104,9 +106,21 @@ func (server *Server) InsertHall(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
 		return
 	}
+	if err := models.ValidateHall(hall); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
 
 	hall, err := server.store.InsertHall(ctx, hall)
 	if err != nil {
+		if errors.Is(err, repository.ErrHallNameTaken) {
+			ctx.JSON(http.StatusConflict, apiErrorResponse{Error: err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrCinemaNotFound) {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
 		return
 	}
@@ -126,6 +140,8 @@ func (server *Server) InsertHall(ctx *gin.Context) {
 // @Success 200 {array} models.Hall
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 409 {object} apiErrorResponse
 // @Router /halls/{id} [put]
 func (server *Server) UpdateHall(ctx *gin.Context) {
 	id := ctx.Param("id")
@@ -134,9 +150,21 @@ func (server *Server) UpdateHall(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
 		return
 	}
+	if err := models.ValidateHall(hall); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
 
 	modifiedHall, err := server.store.UpdateHall(ctx, id, *hall)
 	if err != nil {
+		if errors.Is(err, repository.ErrHallNameTaken) {
+			ctx.JSON(http.StatusConflict, apiErrorResponse{Error: err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrCinemaNotFound) {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
 		return
 	}
@@ -147,23 +175,50 @@ func (server *Server) UpdateHall(ctx *gin.Context) {
 // DeleteHall godoc
 // @Security bearerAuth
 // @Summary Delete a single hall
-// @Description Delete a single hall
+// @Description Delete a single hall. If repertoires are scheduled in the hall, it is only deleted with cascade, which cancels them and their reservations and notifies the affected users.
 // @ID DeleteHall
 // @Accept  json
 // @Produce  json
 // @Param  id path string true "Hall ID"
+// @Param  cascade query bool false "Also cancel the dependents"
 // @Success 200 {array} apiResponse
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Failure 409 {object} dependentsErrorResponse
 // @Router /halls/{id} [delete]
 func (server *Server) DeleteHall(ctx *gin.Context) {
-	id := ctx.Param("id")
+	server.deleteRecord(ctx, ctx.Param("id"), server.store.DeleteHall, repository.ErrHallNotFound, "Hall has been deleted")
+}
 
-	err := server.store.DeleteHall(ctx, id)
+// RestoreHall godoc
+// @Security bearerAuth
+// @Summary Restore a deleted hall
+// @Description Restore a soft-deleted hall (admin only)
+// @ID RestoreHall
+// @Produce  json
+// @Param  id path string true "Hall ID"
+// @Success 200 {object} apiResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Failure 409 {object} apiErrorResponse
+// @Router /halls/{id}/restore [put]
+func (server *Server) RestoreHall(ctx *gin.Context) {
+	err := server.store.RestoreHall(ctx, ctx.Param("id"))
 	if err != nil {
+		if errors.Is(err, repository.ErrHallNotFound) {
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrHallNameTaken) {
+			ctx.JSON(http.StatusConflict, apiErrorResponse{Error: err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, apiResponse{Message: "Hall has been deleted"})
+	ctx.JSON(http.StatusOK, apiResponse{Message: "Hall has been restored"})
 }
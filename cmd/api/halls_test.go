@@ -307,7 +307,7 @@ func TestSearchHallsAPI(t *testing.T) {
 
 func TestInsertHallAPI(t *testing.T) {
 	username := util.RandomOwner()
-	role := util.UserRole
+	role := util.AdminRole
 	hall := randomHall()
 
 	testCases := []struct {
@@ -320,18 +320,20 @@ func TestInsertHallAPI(t *testing.T) {
 		{
 			name: "OK",
 			body: gin.H{
-				"name": hall.Name,
-				"rows": hall.Rows,
-				"cols": hall.Cols,
+				"cinemaId": hall.CinemaID,
+				"name":     hall.Name,
+				"rows":     hall.Rows,
+				"cols":     hall.Cols,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				arg := &models.Hall{
-					Name: hall.Name,
-					Rows: hall.Rows,
-					Cols: hall.Cols,
+					CinemaID: hall.CinemaID,
+					Name:     hall.Name,
+					Rows:     hall.Rows,
+					Cols:     hall.Cols,
 				}
 				store.EXPECT().
 					InsertHall(gomock.Any(), gomock.Eq(arg)).
@@ -343,12 +345,70 @@ func TestInsertHallAPI(t *testing.T) {
 				requireBodyMatchHall(t, recorder.Body, hall)
 			},
 		},
+		{
+			name: "CinemaAdmin",
+			body: gin.H{
+				"cinemaId": hall.CinemaID,
+				"name":     hall.Name,
+				"rows":     hall.Rows,
+				"cols":     hall.Cols,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				user := &models.User{
+					Username: username,
+					Roles:    []string{util.UserRole, util.CinemaRole(util.CinemaAdminRole, hall.CinemaID.Hex())},
+				}
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					InsertHall(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(&hall, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "AdminOfOtherCinema",
+			body: gin.H{
+				"cinemaId": hall.CinemaID,
+				"name":     hall.Name,
+				"rows":     hall.Rows,
+				"cols":     hall.Cols,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				user := &models.User{
+					Username: username,
+					Roles:    []string{util.UserRole, util.CinemaRole(util.CinemaAdminRole, primitive.NewObjectID().Hex())},
+				}
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					InsertHall(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name: "NoAuthorization",
 			body: gin.H{
-				"name": hall.Name,
-				"rows": hall.Rows,
-				"cols": hall.Cols,
+				"cinemaId": hall.CinemaID,
+				"name":     hall.Name,
+				"rows":     hall.Rows,
+				"cols":     hall.Cols,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 			},
@@ -361,12 +421,78 @@ func TestInsertHallAPI(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name: "InvalidLayout",
+			body: gin.H{
+				"cinemaId": hall.CinemaID,
+				"name":     hall.Name,
+				"seats": []models.Seat{
+					{Row: 0, Col: 0, Label: "A1", Category: models.SeatStandard},
+					{Row: 0, Col: 1, Label: "A1", Category: models.SeatVIP},
+				},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					InsertHall(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrorResponse(t, recorder.Body, apiErrorResponse{Error: "duplicate seat A1"})
+			},
+		},
+		{
+			name: "NameTaken",
+			body: gin.H{
+				"cinemaId": hall.CinemaID,
+				"name":     hall.Name,
+				"rows":     hall.Rows,
+				"cols":     hall.Cols,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					InsertHall(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repository.ErrHallNameTaken)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "CinemaNotFound",
+			body: gin.H{
+				"cinemaId": hall.CinemaID,
+				"name":     hall.Name,
+				"rows":     hall.Rows,
+				"cols":     hall.Cols,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					InsertHall(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repository.ErrCinemaNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "InternalError",
 			body: gin.H{
-				"name": hall.Name,
-				"rows": hall.Rows,
-				"cols": hall.Cols,
+				"cinemaId": hall.CinemaID,
+				"name":     hall.Name,
+				"rows":     hall.Rows,
+				"cols":     hall.Cols,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
@@ -384,9 +510,10 @@ func TestInsertHallAPI(t *testing.T) {
 		{
 			name: "InvalidRows",
 			body: gin.H{
-				"name": hall.Name,
-				"rows": "invalid",
-				"cols": hall.Cols,
+				"cinemaId": hall.CinemaID,
+				"name":     hall.Name,
+				"rows":     "invalid",
+				"cols":     hall.Cols,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
@@ -432,7 +559,7 @@ func TestInsertHallAPI(t *testing.T) {
 
 func TestUpdateHallAPI(t *testing.T) {
 	username := util.RandomOwner()
-	role := util.UserRole
+	role := util.AdminRole
 	hall := randomHall()
 
 	testCases := []struct {
@@ -447,20 +574,22 @@ func TestUpdateHallAPI(t *testing.T) {
 			name:   "OK",
 			hallID: hall.ID.Hex(),
 			body: gin.H{
-				"id":   hall.ID.Hex(),
-				"name": hall.Name,
-				"rows": hall.Rows,
-				"cols": hall.Cols,
+				"id":       hall.ID.Hex(),
+				"cinemaId": hall.CinemaID,
+				"name":     hall.Name,
+				"rows":     hall.Rows,
+				"cols":     hall.Cols,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				arg := models.Hall{
-					ID:   hall.ID,
-					Name: hall.Name,
-					Rows: hall.Rows,
-					Cols: hall.Cols,
+					ID:       hall.ID,
+					CinemaID: hall.CinemaID,
+					Name:     hall.Name,
+					Rows:     hall.Rows,
+					Cols:     hall.Cols,
 				}
 				store.EXPECT().
 					UpdateHall(gomock.Any(), gomock.Eq(hall.ID.Hex()), gomock.Eq(arg)).
@@ -472,14 +601,82 @@ func TestUpdateHallAPI(t *testing.T) {
 				requireBodyMatchHall(t, recorder.Body, hall)
 			},
 		},
+		{
+			name:   "CinemaAdmin",
+			hallID: hall.ID.Hex(),
+			body: gin.H{
+				"cinemaId": hall.CinemaID,
+				"name":     hall.Name,
+				"rows":     hall.Rows,
+				"cols":     hall.Cols,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				user := &models.User{
+					Username: username,
+					Roles:    []string{util.UserRole, util.CinemaRole(util.CinemaAdminRole, hall.CinemaID.Hex())},
+				}
+				store.EXPECT().
+					GetHallById(gomock.Any(), gomock.Eq(hall.ID.Hex())).
+					Times(1).
+					Return(&hall, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					UpdateHall(gomock.Any(), gomock.Eq(hall.ID.Hex()), gomock.Any()).
+					Times(1).
+					Return(hall, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "MoveToOtherCinema",
+			hallID: hall.ID.Hex(),
+			body: gin.H{
+				"cinemaId": primitive.NewObjectID(),
+				"name":     hall.Name,
+				"rows":     hall.Rows,
+				"cols":     hall.Cols,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				user := &models.User{
+					Username: username,
+					Roles:    []string{util.UserRole, util.CinemaRole(util.CinemaAdminRole, hall.CinemaID.Hex())},
+				}
+				store.EXPECT().
+					GetHallById(gomock.Any(), gomock.Eq(hall.ID.Hex())).
+					Times(1).
+					Return(&hall, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					UpdateHall(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name:   "NoAuthorization",
 			hallID: hall.ID.Hex(),
 			body: gin.H{
-				"id":   hall.ID.Hex(),
-				"name": hall.Name,
-				"rows": hall.Rows,
-				"cols": hall.Cols,
+				"id":       hall.ID.Hex(),
+				"cinemaId": hall.CinemaID,
+				"name":     hall.Name,
+				"rows":     hall.Rows,
+				"cols":     hall.Cols,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				// Do not set up authorization
@@ -497,20 +694,22 @@ func TestUpdateHallAPI(t *testing.T) {
 			name:   "InternalError",
 			hallID: hall.ID.Hex(),
 			body: gin.H{
-				"id":   hall.ID.Hex(),
-				"name": hall.Name,
-				"rows": hall.Rows,
-				"cols": hall.Cols,
+				"id":       hall.ID.Hex(),
+				"cinemaId": hall.CinemaID,
+				"name":     hall.Name,
+				"rows":     hall.Rows,
+				"cols":     hall.Cols,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				arg := models.Hall{
-					ID:   hall.ID,
-					Name: hall.Name,
-					Rows: hall.Rows,
-					Cols: hall.Cols,
+					ID:       hall.ID,
+					CinemaID: hall.CinemaID,
+					Name:     hall.Name,
+					Rows:     hall.Rows,
+					Cols:     hall.Cols,
 				}
 				store.EXPECT().
 					UpdateHall(gomock.Any(), gomock.Eq(hall.ID.Hex()), gomock.Eq(arg)).
@@ -525,9 +724,10 @@ func TestUpdateHallAPI(t *testing.T) {
 			name:   "InvalidRows",
 			hallID: hall.ID.Hex(),
 			body: gin.H{
-				"name": hall.Name,
-				"rows": "invalid",
-				"cols": hall.Cols,
+				"cinemaId": hall.CinemaID,
+				"name":     hall.Name,
+				"rows":     "invalid",
+				"cols":     hall.Cols,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
@@ -573,7 +773,7 @@ func TestUpdateHallAPI(t *testing.T) {
 
 func TestDeleteHallAPI(t *testing.T) {
 	username := util.RandomOwner()
-	role := util.UserRole
+	role := util.AdminRole
 	hall := randomHall()
 	hallID := hall.ID.Hex()
 	//hallID := "668ef39a1b5b57783fa8b523" // primer ID-a
@@ -593,15 +793,38 @@ func TestDeleteHallAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					DeleteHall(gomock.Any(), gomock.Eq(hallID)).
+					DeleteHall(gomock.Any(), gomock.Eq(hallID), gomock.Eq(username), gomock.Eq(false)).
 					Times(1).
-					Return(nil)
+					Return(repository.Dependents{}, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 				requireBodyMatchResponse(t, recorder.Body, apiResponse{Message: "Hall has been deleted"})
 			},
 		},
+		{
+			name:   "NotCinemaAdmin",
+			hallID: hallID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHallById(gomock.Any(), gomock.Eq(hallID)).
+					Times(1).
+					Return(&hall, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq(username)).
+					Times(1).
+					Return(&models.User{Username: username, Roles: []string{util.UserRole}}, nil)
+				store.EXPECT().
+					DeleteHall(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name:   "NoAuthorization",
 			hallID: hall.ID.Hex(),
@@ -610,13 +833,29 @@ func TestDeleteHallAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					DeleteHall(gomock.Any(), gomock.Any()).
+					DeleteHall(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name:   "NotFound",
+			hallID: hallID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteHall(gomock.Any(), gomock.Eq(hallID), gomock.Eq(username), gomock.Eq(false)).
+					Times(1).
+					Return(repository.Dependents{}, repository.ErrHallNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
 		{
 			name:   "InternalError",
 			hallID: hallID,
@@ -625,9 +864,9 @@ func TestDeleteHallAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					DeleteHall(gomock.Any(), gomock.Eq(hallID)).
+					DeleteHall(gomock.Any(), gomock.Eq(hallID), gomock.Eq(username), gomock.Eq(false)).
 					Times(1).
-					Return(sql.ErrConnDone)
+					Return(repository.Dependents{}, sql.ErrConnDone)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -663,8 +902,9 @@ func TestDeleteHallAPI(t *testing.T) {
 func randomHall() models.Hall {
 	objectID := primitive.NewObjectID()
 	return models.Hall{
-		ID:   objectID,
-		Name: util.RandomHall(),
+		ID:       objectID,
+		CinemaID: primitive.NewObjectID(),
+		Name:     util.RandomHall(),
 		Rows: []string{
 			"A",
 			"B",
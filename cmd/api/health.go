@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+)
+
+// readinessTimeout bounds all readiness checks of one /readyz request
+const readinessTimeout = 2 * time.Second
+
+// ReadinessCheck reports why a dependency of the server is not ready, or nil
+type ReadinessCheck func(ctx context.Context) error
+
+type readinessCheck struct {
+	name  string
+	check ReadinessCheck
+}
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// AddReadinessCheck adds a check that has to pass for /readyz to report ready
+func (server *Server) AddReadinessCheck(name string, check ReadinessCheck) {
+	server.readinessChecks = append(server.readinessChecks, readinessCheck{name: name, check: check})
+}
+
+// healthz godoc
+// @Summary Liveness probe
+// @Description Report that the process is alive
+// @ID healthz
+// @Produce  json
+// @Success 200 {object} healthResponse
+// @Router /healthz [get]
+func (server *Server) healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// readyz godoc
+// @Summary Readiness probe
+// @Description Report whether MongoDB can be reached, the migrations are applied and the background workers are running
+// @ID readyz
+// @Produce  json
+// @Success 200 {object} healthResponse
+// @Failure 503 {object} healthResponse
+// @Router /readyz [get]
+func (server *Server) readyz(ctx *gin.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	rsp := healthResponse{Status: "ready", Checks: make(map[string]string)}
+	status := http.StatusOK
+	for _, c := range server.readinessChecks {
+		if err := c.check(checkCtx); err != nil {
+			rsp.Checks[c.name] = err.Error()
+			rsp.Status = "not ready"
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		rsp.Checks[c.name] = "ok"
+	}
+
+	ctx.JSON(status, rsp)
+}
+
+// checkMongo pings the database
+func (server *Server) checkMongo(ctx context.Context) error {
+	return server.store.Ping(ctx)
+}
+
+// checkMigrations reports the migrations not applied to the database of each tenant
+func (server *Server) checkMigrations(ctx context.Context) error {
+	tenants := server.config.Tenants
+	if len(tenants) == 0 {
+		tenants = []string{""}
+	}
+
+	for _, tenant := range tenants {
+		pending, err := server.store.PendingMigrations(repository.WithTenant(ctx, tenant))
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			if tenant != "" {
+				return fmt.Errorf("tenant %s has pending migrations: %s", tenant, strings.Join(pending, ", "))
+			}
+			return fmt.Errorf("pending migrations: %s", strings.Join(pending, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/tijanadmi/movieginmongoapi/db/mock"
+)
+
+func TestHealthzAPI(t *testing.T) {
+	server := newTestServer(t, nil)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/healthz", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	requireBodyMatchHealth(t, recorder, healthResponse{Status: "ok"})
+}
+
+func TestReadyzAPI(t *testing.T) {
+	testCases := []struct {
+		name          string
+		tenants       []string
+		workersError  error
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Ready",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().PendingMigrations(gomock.Any()).Times(1).Return([]string{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchHealth(t, recorder, healthResponse{
+					Status: "ready",
+					Checks: map[string]string{"mongo": "ok", "migrations": "ok", "workers": "ok"},
+				})
+			},
+		},
+		{
+			name: "MongoDown",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(context.DeadlineExceeded)
+				store.EXPECT().PendingMigrations(gomock.Any()).Times(1).Return(nil, context.DeadlineExceeded)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+				requireBodyMatchHealth(t, recorder, healthResponse{
+					Status: "not ready",
+					Checks: map[string]string{
+						"mongo":      context.DeadlineExceeded.Error(),
+						"migrations": context.DeadlineExceeded.Error(),
+						"workers":    "ok",
+					},
+				})
+			},
+		},
+		{
+			name:    "PendingMigrations",
+			tenants: []string{"acme", "globex"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().PendingMigrations(tenantContext("acme")).Times(1).Return([]string{}, nil)
+				store.EXPECT().PendingMigrations(tenantContext("globex")).Times(1).Return([]string{"0004_cinemas"}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+				requireBodyMatchHealth(t, recorder, healthResponse{
+					Status: "not ready",
+					Checks: map[string]string{
+						"mongo":      "ok",
+						"migrations": "tenant globex has pending migrations: 0004_cinemas",
+						"workers":    "ok",
+					},
+				})
+			},
+		},
+		{
+			name:         "WorkersStopped",
+			workersError: errors.New("purger is not running"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().PendingMigrations(gomock.Any()).Times(1).Return([]string{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.Tenants = tc.tenants
+			server.AddReadinessCheck("workers", func(context.Context) error {
+				return tc.workersError
+			})
+			recorder := httptest.NewRecorder()
+
+			// probes need neither a token nor a tenant
+			request, err := http.NewRequest(http.MethodGet, "/readyz", nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestMetricsAPI(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	request, err := http.NewRequest(http.MethodGet, "/healthz", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(httptest.NewRecorder(), request)
+
+	recorder := httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `movieapi_http_requests_total{method="GET",route="/healthz",status="200"}`)
+	require.Contains(t, recorder.Body.String(), "movieapi_http_request_duration_seconds_bucket")
+}
+
+func requireBodyMatchHealth(t *testing.T, recorder *httptest.ResponseRecorder, expected healthResponse) {
+	var got healthResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &got)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/token"
+)
+
+// dependentsErrorResponse is returned with 409 when a record still has dependents
+type dependentsErrorResponse struct {
+	Error      string                `json:"error"`
+	Dependents repository.Dependents `json:"dependents"`
+}
+
+// deleteFunc is the signature of the store methods deleting a record with dependents
+type deleteFunc func(ctx context.Context, id string, deletedBy string, cascade bool) (repository.Dependents, error)
+
+// deleteRecord deletes the record with the given id. Unless the cascade query
+// parameter is true, a record with dependents is not deleted and the
+// dependents are listed in a 409 response.
+func (server *Server) deleteRecord(ctx *gin.Context, id string, del deleteFunc, notFound error, message string) {
+	cascade := false
+	if value := ctx.Query("cascade"); value != "" {
+		var err error
+		cascade, err = strconv.ParseBool(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: "invalid cascade parameter"})
+			return
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	dependents, err := del(ctx, id, authPayload.Username, cascade)
+	if err != nil {
+		switch {
+		case errors.Is(err, notFound):
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+		case errors.Is(err, repository.ErrHasDependents):
+			ctx.JSON(http.StatusConflict, dependentsErrorResponse{Error: err.Error(), Dependents: dependents})
+		default:
+			ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	if !dependents.Empty() {
+		message = fmt.Sprintf("%s, %d repertoires and %d reservations cancelled",
+			message, len(dependents.Repertoires), len(dependents.Reservations))
+	}
+	ctx.JSON(http.StatusOK, apiResponse{Message: message})
+}
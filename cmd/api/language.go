@@ -0,0 +1,50 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"golang.org/x/text/language"
+)
+
+// languageMatcher picks the best supported content language for a request
+var languageMatcher = newLanguageMatcher()
+
+func newLanguageMatcher() language.Matcher {
+	tags := make([]language.Tag, len(models.SupportedLanguages))
+	for i, lang := range models.SupportedLanguages {
+		tags[i] = language.MustParse(lang)
+	}
+	return language.NewMatcher(tags)
+}
+
+// negotiateLanguage returns the content language for the request based on its
+// Accept-Language header, or the default language when nothing matches.
+func negotiateLanguage(ctx *gin.Context) string {
+	ctx.Header("Vary", "Accept-Language")
+
+	lang := models.DefaultLanguage
+	if header := ctx.GetHeader("Accept-Language"); header != "" {
+		tags, _, err := language.ParseAcceptLanguage(header)
+		if err == nil && len(tags) > 0 {
+			_, index, confidence := languageMatcher.Match(tags...)
+			if confidence != language.No {
+				lang = models.SupportedLanguages[index]
+			}
+		}
+	}
+
+	ctx.Header("Content-Language", lang)
+	return lang
+}
+
+// validateTranslations checks that movie translations use supported languages
+func validateTranslations(translations map[string]models.MovieTranslation) error {
+	for lang := range translations {
+		if !models.IsSupportedLanguage(lang) {
+			return fmt.Errorf("unsupported translation language %q", lang)
+		}
+	}
+	return nil
+}
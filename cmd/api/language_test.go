@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/tijanadmi/movieginmongoapi/models"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	testCases := []struct {
+		name           string
+		acceptLanguage string
+		lang           string
+	}{
+		{
+			name: "NoHeader",
+			lang: models.DefaultLanguage,
+		},
+		{
+			name:           "English",
+			acceptLanguage: "en-US,en;q=0.9",
+			lang:           models.LanguageEnglish,
+		},
+		{
+			name:           "SerbianLatin",
+			acceptLanguage: "sr-Latn-RS",
+			lang:           models.LanguageSerbianLatin,
+		},
+		{
+			name:           "SerbianCyrillic",
+			acceptLanguage: "sr-Cyrl",
+			lang:           models.LanguageSerbianCyrillic,
+		},
+		{
+			name:           "Quality",
+			acceptLanguage: "en;q=0.5, sr-Cyrl;q=0.8",
+			lang:           models.LanguageSerbianCyrillic,
+		},
+		{
+			name:           "Unsupported",
+			acceptLanguage: "ja",
+			lang:           models.DefaultLanguage,
+		},
+		{
+			name:           "Malformed",
+			acceptLanguage: "=;;",
+			lang:           models.DefaultLanguage,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/movies", nil)
+			if tc.acceptLanguage != "" {
+				ctx.Request.Header.Set("Accept-Language", tc.acceptLanguage)
+			}
+
+			require.Equal(t, tc.lang, negotiateLanguage(ctx))
+			require.Equal(t, tc.lang, recorder.Header().Get("Content-Language"))
+		})
+	}
+}
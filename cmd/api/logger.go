@@ -0,0 +1,121 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	maxRequestIDLen = 128
+)
+
+// requestIDMiddleware gives every request an ID, the one of the caller's
+// X-Request-ID header when it has a usable one, and returns it in the same
+// header. The request context carries a logger with the ID and the trace ID,
+// so every log line of the request, down to the store, can be correlated.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		ctx.Header(requestIDHeader, requestID)
+
+		logContext := log.Logger.With().Str("request_id", requestID)
+		if span := trace.SpanContextFromContext(ctx.Request.Context()); span.IsValid() {
+			logContext = logContext.Str("trace_id", span.TraceID().String())
+		}
+		logger := logContext.Logger()
+
+		ctx.Request = ctx.Request.WithContext(logger.WithContext(ctx.Request.Context()))
+		ctx.Next()
+	}
+}
+
+// addLogField adds a field to the logger of the request context for the
+// handlers and middleware that follow
+func addLogField(ctx *gin.Context, key, value string) {
+	logger := zerolog.Ctx(ctx.Request.Context()).With().Str(key, value).Logger()
+	ctx.Request = ctx.Request.WithContext(logger.WithContext(ctx.Request.Context()))
+}
+
+// validRequestID accepts short IDs of printable ASCII, anything else could
+// forge log lines
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// accessLogMiddleware logs every request once it is done, except the paths to
+// skip. Server errors are logged as errors and client errors as warnings.
+func accessLogMiddleware(skipPaths ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, path := range skipPaths {
+		skip[path] = true
+	}
+
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		path := ctx.Request.URL.Path
+		ctx.Next()
+
+		if skip[path] {
+			return
+		}
+
+		status := ctx.Writer.Status()
+		// the logger of the request context has the fields added by the
+		// middleware further down, like the tenant
+		logger := zerolog.Ctx(ctx.Request.Context())
+		var event *zerolog.Event
+		switch {
+		case status >= http.StatusInternalServerError:
+			event = logger.Error()
+		case status >= http.StatusBadRequest:
+			event = logger.Warn()
+		default:
+			event = logger.Info()
+		}
+		if len(ctx.Errors) > 0 {
+			event = event.Str("errors", ctx.Errors.String())
+		}
+
+		event.
+			Str("method", ctx.Request.Method).
+			Str("path", path).
+			Str("route", ctx.FullPath()).
+			Int("status", status).
+			Int("size", ctx.Writer.Size()).
+			Dur("latency", time.Since(start)).
+			Str("client_ip", ctx.ClientIP()).
+			Msg("request")
+	}
+}
+
+// recoveryMiddleware turns a panic into 500 and logs it with the stack to the
+// logger of the request
+func recoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(ctx *gin.Context, recovered any) {
+		zerolog.Ctx(ctx.Request.Context()).Error().
+			Interface("panic", recovered).
+			Bytes("stack", debug.Stack()).
+			Msg("request panicked")
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
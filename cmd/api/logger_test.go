@@ -0,0 +1,148 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/tijanadmi/movieginmongoapi/db/mock"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/storage"
+	"github.com/tijanadmi/movieginmongoapi/util"
+)
+
+func TestRequestIDAPI(t *testing.T) {
+	testCases := []struct {
+		name          string
+		requestID     string
+		checkResponse func(requestID string)
+	}{
+		{
+			name:      "FromCaller",
+			requestID: "req-42",
+			checkResponse: func(requestID string) {
+				require.Equal(t, "req-42", requestID)
+			},
+		},
+		{
+			name: "Generated",
+			checkResponse: func(requestID string) {
+				_, err := uuid.Parse(requestID)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:      "Invalid",
+			requestID: "forged\tline",
+			checkResponse: func(requestID string) {
+				_, err := uuid.Parse(requestID)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:      "TooLong",
+			requestID: strings.Repeat("a", maxRequestIDLen+1),
+			checkResponse: func(requestID string) {
+				_, err := uuid.Parse(requestID)
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/healthz", nil)
+			require.NoError(t, err)
+			if tc.requestID != "" {
+				request.Header.Set(requestIDHeader, tc.requestID)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder.Header().Get(requestIDHeader))
+		})
+	}
+}
+
+func TestRequestLoggingAPI(t *testing.T) {
+	var output bytes.Buffer
+	logger := log.Logger
+	log.Logger = zerolog.New(&output)
+	defer func() { log.Logger = logger }()
+
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListHalls(gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context) ([]models.Hall, error) {
+			zerolog.Ctx(ctx).Error().Msg("from the store")
+			return []models.Hall{}, nil
+		})
+
+	config := testConfig()
+	config.Tenants = []string{"acme"}
+	posters, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	server, err := NewServer(config, store, posters)
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodGet, "/halls", nil)
+	require.NoError(t, err)
+	request.Header.Set(config.TenantHeader, "acme")
+	request.Header.Set(requestIDHeader, "req-42")
+	accessToken, _, err := server.tokenMaker.CreateToken(username, util.UserRole, "acme", time.Minute)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+	server.router.ServeHTTP(httptest.NewRecorder(), request)
+
+	// probes are not logged
+	probe, err := http.NewRequest(http.MethodGet, "/healthz", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(httptest.NewRecorder(), probe)
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(&output)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 2)
+
+	// the line of the store and the access log belong to the same request
+	for _, line := range lines {
+		require.Equal(t, "req-42", line["request_id"])
+		require.Equal(t, "acme", line["tenant"])
+		require.Equal(t, username, line["username"])
+	}
+	require.Equal(t, "from the store", lines[0]["message"])
+
+	access := lines[1]
+	require.Equal(t, "request", access["message"])
+	require.Equal(t, "info", access["level"])
+	require.Equal(t, http.MethodGet, access["method"])
+	require.Equal(t, "/halls", access["route"])
+	require.Equal(t, float64(http.StatusOK), access["status"])
+}
@@ -8,16 +8,34 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 	db "github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/storage"
 	"github.com/tijanadmi/movieginmongoapi/util"
 )
 
-func newTestServer(t *testing.T, store db.Store) *Server {
-	config := util.Config{
-		TokenSymmetricKey:   util.RandomString(32),
-		AccessTokenDuration: time.Minute,
+// testConfig returns a valid config for test servers, with the values
+// LoadConfig would default
+func testConfig() util.Config {
+	return util.Config{
+		TokenSymmetricKey:    util.RandomString(32),
+		AccessTokenDuration:  time.Minute,
+		PosterMaxSize:        5 << 20,
+		SeatHoldDuration:     10 * time.Minute,
+		TenantHeader:         "X-Tenant-ID",
+		LoginAttemptsPerIP:   20,
+		LoginAttemptsPerUser: 5,
+		LoginMaxFailures:     5,
+		LoginLockout:         15 * time.Minute,
+		HSTSMaxAge:           365 * 24 * time.Hour,
 	}
+}
+
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := testConfig()
+
+	posters, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
 
-	server, err := NewServer(config, store)
+	server, err := NewServer(config, store, posters)
 	require.NoError(t, err)
 
 	return server
@@ -1,13 +1,21 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tijanadmi/movieginmongoapi/repository"
 	"github.com/tijanadmi/movieginmongoapi/token"
+	"github.com/tijanadmi/movieginmongoapi/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
@@ -16,6 +24,40 @@ const (
 	authorizationPayloadKey = "authorization_payload"
 )
 
+var errTokenTenant = errors.New("token was issued for another tenant")
+
+// tenantMiddleware resolves the tenant of the request from the tenant header
+// or else from the first label of the host (acme.example.com is tenant acme)
+// and scopes the store queries of the request to it. Without tenants every
+// request uses the default database.
+func tenantMiddleware(tenants []string, header string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if len(tenants) == 0 {
+			ctx.Next()
+			return
+		}
+
+		tenant := ctx.GetHeader(header)
+		if tenant == "" {
+			host := ctx.Request.Host
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			}
+			tenant, _, _ = strings.Cut(host, ".")
+		}
+
+		if !slices.Contains(tenants, tenant) {
+			err := fmt.Errorf("unknown tenant %q", tenant)
+			ctx.AbortWithStatusJSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+
+		ctx.Request = ctx.Request.WithContext(repository.WithTenant(ctx.Request.Context(), tenant))
+		addLogField(ctx, "tenant", tenant)
+		ctx.Next()
+	}
+}
+
 // AuthMiddleware creates a gin middleware for authorization
 func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -48,7 +90,212 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 			return
 		}
 
+		if payload.Tenant != repository.TenantFromContext(ctx) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(errTokenTenant))
+			return
+		}
+
 		ctx.Set(authorizationPayloadKey, payload)
+		addLogField(ctx, "username", payload.Username)
 		ctx.Next()
 	}
 }
+
+// adminMiddleware allows the request only for tokens issued with the admin role.
+// It must run after authMiddleware.
+func adminMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload, ok := ctx.Get(authorizationPayloadKey)
+		if !ok || payload.(*token.Payload).Role != util.AdminRole {
+			err := errors.New("admin role is required")
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// cinemaResolver returns the IDs of the cinemas a request is about
+type cinemaResolver func(ctx *gin.Context) ([]string, error)
+
+var errInvalidBody = errors.New("invalid request body")
+
+// maxCinemaBodySize limits the hall and repertoire bodies read for the cinema
+// references; seat maps of large halls stay well below it
+const maxCinemaBodySize = 1 << 20
+
+// cinemaAdminMiddleware allows the request for admins and for users who are
+// admins of every cinema the request is about. It must run after
+// authMiddleware.
+func cinemaAdminMiddleware(store repository.Store, cinemasOf cinemaResolver) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if payload.Role == util.AdminRole {
+			ctx.Next()
+			return
+		}
+
+		cinemaIDs, err := cinemasOf(ctx)
+		if err != nil {
+			var maxBytesError *http.MaxBytesError
+			switch {
+			case errors.Is(err, errInvalidBody):
+				ctx.AbortWithStatusJSON(http.StatusBadRequest, errorResponse(err))
+			case errors.As(err, &maxBytesError):
+				ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, errorResponse(fmt.Errorf("request body must not exceed %d bytes", maxCinemaBodySize)))
+			case errors.Is(err, repository.ErrCinemaNotFound) || errors.Is(err, repository.ErrRepertoireNotFound) || errors.Is(err, repository.ErrHallNotFound):
+				ctx.AbortWithStatusJSON(http.StatusNotFound, errorResponse(err))
+			default:
+				ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			}
+			return
+		}
+
+		user, err := store.GetUserByUsername(ctx, payload.Username)
+		allowed := err == nil && len(cinemaIDs) > 0
+		for _, cinemaID := range cinemaIDs {
+			allowed = allowed && util.HasRole(user.Roles, util.CinemaRole(util.CinemaAdminRole, cinemaID))
+		}
+		if !allowed {
+			err := errors.New("admin role for this cinema is required")
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// cinemaFromParam resolves the cinema from the :id of /cinemas/:id routes
+func cinemaFromParam(ctx *gin.Context) ([]string, error) {
+	return []string{ctx.Param("id")}, nil
+}
+
+// cinemaRefs are the references to a cinema in the body of hall and
+// repertoire requests
+type cinemaRefs struct {
+	CinemaID primitive.ObjectID `json:"cinemaId"`
+	HallID   primitive.ObjectID `json:"hallId"`
+	Hall     string             `json:"hall"`
+}
+
+// bodyCinemaRefs reads the cinema references from the JSON body and puts the
+// body back for the handler
+func bodyCinemaRefs(ctx *gin.Context) (cinemaRefs, error) {
+	var refs cinemaRefs
+	if ctx.Request.Body == nil {
+		return refs, nil
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxCinemaBodySize))
+	if err != nil {
+		return refs, err
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &refs); err != nil {
+			return refs, fmt.Errorf("%w: %s", errInvalidBody, err)
+		}
+	}
+	return refs, nil
+}
+
+// cinemaOfHallBody resolves the cinema of a POST /halls from the cinemaId of
+// the body
+func cinemaOfHallBody(ctx *gin.Context) ([]string, error) {
+	refs, err := bodyCinemaRefs(ctx)
+	if err != nil || refs.CinemaID.IsZero() {
+		return nil, err
+	}
+	return []string{refs.CinemaID.Hex()}, nil
+}
+
+// cinemaOfHall resolves the cinema from the :id of /halls/:id routes. A hall
+// moved to another cinema needs the admin of that cinema too.
+func cinemaOfHall(store repository.Store) cinemaResolver {
+	return func(ctx *gin.Context) ([]string, error) {
+		hall, err := store.GetHallById(ctx, ctx.Param("id"))
+		if err != nil {
+			return nil, err
+		}
+
+		refs, err := bodyCinemaRefs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		cinemaIDs := []string{hall.CinemaID.Hex()}
+		if !refs.CinemaID.IsZero() && refs.CinemaID != hall.CinemaID {
+			cinemaIDs = append(cinemaIDs, refs.CinemaID.Hex())
+		}
+		return cinemaIDs, nil
+	}
+}
+
+// cinemaOfRepertoireHall resolves the cinema of the hall named by hallId, or
+// else by the hall name and cinemaId, in the body of repertoire requests.
+// Without cinemaId a hall name may match halls of several cinemas, and the
+// user must be admin of all of them.
+func cinemaOfRepertoireHall(store repository.Store) cinemaResolver {
+	return func(ctx *gin.Context) ([]string, error) {
+		refs, err := bodyCinemaRefs(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case !refs.HallID.IsZero():
+			hall, err := store.GetHallById(ctx, refs.HallID.Hex())
+			if err != nil {
+				return nil, err
+			}
+			return []string{hall.CinemaID.Hex()}, nil
+		case !refs.CinemaID.IsZero():
+			return []string{refs.CinemaID.Hex()}, nil
+		case refs.Hall != "":
+			halls, err := store.GetHall(ctx, refs.Hall)
+			if err != nil {
+				return nil, err
+			}
+			cinemaIDs := make([]string, 0, len(halls))
+			for _, hall := range halls {
+				cinemaIDs = append(cinemaIDs, hall.CinemaID.Hex())
+			}
+			return cinemaIDs, nil
+		}
+		return nil, nil
+	}
+}
+
+// cinemaOfRepertoire resolves the cinema from the :id of /repertoires/:id routes
+func cinemaOfRepertoire(store repository.Store) cinemaResolver {
+	return func(ctx *gin.Context) ([]string, error) {
+		repertoire, err := store.GetRepertoire(ctx, ctx.Param("id"))
+		if err != nil {
+			return nil, err
+		}
+		return []string{repertoire.CinemaID.Hex()}, nil
+	}
+}
+
+// cinemaOfRepertoireUpdate resolves the cinema of PUT /repertoires/:id. A
+// repertoire moved to a hall of another cinema needs the admin of that cinema
+// too.
+func cinemaOfRepertoireUpdate(store repository.Store) cinemaResolver {
+	return func(ctx *gin.Context) ([]string, error) {
+		current, err := cinemaOfRepertoire(store)(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		cinemaIDs, err := cinemaOfRepertoireHall(store)(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(cinemaIDs, current[0]) {
+			cinemaIDs = append(cinemaIDs, current[0])
+		}
+		return cinemaIDs, nil
+	}
+}
@@ -1,14 +1,20 @@
 package api
 
 import (
+	"crypto/tls"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	mockdb "github.com/tijanadmi/movieginmongoapi/db/mock"
+	"github.com/tijanadmi/movieginmongoapi/models"
 	"github.com/tijanadmi/movieginmongoapi/token"
 	"github.com/tijanadmi/movieginmongoapi/util"
 )
@@ -22,7 +28,7 @@ func addAuthorization(
 	role string,
 	duration time.Duration,
 ) {
-	token, payload, err := tokenMaker.CreateToken(username, role, duration)
+	token, payload, err := tokenMaker.CreateToken(username, role, "", duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -109,3 +115,300 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestAdminMiddleware(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		role          string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Admin",
+			role: util.AdminRole,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "User",
+			role: util.UserRole,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			adminPath := "/admin"
+			server.router.GET(
+				adminPath,
+				authMiddleware(server.tokenMaker),
+				adminMiddleware(),
+				func(ctx *gin.Context) {
+					ctx.JSON(http.StatusOK, gin.H{})
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, adminPath, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, username, tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCinemaOfRepertoireHall(t *testing.T) {
+	username := util.RandomOwner()
+	hall := randomHall()
+	otherHall := randomHall()
+	otherHall.Name = hall.Name
+	user := &models.User{
+		Username: username,
+		Roles:    []string{util.UserRole, util.CinemaRole(util.CinemaAdminRole, hall.CinemaID.Hex())},
+	}
+
+	testCases := []struct {
+		name          string
+		body          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "HallID",
+			body: fmt.Sprintf(`{"hallId": %q, "time": "20:00"}`, hall.ID.Hex()),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHallById(gomock.Any(), gomock.Eq(hall.ID.Hex())).Times(1).Return(&hall, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				// the handler still gets the body
+				require.Contains(t, recorder.Body.String(), "20:00")
+			},
+		},
+		{
+			name: "HallNameOfSeveralCinemas",
+			body: fmt.Sprintf(`{"hall": %q}`, hall.Name),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHall(gomock.Any(), gomock.Eq(hall.Name)).Times(1).Return([]models.Hall{hall, otherHall}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "HallNameInCinema",
+			body: fmt.Sprintf(`{"hall": %q, "cinemaId": %q}`, hall.Name, hall.CinemaID.Hex()),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHall(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "NoHall",
+			body:       `{}`,
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:       "InvalidBody",
+			body:       `{"hallId": 1`,
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:       "BodyTooLarge",
+			body:       fmt.Sprintf(`{"hall": %q}`, strings.Repeat("A", maxCinemaBodySize)),
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			store.EXPECT().GetUserByUsername(gomock.Any(), gomock.Eq(username)).AnyTimes().Return(user, nil)
+
+			server := newTestServer(t, store)
+			path := "/cinema-admin"
+			server.router.POST(
+				path,
+				authMiddleware(server.tokenMaker),
+				cinemaAdminMiddleware(store, cinemaOfRepertoireHall(store)),
+				func(ctx *gin.Context) {
+					body, _ := io.ReadAll(ctx.Request.Body)
+					ctx.String(http.StatusOK, string(body))
+				},
+			)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, path, strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, username, util.UserRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	testCases := []struct {
+		name          string
+		origins       []string
+		origin        string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "AnyOrigin",
+			origin: "https://example.com",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+				require.Equal(t, "*", recorder.Header().Get("Access-Control-Allow-Origin"))
+				// credentials are never allowed for any origin
+				require.Empty(t, recorder.Header().Get("Access-Control-Allow-Credentials"))
+				require.Contains(t, recorder.Header().Get("Access-Control-Allow-Headers"), "X-Tenant-Id")
+			},
+		},
+		{
+			name:    "AllowedOrigin",
+			origins: []string{"https://movies.example.com"},
+			origin:  "https://movies.example.com",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+				require.Equal(t, "https://movies.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+				require.Equal(t, "true", recorder.Header().Get("Access-Control-Allow-Credentials"))
+			},
+		},
+		{
+			name:    "OtherOrigin",
+			origins: []string{"https://movies.example.com"},
+			origin:  "https://evil.example.com",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			config := testConfig()
+			config.CORSAllowedOrigins = tc.origins
+			cors, err := corsMiddleware(config)
+			require.NoError(t, err)
+
+			router := gin.New()
+			router.Use(cors)
+			router.GET("/halls", func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{})
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodOptions, "/halls", nil)
+			require.NoError(t, err)
+			request.Header.Set("Origin", tc.origin)
+			request.Header.Set("Access-Control-Request-Method", http.MethodGet)
+
+			router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCORSMiddlewareInvalidOrigin(t *testing.T) {
+	_, err := corsMiddleware(util.Config{CORSAllowedOrigins: []string{"movies.example.com"}})
+	require.Error(t, err)
+}
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	testCases := []struct {
+		name     string
+		setup    func(request *http.Request)
+		wantHSTS string
+	}{
+		{
+			name: "HTTP",
+		},
+		{
+			name: "TLS",
+			setup: func(request *http.Request) {
+				request.TLS = &tls.ConnectionState{}
+			},
+			wantHSTS: "max-age=3600; includeSubDomains",
+		},
+		{
+			name: "BehindProxy",
+			setup: func(request *http.Request) {
+				request.RemoteAddr = "10.0.0.2:40000"
+				request.Header.Set("X-Forwarded-Proto", "https")
+			},
+			wantHSTS: "max-age=3600; includeSubDomains",
+		},
+		{
+			name: "BehindProxyIP",
+			setup: func(request *http.Request) {
+				request.RemoteAddr = "192.0.2.10:40000"
+				request.Header.Set("X-Forwarded-Proto", "https")
+			},
+			wantHSTS: "max-age=3600; includeSubDomains",
+		},
+		{
+			name: "ForwardedProtoFromClient",
+			setup: func(request *http.Request) {
+				request.RemoteAddr = "203.0.113.7:40000"
+				request.Header.Set("X-Forwarded-Proto", "https")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			securityHeaders, err := securityHeadersMiddleware(time.Hour, []string{"10.0.0.0/8", "192.0.2.10"})
+			require.NoError(t, err)
+			router.Use(securityHeaders)
+			router.GET("/halls", func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{})
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/halls", nil)
+			require.NoError(t, err)
+			if tc.setup != nil {
+				tc.setup(request)
+			}
+
+			router.ServeHTTP(recorder, request)
+			require.Equal(t, "nosniff", recorder.Header().Get("X-Content-Type-Options"))
+			require.Equal(t, "DENY", recorder.Header().Get("X-Frame-Options"))
+			require.Equal(t, tc.wantHSTS, recorder.Header().Get("Strict-Transport-Security"))
+		})
+	}
+}
@@ -4,12 +4,53 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/tijanadmi/movieginmongoapi/models"
 	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/util"
 )
 
+const (
+	defaultNowShowingDays = 7
+	maxNowShowingDays     = 31
+)
+
+// movieRequest holds the movie fields a client may set; the id, the poster
+// sizes, the ratings and the deletion marks are kept by the server
+type movieRequest struct {
+	Title        string                             `json:"title"`
+	Duration     int32                              `json:"duration"`
+	Genre        models.StringList                  `json:"genre" swaggertype:"array,string"`
+	Directors    models.StringList                  `json:"directors" swaggertype:"array,string"`
+	Actors       models.StringList                  `json:"actors" swaggertype:"array,string"`
+	Screening    time.Time                          `json:"screening"`
+	Plot         string                             `json:"plot"`
+	Translations map[string]models.MovieTranslation `json:"translations"`
+	Poster       string                             `json:"poster"`
+	Screenings   []models.Screening                 `json:"screenings"`
+	AgeRating    int                                `json:"ageRating" binding:"omitempty,oneof=12 14 16 18"`
+}
+
+func (req movieRequest) movie() *models.Movie {
+	return &models.Movie{
+		Title:        req.Title,
+		Duration:     req.Duration,
+		Genre:        req.Genre,
+		Directors:    req.Directors,
+		Actors:       req.Actors,
+		Screening:    req.Screening,
+		Plot:         req.Plot,
+		Translations: req.Translations,
+		Poster:       req.Poster,
+		Screenings:   req.Screenings,
+		AgeRating:    req.AgeRating,
+	}
+}
+
 // searchMovies godoc
 // @Security bearerAuth
 // @Summary List existing movie by id
@@ -18,6 +59,7 @@ import (
 // @Accept  json
 // @Produce  json
 // @Param  id path string true "Movie ID"
+// @Param  Accept-Language header string false "Preferred content language (sr-Latn, sr-Cyrl, en)"
 // @Success 200  models.Movie
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
@@ -36,6 +78,7 @@ func (server *Server) searchMovies(ctx *gin.Context) {
 		return
 	}
 
+	movie.Localize(negotiateLanguage(ctx))
 	ctx.JSON(http.StatusOK, movie)
 }
 
@@ -46,11 +89,18 @@ func (server *Server) searchMovies(ctx *gin.Context) {
 // @ID listMovies
 // @Accept  json
 // @Produce  json
+// @Param  maxAgeRating query int false "Only movies rated for this age or younger"
+// @Param  Accept-Language header string false "Preferred content language (sr-Latn, sr-Cyrl, en)"
 // @Success 200 {array} models.Movie
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
 // @Router /movies [get]
 func (server *Server) listMovies(ctx *gin.Context) {
+	maxAgeRating, filterAge, err := maxAgeRatingParam(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
 
 	movies, err := server.store.SearchMovies(ctx, "0")
 	if err != nil {
@@ -58,6 +108,302 @@ func (server *Server) listMovies(ctx *gin.Context) {
 		return
 	}
 
+	if filterAge {
+		movies = filterByAgeRating(movies, maxAgeRating, func(m models.Movie) int { return m.AgeRating })
+	}
+
+	lang := negotiateLanguage(ctx)
+	for i := range movies {
+		movies[i].Localize(lang)
+	}
+
+	ctx.JSON(http.StatusOK, movies)
+}
+
+// searchMoviesText godoc
+// @Security bearerAuth
+// @Summary Full-text search of movies
+// @Description Search movies by title, plot, actors and directors, ordered by relevance
+// @ID searchMoviesText
+// @Accept  json
+// @Produce  json
+// @Param  q query string false "Search text"
+// @Param  genre query string false "Genre"
+// @Param  showing query bool false "Only movies with upcoming screenings"
+// @Param  from query string false "Screenings from date (YYYY-MM-DD)"
+// @Param  to query string false "Screenings to date (YYYY-MM-DD)"
+// @Param  maxAgeRating query int false "Only movies rated for this age or younger"
+// @Param  Accept-Language header string false "Preferred content language (sr-Latn, sr-Cyrl, en)"
+// @Success 200 {array} models.Movie
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Router /movies/search [get]
+func (server *Server) searchMoviesText(ctx *gin.Context) {
+	maxAgeRating, filterAge, err := maxAgeRatingParam(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	arg := repository.SearchMoviesParams{
+		Query: strings.TrimSpace(ctx.Query("q")),
+		Genre: strings.TrimSpace(ctx.Query("genre")),
+	}
+
+	if showing := ctx.Query("showing"); showing != "" {
+		currentlyShown, err := strconv.ParseBool(showing)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: "invalid showing parameter"})
+			return
+		}
+		arg.CurrentlyShown = currentlyShown
+	}
+
+	if from := ctx.Query("from"); from != "" {
+		fromValue, err := util.ParseDate(from)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: "Error parsing from"})
+			return
+		}
+		arg.From = fromValue
+	}
+
+	if to := ctx.Query("to"); to != "" {
+		toValue, err := util.ParseDate(to)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: "Error parsing to"})
+			return
+		}
+		arg.To = toValue
+	}
+
+	if !arg.From.IsZero() && !arg.To.IsZero() && arg.To.Before(arg.From) {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: "to must not be before from"})
+		return
+	}
+
+	movies, err := server.store.SearchMoviesText(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if filterAge {
+		movies = filterByAgeRating(movies, maxAgeRating, func(m models.Movie) int { return m.AgeRating })
+	}
+
+	lang := negotiateLanguage(ctx)
+	for i := range movies {
+		movies[i].Localize(lang)
+	}
+
+	ctx.JSON(http.StatusOK, movies)
+}
+
+// listNowShowing godoc
+// @Security bearerAuth
+// @Summary List movies now showing
+// @Description Get the movies with screenings in the next N days, with showtimes grouped by date and hall
+// @ID listNowShowing
+// @Accept  json
+// @Produce  json
+// @Param  days query int false "Number of days ahead (default 7)"
+// @Param  maxAgeRating query int false "Only movies rated for this age or younger"
+// @Param  Accept-Language header string false "Preferred content language (sr-Latn, sr-Cyrl, en)"
+// @Success 200 {array} models.MovieSchedule
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Router /movies/now-showing [get]
+func (server *Server) listNowShowing(ctx *gin.Context) {
+	maxAgeRating, filterAge, err := maxAgeRatingParam(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	days := defaultNowShowingDays
+	if daysParam := ctx.Query("days"); daysParam != "" {
+		value, err := strconv.Atoi(daysParam)
+		if err != nil || value < 1 || value > maxNowShowingDays {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: fmt.Sprintf("days must be between 1 and %d", maxNowShowingDays)})
+			return
+		}
+		days = value
+	}
+
+	today := startOfToday()
+	movies, err := server.store.ListNowShowing(ctx, today, today.AddDate(0, 0, days-1))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if filterAge {
+		movies = filterByAgeRating(movies, maxAgeRating, func(m models.MovieSchedule) int { return m.AgeRating })
+	}
+
+	lang := negotiateLanguage(ctx)
+	for i := range movies {
+		movies[i].Localize(lang)
+	}
+
+	ctx.JSON(http.StatusOK, movies)
+}
+
+// listComingSoon godoc
+// @Security bearerAuth
+// @Summary List movies coming soon
+// @Description Get the movies whose premiere is in the future, with already scheduled showtimes
+// @ID listComingSoon
+// @Accept  json
+// @Produce  json
+// @Param  maxAgeRating query int false "Only movies rated for this age or younger"
+// @Param  Accept-Language header string false "Preferred content language (sr-Latn, sr-Cyrl, en)"
+// @Success 200 {array} models.MovieSchedule
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Router /movies/coming-soon [get]
+func (server *Server) listComingSoon(ctx *gin.Context) {
+	maxAgeRating, filterAge, err := maxAgeRatingParam(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	movies, err := server.store.ListComingSoon(ctx, startOfToday())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if filterAge {
+		movies = filterByAgeRating(movies, maxAgeRating, func(m models.MovieSchedule) int { return m.AgeRating })
+	}
+
+	lang := negotiateLanguage(ctx)
+	for i := range movies {
+		movies[i].Localize(lang)
+	}
+
+	ctx.JSON(http.StatusOK, movies)
+}
+
+// maxAgeRatingParam reads the optional maxAgeRating query parameter
+func maxAgeRatingParam(ctx *gin.Context) (int, bool, error) {
+	value := ctx.Query("maxAgeRating")
+	if value == "" {
+		return 0, false, nil
+	}
+
+	maxAgeRating, err := strconv.Atoi(value)
+	if err != nil || maxAgeRating < 0 {
+		return 0, false, errors.New("invalid maxAgeRating parameter")
+	}
+	return maxAgeRating, true, nil
+}
+
+// filterByAgeRating keeps the items whose age rating is at most maxAgeRating
+func filterByAgeRating[T any](items []T, maxAgeRating int, ageRating func(T) int) []T {
+	filtered := make([]T, 0, len(items))
+	for _, item := range items {
+		if ageRating(item) <= maxAgeRating {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// startOfToday returns today's date the way repertoire dates are stored
+func startOfToday() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+// listMoviesByGenre godoc
+// @Security bearerAuth
+// @Summary List movies of a genre
+// @Description Get all movies of the given genre
+// @ID listMoviesByGenre
+// @Accept  json
+// @Produce  json
+// @Param  genre path string true "Genre"
+// @Param  maxAgeRating query int false "Only movies rated for this age or younger"
+// @Param  Accept-Language header string false "Preferred content language (sr-Latn, sr-Cyrl, en)"
+// @Success 200 {array} models.Movie
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Router /movies/genre/{genre} [get]
+func (server *Server) listMoviesByGenre(ctx *gin.Context) {
+	maxAgeRating, filterAge, err := maxAgeRatingParam(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	genre := strings.TrimSpace(ctx.Param("genre"))
+
+	movies, err := server.store.ListMoviesByGenre(ctx, genre)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if filterAge {
+		movies = filterByAgeRating(movies, maxAgeRating, func(m models.Movie) int { return m.AgeRating })
+	}
+
+	lang := negotiateLanguage(ctx)
+	for i := range movies {
+		movies[i].Localize(lang)
+	}
+
+	ctx.JSON(http.StatusOK, movies)
+}
+
+// listMoviesByPerson godoc
+// @Security bearerAuth
+// @Summary List movies of a person
+// @Description Get all movies the person acted in or directed
+// @ID listMoviesByPerson
+// @Accept  json
+// @Produce  json
+// @Param  name path string true "Actor or director name"
+// @Param  role query string false "actor or director"
+// @Param  maxAgeRating query int false "Only movies rated for this age or younger"
+// @Param  Accept-Language header string false "Preferred content language (sr-Latn, sr-Cyrl, en)"
+// @Success 200 {array} models.Movie
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Router /movies/person/{name} [get]
+func (server *Server) listMoviesByPerson(ctx *gin.Context) {
+	maxAgeRating, filterAge, err := maxAgeRatingParam(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	name := strings.TrimSpace(ctx.Param("name"))
+	role := ctx.Query("role")
+
+	movies, err := server.store.ListMoviesByPerson(ctx, name, role)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidPersonRole) {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if filterAge {
+		movies = filterByAgeRating(movies, maxAgeRating, func(m models.Movie) int { return m.AgeRating })
+	}
+
+	lang := negotiateLanguage(ctx)
+	for i := range movies {
+		movies[i].Localize(lang)
+	}
+
 	ctx.JSON(http.StatusOK, movies)
 }
 
@@ -68,17 +414,23 @@ func (server *Server) listMovies(ctx *gin.Context) {
 // @ID InsertMovie
 // @Accept  json
 // @Produce  json
-// @Param movie body models.Movie true "Create movie"
+// @Param movie body movieRequest true "Create movie"
 // @Success 201 {array} models.Movie
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
 // @Router /movies [post]
 func (server *Server) InsertMovie(ctx *gin.Context) {
-	var movie *models.Movie
-	if err := ctx.ShouldBindJSON(&movie); err != nil {
+	var req movieRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: fmt.Sprintf(" invalid input: %s", err.Error())})
 		return
 	}
+	movie := req.movie()
+
+	if err := validateTranslations(movie.Translations); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
 
 	movie, err := server.store.AddMovie(ctx, movie)
 	if err != nil {
@@ -97,18 +449,24 @@ func (server *Server) InsertMovie(ctx *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param  id path string true "Movie ID"
-// @Param movie body models.Movie true "Update hall"
+// @Param movie body movieRequest true "Update movie"
 // @Success 200 {array} models.Movie
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
 // @Router /movies/{id} [put]
 func (server *Server) UpdateMovie(ctx *gin.Context) {
 	id := ctx.Param("id")
-	var movie *models.Movie
-	if err := ctx.ShouldBindJSON(&movie); err != nil {
+	var req movieRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: fmt.Sprintf(" invalid input: %s", err.Error())})
 		return
 	}
+	movie := req.movie()
+
+	if err := validateTranslations(movie.Translations); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
 
 	modifiedMovie, err := server.store.UpdateMovie(ctx, id, movie)
 	if err != nil {
@@ -122,23 +480,44 @@ func (server *Server) UpdateMovie(ctx *gin.Context) {
 // DeleteMovie godoc
 // @Security bearerAuth
 // @Summary Delete a single movie
-// @Description Delete a single movie
+// @Description Delete a single movie. If the movie has repertoires or reservations, it is only deleted with cascade, which cancels them and notifies the affected users.
 // @ID DeleteMovie
 // @Accept  json
 // @Produce  json
 // @Param  id path string true "Movie ID"
+// @Param  cascade query bool false "Also cancel the dependents"
 // @Success 200 {array} apiResponse
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Failure 409 {object} dependentsErrorResponse
 // @Router /movies/{id} [delete]
 func (server *Server) DeleteMovie(ctx *gin.Context) {
-	id := ctx.Param("id")
+	server.deleteRecord(ctx, ctx.Param("id"), server.store.DeleteMovie, repository.ErrMovieNotFound, "Movie has been deleted")
+}
 
-	err := server.store.DeleteMovie(ctx, id)
+// RestoreMovie godoc
+// @Security bearerAuth
+// @Summary Restore a deleted movie
+// @Description Restore a soft-deleted movie (admin only)
+// @ID RestoreMovie
+// @Produce  json
+// @Param  id path string true "Movie ID"
+// @Success 200 {object} apiResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Router /movies/{id}/restore [put]
+func (server *Server) RestoreMovie(ctx *gin.Context) {
+	err := server.store.RestoreMovie(ctx, ctx.Param("id"))
 	if err != nil {
+		if errors.Is(err, repository.ErrMovieNotFound) {
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, apiResponse{Message: "Movie has been deleted"})
+	ctx.JSON(http.StatusOK, apiResponse{Message: "Movie has been restored"})
 }
@@ -2,12 +2,14 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 	"time"
 
@@ -35,9 +37,9 @@ func (m movieMatcher) Matches(x interface{}) bool {
 
 	return actual.Title == m.expected.Title &&
 		actual.Duration == m.expected.Duration &&
-		actual.Genre == m.expected.Genre &&
-		actual.Directors == m.expected.Directors &&
-		actual.Actors == m.expected.Actors &&
+		reflect.DeepEqual(actual.Genre, m.expected.Genre) &&
+		reflect.DeepEqual(actual.Directors, m.expected.Directors) &&
+		reflect.DeepEqual(actual.Actors, m.expected.Actors) &&
 		actual.Screening.Truncate(time.Second).Equal(m.expected.Screening.Truncate(time.Second)) &&
 		actual.Plot == m.expected.Plot &&
 		actual.Poster == m.expected.Poster
@@ -158,9 +160,12 @@ func TestListMoviesAPI(t *testing.T) {
 	for i := 0; i < n; i++ {
 		movies[i] = randomMovie()
 	}
+	movies[0].AgeRating = 12
+	movies[1].AgeRating = 18
 
 	testCases := []struct {
 		name          string
+		maxAgeRating  string
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(recoder *httptest.ResponseRecorder)
@@ -182,27 +187,478 @@ func TestListMoviesAPI(t *testing.T) {
 				//requireBodyMatchHalls(t, recorder.Body, halls)
 			},
 		},
+		{
+			name:         "MaxAgeRating",
+			maxAgeRating: "12",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchMovies(gomock.Any(), gomock.Eq("0")).
+					Times(1).
+					Return(movies, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotMovies []models.Movie
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &gotMovies))
+				require.Len(t, gotMovies, n-1)
+				for _, movie := range gotMovies {
+					require.LessOrEqual(t, movie.AgeRating, 12)
+				}
+			},
+		},
+		{
+			name:         "InvalidMaxAgeRating",
+			maxAgeRating: "adults",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchMovies(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "NoAuthorization",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					SearchMovies(gomock.Any(), gomock.Any()).
-					Times(0)
+					SearchMovies(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchMovies(gomock.Any(), gomock.Eq("0")).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := "/movies"
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			// Add query parameters to request URL
+			q := request.URL.Query()
+			if tc.maxAgeRating != "" {
+				q.Add("maxAgeRating", tc.maxAgeRating)
+			}
+			request.URL.RawQuery = q.Encode()
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestSearchMoviesTextAPI(t *testing.T) {
+	username := util.RandomOwner()
+	role := util.UserRole
+	movies := []models.Movie{randomMovie(), randomMovie()}
+
+	testCases := []struct {
+		name          string
+		query         map[string]string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			query: map[string]string{
+				"q":       "titanik",
+				"genre":   "drama",
+				"showing": "true",
+				"from":    "2024-07-01",
+				"to":      "2024-07-31",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := repository.SearchMoviesParams{
+					Query:          "titanik",
+					Genre:          "drama",
+					CurrentlyShown: true,
+					From:           time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+					To:             time.Date(2024, time.July, 31, 0, 0, 0, 0, time.UTC),
+				}
+				store.EXPECT().
+					SearchMoviesText(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(movies, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "NoAuthorization",
+			query: map[string]string{"q": "titanik"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchMoviesText(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidFrom",
+			query: map[string]string{"q": "titanik", "from": "01.07.2024"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchMoviesText(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidRange",
+			query: map[string]string{"from": "2024-07-31", "to": "2024-07-01"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchMoviesText(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: map[string]string{"q": "titanik"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchMoviesText(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/movies/search", nil)
+			require.NoError(t, err)
+
+			q := request.URL.Query()
+			for key, value := range tc.query {
+				q.Add(key, value)
+			}
+			request.URL.RawQuery = q.Encode()
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListNowShowingAPI(t *testing.T) {
+	username := util.RandomOwner()
+	role := util.UserRole
+	movies := []models.MovieSchedule{{Movie: randomMovie()}}
+	today := startOfToday()
+
+	testCases := []struct {
+		name          string
+		days          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "DefaultDays",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListNowShowing(gomock.Any(), gomock.Eq(today), gomock.Eq(today.AddDate(0, 0, defaultNowShowingDays-1))).
+					Times(1).
+					Return(movies, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "CustomDays",
+			days: "3",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListNowShowing(gomock.Any(), gomock.Eq(today), gomock.Eq(today.AddDate(0, 0, 2))).
+					Times(1).
+					Return(movies, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidDays",
+			days: "0",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListNowShowing(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListNowShowing(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/movies/now-showing", nil)
+			require.NoError(t, err)
+
+			if tc.days != "" {
+				q := request.URL.Query()
+				q.Add("days", tc.days)
+				request.URL.RawQuery = q.Encode()
+			}
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListComingSoonAPI(t *testing.T) {
+	username := util.RandomOwner()
+	role := util.UserRole
+	movies := []models.MovieSchedule{{Movie: randomMovie()}}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListComingSoon(gomock.Any(), gomock.Eq(startOfToday())).
+					Times(1).
+					Return(movies, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListComingSoon(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/movies/coming-soon", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListMoviesByPersonAPI(t *testing.T) {
+	username := util.RandomOwner()
+	role := util.UserRole
+	movies := []models.Movie{randomMovie()}
+
+	testCases := []struct {
+		name          string
+		url           string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			url:  "/movies/person/Kejt%20Vinslet?role=actor",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListMoviesByPerson(gomock.Any(), gomock.Eq("Kejt Vinslet"), gomock.Eq(repository.PersonRoleActor)).
+					Times(1).
+					Return(movies, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMovies(t, recorder.Body, movies)
 			},
 		},
 		{
-			name: "InternalError",
+			name: "InvalidRole",
+			url:  "/movies/person/Kameron?role=producer",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					SearchMovies(gomock.Any(), gomock.Eq("0")).
+					ListMoviesByPerson(gomock.Any(), gomock.Eq("Kameron"), gomock.Eq("producer")).
+					Times(1).
+					Return(nil, repository.ErrInvalidPersonRole)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Genre",
+			url:  "/movies/genre/drama",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListMoviesByGenre(gomock.Any(), gomock.Eq("drama")).
+					Times(1).
+					Return(movies, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "GenreInternalError",
+			url:  "/movies/genre/drama",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListMoviesByGenre(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(nil, sql.ErrConnDone)
 			},
@@ -225,14 +681,9 @@ func TestListMoviesAPI(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			url := "/movies"
-			request, err := http.NewRequest(http.MethodGet, url, nil)
+			request, err := http.NewRequest(http.MethodGet, tc.url, nil)
 			require.NoError(t, err)
 
-			// Add query parameters to request URL
-			q := request.URL.Query()
-			request.URL.RawQuery = q.Encode()
-
 			tc.setupAuth(t, request, server.tokenMaker)
 			server.router.ServeHTTP(recorder, request)
 			tc.checkResponse(recorder)
@@ -286,6 +737,94 @@ func TestInsertMovieAPI(t *testing.T) {
 				requireBodyMatchMovie(t, recorder.Body, movie)
 			},
 		},
+		{
+			name: "LegacyStringLists",
+			body: gin.H{
+				"title":     movie.Title,
+				"duration":  movie.Duration,
+				"genre":     "drama",
+				"directors": "Kameron",
+				"actors":    "Leonardo Di Kaprio, Kejt Vinslet",
+				"screening": movie.Screening,
+				"plot":      movie.Plot,
+				"poster":    movie.Poster,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := &models.Movie{
+					Title:     movie.Title,
+					Duration:  movie.Duration,
+					Genre:     movie.Genre,
+					Directors: movie.Directors,
+					Actors:    movie.Actors,
+					Screening: movie.Screening,
+					Plot:      movie.Plot,
+					Poster:    movie.Poster,
+				}
+				store.EXPECT().AddMovie(gomock.Any(), movieMatcher{expected: arg}).Times(1).Return(&movie, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "ServerFieldsIgnored",
+			body: gin.H{
+				"id":           movie.ID.Hex(),
+				"title":        movie.Title,
+				"duration":     movie.Duration,
+				"plot":         movie.Plot,
+				"score":        5,
+				"avgRating":    5,
+				"numOfRatings": 1000,
+				"poster_sizes": gin.H{"small": "fake.jpg"},
+				"deleted_at":   time.Now().UTC(),
+				"deleted_by":   username,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddMovie(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, movie *models.Movie) (*models.Movie, error) {
+						require.True(t, movie.ID.IsZero())
+						require.Zero(t, movie.Score)
+						require.Zero(t, movie.AvgRating)
+						require.Zero(t, movie.NumOfRatings)
+						require.Nil(t, movie.PosterSizes)
+						require.Nil(t, movie.DeletedAt)
+						require.Empty(t, movie.DeletedBy)
+						return movie, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "UnsupportedTranslation",
+			body: gin.H{
+				"title":        movie.Title,
+				"duration":     movie.Duration,
+				"plot":         movie.Plot,
+				"translations": gin.H{"de": gin.H{"title": "Wer singt denn da?"}},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddMovie(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "InternalError",
 			body: gin.H{
@@ -414,6 +953,37 @@ func TestUpdateMovieAPI(t *testing.T) {
 				// requireBodyMatchMovie(t, recorder.Body, movie)
 			},
 		},
+		{
+			name:    "ServerFieldsIgnored",
+			movieID: movie.ID.Hex(),
+			body: gin.H{
+				"title":        movie.Title,
+				"score":        5,
+				"avgRating":    5,
+				"numOfRatings": 1000,
+				"deleted_at":   time.Now().UTC(),
+				"deleted_by":   username,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateMovie(gomock.Any(), gomock.Eq(movie.ID.Hex()), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, _ string, movie *models.Movie) (*models.Movie, error) {
+						require.Zero(t, movie.Score)
+						require.Zero(t, movie.AvgRating)
+						require.Zero(t, movie.NumOfRatings)
+						require.Nil(t, movie.DeletedAt)
+						require.Empty(t, movie.DeletedBy)
+						return movie, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
 		{
 			name:    "NoAuthorization",
 			movieID: movie.ID.Hex(),
@@ -681,9 +1251,15 @@ func TestDeleteMovieAPI(t *testing.T) {
 	movie := randomMovie()
 	movieID := movie.ID.Hex()
 
+	dependents := repository.Dependents{
+		Repertoires:  []models.Repertoire{{ID: primitive.NewObjectID(), MovieID: movie.ID, Time: "20:00", Hall: "Sala 1"}},
+		Reservations: []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()},
+	}
+
 	testCases := []struct {
 		name          string
 		movieID       string
+		query         string
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(recorder *httptest.ResponseRecorder)
@@ -696,15 +1272,72 @@ func TestDeleteMovieAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					DeleteMovie(gomock.Any(), gomock.Eq(movieID)).
+					DeleteMovie(gomock.Any(), gomock.Eq(movieID), gomock.Eq(username), gomock.Eq(false)).
 					Times(1).
-					Return(nil)
+					Return(repository.Dependents{}, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 				requireBodyMatchResponse(t, recorder.Body, apiResponse{Message: "Movie has been deleted"})
 			},
 		},
+		{
+			name:    "HasDependents",
+			movieID: movieID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteMovie(gomock.Any(), gomock.Eq(movieID), gomock.Eq(username), gomock.Eq(false)).
+					Times(1).
+					Return(dependents, repository.ErrHasDependents)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+
+				var got dependentsErrorResponse
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&got))
+				require.Equal(t, repository.ErrHasDependents.Error(), got.Error)
+				require.Len(t, got.Dependents.Repertoires, 1)
+				require.Equal(t, dependents.Repertoires[0].ID, got.Dependents.Repertoires[0].ID)
+				require.Equal(t, dependents.Reservations, got.Dependents.Reservations)
+			},
+		},
+		{
+			name:    "Cascade",
+			movieID: movieID,
+			query:   "?cascade=true",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteMovie(gomock.Any(), gomock.Eq(movieID), gomock.Eq(username), gomock.Eq(true)).
+					Times(1).
+					Return(dependents, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchResponse(t, recorder.Body, apiResponse{Message: "Movie has been deleted, 1 repertoires and 2 reservations cancelled"})
+			},
+		},
+		{
+			name:    "InvalidCascade",
+			movieID: movieID,
+			query:   "?cascade=maybe",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteMovie(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name:    "InternalError",
 			movieID: movieID,
@@ -713,9 +1346,9 @@ func TestDeleteMovieAPI(t *testing.T) {
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					DeleteMovie(gomock.Any(), gomock.Eq(movieID)).
+					DeleteMovie(gomock.Any(), gomock.Eq(movieID), gomock.Eq(username), gomock.Eq(false)).
 					Times(1).
-					Return(sql.ErrConnDone)
+					Return(repository.Dependents{}, sql.ErrConnDone)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -737,7 +1370,7 @@ func TestDeleteMovieAPI(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			url := "/movies/" + tc.movieID
+			url := "/movies/" + tc.movieID + tc.query
 			request, err := http.NewRequest(http.MethodDelete, url, nil)
 			require.NoError(t, err)
 
@@ -748,6 +1381,87 @@ func TestDeleteMovieAPI(t *testing.T) {
 	}
 }
 
+func TestRestoreMovieAPI(t *testing.T) {
+	admin := util.RandomOwner()
+	movieID := primitive.NewObjectID().Hex()
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RestoreMovie(gomock.Any(), gomock.Eq(movieID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchResponse(t, recorder.Body, apiResponse{Message: "Movie has been restored"})
+			},
+		},
+		{
+			name: "NotAdmin",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.UserRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RestoreMovie(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotDeleted",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RestoreMovie(gomock.Any(), gomock.Eq(movieID)).
+					Times(1).
+					Return(repository.ErrMovieNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := "/movies/" + movieID + "/restore"
+			request, err := http.NewRequest(http.MethodPut, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
 // func randomMovie() models.Movie {
 // 	objectID := primitive.NewObjectID()
 // 	return models.Movie{
@@ -769,10 +1483,10 @@ func randomMovie() models.Movie {
 		ID:        objectID,
 		Title:     "Titanik",
 		Duration:  132,
-		Genre:     "drama",
-		Directors: "Kameron",
-		Actors:    "Leonardo Di Kaprio, Kejt Vinslet",
-		Screening: time.Now(),
+		Genre:     models.StringList{"drama"},
+		Directors: models.StringList{"Kameron"},
+		Actors:    models.StringList{"Leonardo Di Kaprio", "Kejt Vinslet"},
+		Screening: time.Now().UTC(),
 		Plot:      "nekada davno",
 		Poster:    "skinuti sa interneta",
 	}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tijanadmi/movieginmongoapi/token"
+)
+
+// ListNotifications godoc
+// @Security bearerAuth
+// @Summary Get the notifications of the logged in user
+// @Description Get the notifications of the logged in user, such as cancelled screenings, newest first
+// @ID ListNotifications
+// @Produce  json
+// @Success 200 {array} models.Notification
+// @Failure 401 {object} apiErrorResponse
+// @Router /notifications [get]
+func (server *Server) ListNotifications(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	notifications, err := server.store.ListNotifications(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, notifications)
+}
@@ -0,0 +1,236 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/storage"
+	"github.com/tijanadmi/movieginmongoapi/util"
+)
+
+const (
+	posterFormField = "poster"
+	posterURLPrefix = "/posters/"
+	// poster names contain a hash of the content, so they never change once stored
+	posterCacheControl = "public, max-age=31536000, immutable"
+	// a small file can declare huge dimensions, and decoding allocates
+	// 4 bytes per pixel, so larger images are rejected before decoding
+	posterMaxPixels = 40_000_000
+)
+
+// posterExtensions lists the accepted poster content types, detected from the content itself
+var posterExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+// posterSizes are the resized variants generated for every uploaded poster
+var posterSizes = []struct {
+	Name  string
+	Width int
+}{
+	{Name: "thumbnail", Width: 92},
+	{Name: "small", Width: 185},
+	{Name: "medium", Width: 342},
+	{Name: "large", Width: 780},
+}
+
+// uploadMoviePoster godoc
+// @Security bearerAuth
+// @Summary Upload a movie poster
+// @Description Upload a JPEG or PNG poster; resized variants are generated and the movie is updated
+// @ID uploadMoviePoster
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param  id path string true "Movie ID"
+// @Param  poster formData file true "Poster image"
+// @Success 200 {object} models.Movie
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Failure 413 {object} apiErrorResponse
+// @Failure 415 {object} apiErrorResponse
+// @Router /movies/{id}/poster [post]
+func (server *Server) uploadMoviePoster(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	movie, err := server.store.GetMovie(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrMovieNotFound) {
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	maxSize := server.config.PosterMaxSize
+	tooLarge := fmt.Sprintf("poster must not be larger than %d bytes", maxSize)
+
+	// leave some room for the multipart envelope around the file
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize+64<<10)
+	fileHeader, err := ctx.FormFile(posterFormField)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, apiErrorResponse{Error: tooLarge})
+			return
+		}
+
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: fmt.Sprintf(" invalid input: %s", err.Error())})
+		return
+	}
+	if fileHeader.Size > maxSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, apiErrorResponse{Error: tooLarge})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := posterExtensions[contentType]
+	if !ok {
+		ctx.JSON(http.StatusUnsupportedMediaType, apiErrorResponse{Error: fmt.Sprintf("unsupported poster type %s", contentType)})
+		return
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: "invalid image"})
+		return
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > posterMaxPixels {
+		ctx.JSON(http.StatusRequestEntityTooLarge, apiErrorResponse{
+			Error: fmt.Sprintf("poster must not have more than %d pixels", posterMaxPixels),
+		})
+		return
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: "invalid image"})
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	baseName := fmt.Sprintf("%s-%s", movie.ID.Hex(), hex.EncodeToString(sum[:6]))
+
+	poster := baseName + ext
+	if err = server.posters.Save(ctx, poster, contentType, data); err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	sizes := make(map[string]string, len(posterSizes))
+	for _, size := range posterSizes {
+		resized, err := encodePoster(util.ResizeImage(img, size.Width), contentType)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+			return
+		}
+
+		name := fmt.Sprintf("%s-%s%s", baseName, size.Name, ext)
+		if err = server.posters.Save(ctx, name, contentType, resized); err != nil {
+			ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+			return
+		}
+		sizes[size.Name] = posterURLPrefix + name
+	}
+
+	if err = server.store.UpdateMoviePoster(ctx, id, posterURLPrefix+poster, sizes); err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// The previous poster files are no longer referenced; failing to remove them is not an error.
+	if movie.Poster != posterURLPrefix+poster {
+		server.deletePosterFiles(ctx, movie.Poster, movie.PosterSizes)
+	}
+
+	movie.Poster = posterURLPrefix + poster
+	movie.PosterSizes = sizes
+	ctx.JSON(http.StatusOK, movie)
+}
+
+// getPoster godoc
+// @Summary Get a poster image
+// @Description Serve a stored poster image with long-lived cache headers
+// @ID getPoster
+// @Produce  image/jpeg,image/png
+// @Param  name path string true "Poster file name"
+// @Success 200 {file} file
+// @Failure 404 {object} apiErrorResponse
+// @Router /posters/{name} [get]
+func (server *Server) getPoster(ctx *gin.Context) {
+	name := ctx.Param("name")
+
+	content, object, err := server.posters.Open(ctx, name)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+	defer content.Close()
+
+	ctx.Header("Cache-Control", posterCacheControl)
+	ctx.Header("ETag", fmt.Sprintf("%q", name))
+	if object.ContentType != "" {
+		ctx.Header("Content-Type", object.ContentType)
+	}
+	http.ServeContent(ctx.Writer, ctx.Request, name, object.ModTime, content)
+}
+
+func (server *Server) deletePosterFiles(ctx *gin.Context, poster string, sizes map[string]string) {
+	urls := []string{poster}
+	for _, url := range sizes {
+		urls = append(urls, url)
+	}
+
+	for _, url := range urls {
+		if name, ok := strings.CutPrefix(url, posterURLPrefix); ok {
+			_ = server.posters.Delete(ctx, name)
+		}
+	}
+}
+
+func encodePoster(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode poster: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,264 @@
+package api
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/tijanadmi/movieginmongoapi/db/mock"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/token"
+	"github.com/tijanadmi/movieginmongoapi/util"
+)
+
+func TestUploadMoviePosterAPI(t *testing.T) {
+	username := util.RandomOwner()
+	role := util.AdminRole
+	movie := randomMovie()
+
+	testCases := []struct {
+		name          string
+		poster        []byte
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			poster: randomPosterPNG(t, 1000, 1500),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMovie(gomock.Any(), gomock.Eq(movie.ID.Hex())).
+					Times(1).
+					Return(&movie, nil)
+				store.EXPECT().
+					UpdateMoviePoster(gomock.Any(), gomock.Eq(movie.ID.Hex()), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotMovie models.Movie
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &gotMovie))
+				require.True(t, strings.HasPrefix(gotMovie.Poster, posterURLPrefix+movie.ID.Hex()))
+				require.Len(t, gotMovie.PosterSizes, len(posterSizes))
+
+				// the thumbnail is served with cache headers and has the configured width
+				recorder = httptest.NewRecorder()
+				request, err := http.NewRequest(http.MethodGet, gotMovie.PosterSizes["thumbnail"], nil)
+				require.NoError(t, err)
+				server.router.ServeHTTP(recorder, request)
+
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "image/png", recorder.Header().Get("Content-Type"))
+				require.Equal(t, posterCacheControl, recorder.Header().Get("Cache-Control"))
+				require.NotEmpty(t, recorder.Header().Get("ETag"))
+
+				thumbnail, _, err := image.Decode(recorder.Body)
+				require.NoError(t, err)
+				require.Equal(t, 92, thumbnail.Bounds().Dx())
+				require.Equal(t, 138, thumbnail.Bounds().Dy())
+			},
+		},
+		{
+			name:   "NoAuthorization",
+			poster: randomPosterPNG(t, 10, 10),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMovie(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "NotFound",
+			poster: randomPosterPNG(t, 10, 10),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMovie(gomock.Any(), gomock.Eq(movie.ID.Hex())).
+					Times(1).
+					Return(nil, repository.ErrMovieNotFound)
+				store.EXPECT().
+					UpdateMoviePoster(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "NotAdmin",
+			poster: randomPosterPNG(t, 10, 10),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, util.UserRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMovie(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "UnsupportedType",
+			poster: []byte("GIF89a this is not a poster"),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMovie(gomock.Any(), gomock.Eq(movie.ID.Hex())).
+					Times(1).
+					Return(&movie, nil)
+				store.EXPECT().
+					UpdateMoviePoster(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+			},
+		},
+		{
+			name:   "TooLarge",
+			poster: append(randomPosterPNG(t, 10, 10), make([]byte, testConfig().PosterMaxSize)...),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMovie(gomock.Any(), gomock.Eq(movie.ID.Hex())).
+					Times(1).
+					Return(&movie, nil)
+				store.EXPECT().
+					UpdateMoviePoster(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+		{
+			name:   "TooManyPixels",
+			poster: pngWithSize(t, randomPosterPNG(t, 10, 10), 50000, 50000),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetMovie(gomock.Any(), gomock.Eq(movie.ID.Hex())).
+					Times(1).
+					Return(&movie, nil)
+				store.EXPECT().
+					UpdateMoviePoster(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body, contentType := posterForm(t, tc.poster)
+			url := fmt.Sprintf("/movies/%s/poster", movie.ID.Hex())
+			request, err := http.NewRequest(http.MethodPost, url, body)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", contentType)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestGetPosterNotFoundAPI(t *testing.T) {
+	server := newTestServer(t, nil)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/posters/missing.png", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func randomPosterPNG(t *testing.T, width int, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: uint8(util.RandomInt(0, 255)), A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// pngWithSize rewrites the dimensions declared in the header of a PNG
+// without touching its pixel data
+func pngWithSize(t *testing.T, data []byte, width uint32, height uint32) []byte {
+	// the IHDR chunk follows the 8 byte signature: length, type, width, height
+	require.Equal(t, "IHDR", string(data[12:16]))
+	data = bytes.Clone(data)
+	binary.BigEndian.PutUint32(data[16:20], width)
+	binary.BigEndian.PutUint32(data[20:24], height)
+
+	// the checksum covers the chunk type and the 13 bytes of chunk data
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func posterForm(t *testing.T, poster []byte) (io.Reader, string) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile(posterFormField, "poster.png")
+	require.NoError(t, err)
+	_, err = part.Write(poster)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return &body, writer.FormDataContentType()
+}
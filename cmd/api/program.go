@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/util"
+)
+
+// GetProgram godoc
+// @Security bearerAuth
+// @Summary Get the cinema program for a day
+// @Description Get all screenings of a day grouped by hall and sorted by start time, with occupancy. With cinema_id only the screenings of that cinema, and today is the date in its time zone.
+// @ID GetProgram
+// @Accept  json
+// @Produce  json
+// @Param  date query string false "Date (YYYY-MM-DD), defaults to today"
+// @Param  cinema_id query string false "Cinema ID"
+// @Success 200 {array} models.ProgramHall
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Router /program [get]
+func (server *Server) GetProgram(ctx *gin.Context) {
+	dateValue := startOfToday()
+
+	cinemaId := ctx.Query("cinema_id")
+	if cinemaId != "" {
+		cinema, err := server.store.GetCinema(ctx, cinemaId)
+		if err != nil {
+			if errors.Is(err, repository.ErrCinemaNotFound) {
+				ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+			return
+		}
+		dateValue = cinema.Today(time.Now())
+	}
+
+	if date := ctx.Query("date"); date != "" {
+		var err error
+		dateValue, err = util.ParseDate(date)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: "Error parsing date"})
+			return
+		}
+	}
+
+	program, err := server.store.GetProgram(ctx, dateValue, cinemaId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, program)
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/tijanadmi/movieginmongoapi/db/mock"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/token"
+	"github.com/tijanadmi/movieginmongoapi/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetProgramAPI(t *testing.T) {
+	username := util.RandomOwner()
+	role := util.UserRole
+	program := randomProgram()
+	cinema := models.Cinema{ID: primitive.NewObjectID(), Name: "Beograd", TimeZone: "Pacific/Kiritimati"}
+
+	testCases := []struct {
+		name          string
+		date          string
+		cinemaId      string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			date: "2024-07-15",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetProgram(gomock.Any(), gomock.Eq(time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC)), gomock.Eq("")).
+					Times(1).
+					Return(program, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchProgram(t, recorder.Body, program)
+			},
+		},
+		{
+			name: "DefaultToday",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetProgram(gomock.Any(), gomock.Eq(startOfToday()), gomock.Eq("")).
+					Times(1).
+					Return(program, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "CinemaToday",
+			cinemaId: cinema.ID.Hex(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCinema(gomock.Any(), gomock.Eq(cinema.ID.Hex())).
+					Times(1).
+					Return(&cinema, nil)
+				store.EXPECT().
+					GetProgram(gomock.Any(), gomock.Eq(cinema.Today(time.Now())), gomock.Eq(cinema.ID.Hex())).
+					Times(1).
+					Return(program, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "CinemaNotFound",
+			cinemaId: cinema.ID.Hex(),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCinema(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repository.ErrCinemaNotFound)
+				store.EXPECT().
+					GetProgram(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			date: "2024-07-15",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetProgram(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidDate",
+			date: "15.07.2024",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetProgram(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			date: "2024-07-15",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, username, role, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetProgram(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/program", nil)
+			require.NoError(t, err)
+
+			q := request.URL.Query()
+			if tc.date != "" {
+				q.Add("date", tc.date)
+			}
+			if tc.cinemaId != "" {
+				q.Add("cinema_id", tc.cinemaId)
+			}
+			request.URL.RawQuery = q.Encode()
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func randomProgram() []models.ProgramHall {
+	return []models.ProgramHall{
+		{
+			Hall: util.RandomHall(),
+			Screenings: []models.ProgramScreening{
+				{
+					RepertoireID:    primitive.NewObjectID(),
+					MovieID:         primitive.NewObjectID(),
+					MovieTitle:      "Titanik",
+					Duration:        132,
+					Time:            "18:00",
+					NumOfTickets:    50,
+					NumOfResTickets: 10,
+					Occupancy:       0.2,
+				},
+			},
+		},
+	}
+}
+
+func requireBodyMatchProgram(t *testing.T, body *bytes.Buffer, program []models.ProgramHall) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotProgram []models.ProgramHall
+	err = json.Unmarshal(data, &gotProgram)
+	require.NoError(t, err)
+	require.Equal(t, program, gotProgram)
+}
@@ -1,12 +1,14 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/repository"
 	"github.com/tijanadmi/movieginmongoapi/util"
 )
 
@@ -44,6 +46,7 @@ func (server *Server) GetRepertoire(ctx *gin.Context) {
 // @Param  movie_id query string true "Movie ID"
 // @Param  start_date query string true "Start Date"
 // @Param  end_date query string true "End Date"
+// @Param  cinema_id query string false "Only the repertoires of this cinema"
 // @Success 200 {array} models.Repertoire
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
@@ -68,8 +71,12 @@ func (server *Server) GetAllRepertoireForMovie(ctx *gin.Context) {
 		return
 	}
 
-	repertoires, err := server.store.GetAllRepertoireForMovie(ctx, movieId, startDateValue, endDateValue)
+	repertoires, err := server.store.GetAllRepertoireForMovie(ctx, movieId, startDateValue, endDateValue, ctx.Query("cinema_id"))
 	if err != nil {
+		if errors.Is(err, repository.ErrCinemaNotFound) {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
 		return
 	}
@@ -106,10 +113,11 @@ func (server *Server) ListRepertoires(ctx *gin.Context) {
 // @ID AddRepertoire
 // @Accept  json
 // @Produce  json
-// @Param repertoire body models.Repertoire true "Create repertoire"
+// @Param repertoire body models.Repertoire true "Create repertoire, with the hall given by hallId or by name"
 // @Success 201 {array} models.Repertoire
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
 // @Router /repertoires [post]
 func (server *Server) AddRepertoire(ctx *gin.Context) {
 	var repertoire *models.Repertoire
@@ -130,6 +138,10 @@ func (server *Server) AddRepertoire(ctx *gin.Context) {
 
 	repertoire, err = server.store.AddRepertoire(ctx, repertoire)
 	if err != nil {
+		if errors.Is(err, repository.ErrHallNotFound) || errors.Is(err, repository.ErrHallNameAmbiguous) || errors.Is(err, repository.ErrTooManyTickets) {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
 		return
 	}
@@ -149,6 +161,7 @@ func (server *Server) AddRepertoire(ctx *gin.Context) {
 // @Success 200 {array} models.Repertoire
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
 // @Router /repertoires/{id} [put]
 func (server *Server) UpdateRepertoire(ctx *gin.Context) {
 	id := ctx.Param("id")
@@ -159,6 +172,10 @@ func (server *Server) UpdateRepertoire(ctx *gin.Context) {
 	}
 	repertoire, err := server.store.UpdateRepertoire(ctx, id, *repertoire)
 	if err != nil {
+		if errors.Is(err, repository.ErrHallNotFound) || errors.Is(err, repository.ErrHallNameAmbiguous) {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
 		return
 	}
@@ -169,47 +186,65 @@ func (server *Server) UpdateRepertoire(ctx *gin.Context) {
 // DeleteRepertoire godoc
 // @Security bearerAuth
 // @Summary Delete a single repertoire
-// @Description Delete a single repertoire
+// @Description Delete a single repertoire. If the repertoire has upcoming reservations, it is only deleted with cascade, which cancels them and notifies the affected users.
 // @ID DeleteRepertoire
 // @Accept  json
 // @Produce  json
 // @Param  id path string true "repertoire ID"
+// @Param  cascade query bool false "Also cancel the dependents"
 // @Success 200 {array} apiResponse
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Failure 409 {object} dependentsErrorResponse
 // @Router /repertoires/{id} [delete]
 func (server *Server) DeleteRepertoire(ctx *gin.Context) {
-	id := ctx.Param("id")
+	server.deleteRecord(ctx, ctx.Param("id"), server.store.DeleteRepertoire, repository.ErrRepertoireNotFound, "repertoire has been deleted")
+}
 
-	err := server.store.DeleteRepertoire(ctx, id)
+// RestoreRepertoire godoc
+// @Security bearerAuth
+// @Summary Restore a deleted repertoire
+// @Description Restore a soft-deleted repertoire (admin only)
+// @ID RestoreRepertoire
+// @Produce  json
+// @Param  id path string true "repertoire ID"
+// @Success 200 {object} apiResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Router /repertoires/{id}/restore [put]
+func (server *Server) RestoreRepertoire(ctx *gin.Context) {
+	err := server.store.RestoreRepertoire(ctx, ctx.Param("id"))
 	if err != nil {
+		if errors.Is(err, repository.ErrRepertoireNotFound) {
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, apiResponse{Message: fmt.Sprintf("repertoire has been deleted")})
+	ctx.JSON(http.StatusOK, apiResponse{Message: "repertoire has been restored"})
 }
 
 // DeleteRepertoireForMovie godoc
 // @Security bearerAuth
 // @Summary Delete all repertoires for the movie
-// @Description Delete all repertoires for the movie
+// @Description Delete all repertoires for the movie. If they have upcoming reservations, they are only deleted with cascade, which cancels them and notifies the affected users.
 // @ID DeleteRepertoireForMovie
 // @Accept  json
 // @Produce  json
 // @Param  movie_id query string true "movie ID"
+// @Param  cascade query bool false "Also cancel the dependents"
 // @Success 200 {array} apiResponse
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Failure 409 {object} dependentsErrorResponse
 // @Router /repertoires/movie [delete]
 func (server *Server) DeleteRepertoireForMovie(ctx *gin.Context) {
-	movieId := ctx.Query("movie_id")
-
-	err := server.store.DeleteRepertoireForMovie(ctx, movieId)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
-		return
-	}
-
-	ctx.JSON(http.StatusOK, apiResponse{Message: fmt.Sprintf("repertoire has been deleted")})
+	server.deleteRecord(ctx, ctx.Query("movie_id"), server.store.DeleteRepertoireForMovie, repository.ErrRepertoireNotFound, "repertoire has been deleted")
 }
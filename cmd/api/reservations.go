@@ -1,10 +1,12 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/token"
 	"github.com/tijanadmi/movieginmongoapi/util"
 )
 
@@ -36,7 +38,7 @@ func (server *Server) GetAllReservationsForUser(ctx *gin.Context) {
 // AddReservation godoc
 // @Security bearerAuth
 // @Summary Insert new reservation
-// @Description Insert new reservation
+// @Description Insert new reservation for the logged-in user; admins can book for the user named in the body
 // @ID AddReservation
 // @Accept  json
 // @Produce  json
@@ -44,6 +46,8 @@ func (server *Server) GetAllReservationsForUser(ctx *gin.Context) {
 // @Success 201 {array} models.Reservation
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 409 {object} apiErrorResponse
 // @Router /reservation [post]
 func (server *Server) AddReservation(ctx *gin.Context) {
 	var req reservationRequest
@@ -58,17 +62,48 @@ func (server *Server) AddReservation(ctx *gin.Context) {
 		return
 	}
 
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if req.AgeOverride && authPayload.Role != util.AdminRole {
+		ctx.JSON(http.StatusForbidden, apiErrorResponse{Error: "only admins can override age restrictions"})
+		return
+	}
+	// the age restriction is checked for the user the seats are booked for
+	username := authPayload.Username
+	if req.Username != "" && req.Username != authPayload.Username {
+		if authPayload.Role != util.AdminRole {
+			ctx.JSON(http.StatusForbidden, apiErrorResponse{Error: "only admins can book for another user"})
+			return
+		}
+		username = req.Username
+	}
+
 	req1 := repository.AddReservationParams{
-		Username:    req.Username,
+		Username:    username,
 		MovieID:     req.MovieID,
 		Date:        dateValue,
 		Time:        req.Time,
+		HallID:      req.HallID,
 		Hall:        req.Hall,
 		ReservSeats: req.ReservSeats,
+		AgeOverride: req.AgeOverride,
 	}
 	_, err = server.store.AddReservation(ctx, req1)
 
 	if err != nil {
+		if errors.Is(err, repository.ErrAgeRestricted) {
+			ctx.JSON(http.StatusForbidden, apiErrorResponse{Error: err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrHallNotFound) || errors.Is(err, repository.ErrUnknownSeat) {
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrSeatBlocked) || errors.Is(err, repository.ErrSeatReserved) ||
+			errors.Is(err, repository.ErrSeatHeld) || errors.Is(err, repository.ErrNotEnoughTickets) {
+			ctx.JSON(http.StatusConflict, apiErrorResponse{Error: err.Error()})
+			return
+		}
+
 		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
 		return
 	}
@@ -101,3 +136,34 @@ func (server *Server) CancelReservation(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, apiResponse{Message: "Reservation canceled successfully"})
 
 }
+
+// CheckInReservation godoc
+// @Security bearerAuth
+// @Summary Check in a reservation
+// @Description Mark a reservation as checked in at the cinema (admin only)
+// @ID CheckInReservation
+// @Accept  json
+// @Produce  json
+// @Param  id path string true "reservation ID"
+// @Success 200 {object} apiResponse
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Router /reservation/{id}/checkin [put]
+func (server *Server) CheckInReservation(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	err := server.store.CheckInReservation(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrReservationNotFound) {
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apiResponse{Message: "Reservation checked in successfully"})
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/token"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type reviewRequest struct {
+	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment string `json:"comment" binding:"max=2000"`
+}
+
+type reviewStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=published hidden"`
+}
+
+// AddReview godoc
+// @Security bearerAuth
+// @Summary Review a movie
+// @Description Rate and review a movie; only users with a checked-in or past reservation for the movie may review it
+// @ID AddReview
+// @Accept  json
+// @Produce  json
+// @Param  id path string true "Movie ID"
+// @Param review body reviewRequest true "Review"
+// @Success 201 {object} models.Review
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 409 {object} apiErrorResponse
+// @Router /movies/{id}/reviews [post]
+func (server *Server) AddReview(ctx *gin.Context) {
+	movieID, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: "invalid movie id"})
+		return
+	}
+
+	var req reviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUserByUsername(ctx, authPayload.Username)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	review, err := server.store.AddReview(ctx, &models.Review{
+		MovieID:  movieID,
+		UserID:   user.ID,
+		Username: user.Username,
+		Rating:   req.Rating,
+		Comment:  req.Comment,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrReviewNotAllowed):
+			ctx.JSON(http.StatusForbidden, apiErrorResponse{Error: err.Error()})
+		case errors.Is(err, repository.ErrReviewExists):
+			ctx.JSON(http.StatusConflict, apiErrorResponse{Error: err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, review)
+}
+
+// ListReviewsForMovie godoc
+// @Security bearerAuth
+// @Summary List reviews of a movie
+// @Description Get the published reviews of a movie, newest first
+// @ID ListReviewsForMovie
+// @Accept  json
+// @Produce  json
+// @Param  id path string true "Movie ID"
+// @Success 200 {array} models.Review
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Router /movies/{id}/reviews [get]
+func (server *Server) ListReviewsForMovie(ctx *gin.Context) {
+	reviews, err := server.store.ListReviewsForMovie(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reviews)
+}
+
+// ListReviews godoc
+// @Security bearerAuth
+// @Summary List reviews for moderation
+// @Description Get all reviews, optionally only those with the given status (admin only)
+// @ID ListReviews
+// @Accept  json
+// @Produce  json
+// @Param  status query string false "published or hidden"
+// @Success 200 {array} models.Review
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Router /reviews [get]
+func (server *Server) ListReviews(ctx *gin.Context) {
+	status := ctx.Query("status")
+	if status != "" && status != models.ReviewStatusPublished && status != models.ReviewStatusHidden {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: "status must be published or hidden"})
+		return
+	}
+
+	reviews, err := server.store.ListReviews(ctx, status)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reviews)
+}
+
+// ModerateReview godoc
+// @Security bearerAuth
+// @Summary Publish or hide a review
+// @Description Change the status of a review (admin only)
+// @ID ModerateReview
+// @Accept  json
+// @Produce  json
+// @Param  id path string true "Review ID"
+// @Param status body reviewStatusRequest true "New status"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Router /reviews/{id}/status [put]
+func (server *Server) ModerateReview(ctx *gin.Context) {
+	var req reviewStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	review, err := server.store.SetReviewStatus(ctx, ctx.Param("id"), req.Status, authPayload.Username)
+	if err != nil {
+		if errors.Is(err, repository.ErrReviewNotFound) {
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, review)
+}
+
+// DeleteReview godoc
+// @Security bearerAuth
+// @Summary Delete a review
+// @Description Delete a single review (admin only)
+// @ID DeleteReview
+// @Accept  json
+// @Produce  json
+// @Param  id path string true "Review ID"
+// @Success 200 {object} apiResponse
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Router /reviews/{id} [delete]
+func (server *Server) DeleteReview(ctx *gin.Context) {
+	err := server.store.DeleteReview(ctx, ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrReviewNotFound) {
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+			return
+		}
+
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apiResponse{Message: "Review has been deleted"})
+}
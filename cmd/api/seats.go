@@ -0,0 +1,146 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/token"
+)
+
+type blockSeatsRequest struct {
+	Seats  []string `json:"seats" binding:"required,min=1,dive,required"`
+	Reason string   `json:"reason" binding:"required"`
+}
+
+type unblockSeatsRequest struct {
+	Seats []string `json:"seats" binding:"required,min=1,dive,required"`
+}
+
+type bestSeatsRequest struct {
+	PartySize int  `json:"partySize" binding:"required,min=1,max=10"`
+	Hold      bool `json:"hold"`
+}
+
+// BlockSeats godoc
+// @Security bearerAuth
+// @Summary Block seats of a repertoire
+// @Description Take seats of a single screening out of sale, e.g. broken seats or distancing (admin only)
+// @ID BlockSeats
+// @Accept  json
+// @Produce  json
+// @Param  id path string true "repertoire ID"
+// @Param  seats body blockSeatsRequest true "Seats and the reason"
+// @Success 200 {object} models.Repertoire
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Failure 409 {object} apiErrorResponse
+// @Router /repertoires/{id}/blocked-seats [post]
+func (server *Server) BlockSeats(ctx *gin.Context) {
+	var req blockSeatsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	repertoire, err := server.store.BlockSeats(ctx, ctx.Param("id"), req.Seats, req.Reason, authPayload.Username)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRepertoireNotFound):
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+		case errors.Is(err, repository.ErrUnknownSeat):
+			ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		case errors.Is(err, repository.ErrSeatReserved):
+			ctx.JSON(http.StatusConflict, apiErrorResponse{Error: err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, repertoire)
+}
+
+// UnblockSeats godoc
+// @Security bearerAuth
+// @Summary Unblock seats of a repertoire
+// @Description Put blocked seats of a single screening back on sale (admin only)
+// @ID UnblockSeats
+// @Accept  json
+// @Produce  json
+// @Param  id path string true "repertoire ID"
+// @Param  seats body unblockSeatsRequest true "Seats to unblock"
+// @Success 200 {object} models.Repertoire
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 403 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Router /repertoires/{id}/blocked-seats [delete]
+func (server *Server) UnblockSeats(ctx *gin.Context) {
+	var req unblockSeatsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	repertoire, err := server.store.UnblockSeats(ctx, ctx.Param("id"), req.Seats)
+	if err != nil {
+		if errors.Is(err, repository.ErrRepertoireNotFound) {
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, repertoire)
+}
+
+// FindBestSeats godoc
+// @Security bearerAuth
+// @Summary Propose the best available seats
+// @Description Propose adjacent free seats for a party, as close to the center of the hall as possible and without leaving single free seats. With hold the seats are kept for the user for SEAT_HOLD_DURATION.
+// @ID FindBestSeats
+// @Accept  json
+// @Produce  json
+// @Param  id path string true "repertoire ID"
+// @Param  party body bestSeatsRequest true "Party size and whether to hold the seats"
+// @Success 200 {object} models.SeatProposal
+// @Failure 400 {object} apiErrorResponse
+// @Failure 401 {object} apiErrorResponse
+// @Failure 404 {object} apiErrorResponse
+// @Failure 409 {object} apiErrorResponse
+// @Router /repertoires/{id}/best-seats [post]
+func (server *Server) FindBestSeats(ctx *gin.Context) {
+	var req bestSeatsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, apiErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var holdUntil time.Time
+	if req.Hold {
+		holdUntil = time.Now().Add(server.config.SeatHoldDuration).UTC()
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	proposal, err := server.store.FindBestSeats(ctx, ctx.Param("id"), req.PartySize, authPayload.Username, holdUntil)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRepertoireNotFound):
+			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: err.Error()})
+		case errors.Is(err, repository.ErrNotEnoughTickets), errors.Is(err, repository.ErrNoAdjacentSeats):
+			ctx.JSON(http.StatusConflict, apiErrorResponse{Error: err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, proposal)
+}
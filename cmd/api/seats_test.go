@@ -0,0 +1,398 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/tijanadmi/movieginmongoapi/db/mock"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/token"
+	"github.com/tijanadmi/movieginmongoapi/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBlockSeatsAPI(t *testing.T) {
+	admin := util.RandomOwner()
+	repertoire := models.Repertoire{
+		ID:           primitive.NewObjectID(),
+		CinemaID:     primitive.NewObjectID(),
+		NumOfTickets: 10,
+		BlockedSeats: []models.BlockedSeat{{Seat: "A1", Reason: "broken", BlockedBy: admin}},
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"seats": []string{"A1"}, "reason": "broken"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockSeats(gomock.Any(), gomock.Eq(repertoire.ID.Hex()), gomock.Eq([]string{"A1"}), gomock.Eq("broken"), gomock.Eq(admin)).
+					Times(1).
+					Return(&repertoire, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.Repertoire
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, repertoire.BlockedSeats, got.BlockedSeats)
+			},
+		},
+		{
+			name: "CinemaAdmin",
+			body: gin.H{"seats": []string{"A1"}, "reason": "broken"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.UserRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				user := &models.User{
+					Username: admin,
+					Roles:    []string{util.UserRole, util.CinemaRole(util.CinemaAdminRole, repertoire.CinemaID.Hex())},
+				}
+				store.EXPECT().
+					GetRepertoire(gomock.Any(), gomock.Eq(repertoire.ID.Hex())).
+					Times(1).
+					Return(&repertoire, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq(admin)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					BlockSeats(gomock.Any(), gomock.Eq(repertoire.ID.Hex()), gomock.Eq([]string{"A1"}), gomock.Eq("broken"), gomock.Eq(admin)).
+					Times(1).
+					Return(&repertoire, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			body: gin.H{"seats": []string{"A1"}, "reason": "broken"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.UserRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				user := &models.User{
+					Username: admin,
+					Roles:    []string{util.UserRole, util.CinemaRole(util.CinemaAdminRole, primitive.NewObjectID().Hex())},
+				}
+				store.EXPECT().
+					GetRepertoire(gomock.Any(), gomock.Eq(repertoire.ID.Hex())).
+					Times(1).
+					Return(&repertoire, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq(admin)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					BlockSeats(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NoReason",
+			body: gin.H{"seats": []string{"A1"}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockSeats(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownSeat",
+			body: gin.H{"seats": []string{"Z9"}, "reason": "broken"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockSeats(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, fmt.Errorf("%w: Z9", repository.ErrUnknownSeat))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchErrorResponse(t, recorder.Body, apiErrorResponse{Error: "seat is not sellable in this hall: Z9"})
+			},
+		},
+		{
+			name: "SeatReserved",
+			body: gin.H{"seats": []string{"A2"}, "reason": "distancing"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockSeats(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, fmt.Errorf("%w: A2", repository.ErrSeatReserved))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"seats": []string{"A1"}, "reason": "broken"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BlockSeats(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repository.ErrRepertoireNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/repertoires/" + repertoire.ID.Hex() + "/blocked-seats"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUnblockSeatsAPI(t *testing.T) {
+	admin := util.RandomOwner()
+	repertoire := models.Repertoire{ID: primitive.NewObjectID(), NumOfTickets: 10}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"seats": []string{"A1", "A2"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UnblockSeats(gomock.Any(), gomock.Eq(repertoire.ID.Hex()), gomock.Eq([]string{"A1", "A2"})).
+					Times(1).
+					Return(&repertoire, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NoSeats",
+			body: gin.H{"seats": []string{}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UnblockSeats(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"seats": []string{"A1"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UnblockSeats(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, repository.ErrRepertoireNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/repertoires/" + repertoire.ID.Hex() + "/blocked-seats"
+			request, err := http.NewRequest(http.MethodDelete, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestFindBestSeatsAPI(t *testing.T) {
+	username := util.RandomOwner()
+	repertoireID := primitive.NewObjectID()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Propose",
+			body: gin.H{"partySize": 2},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					FindBestSeats(gomock.Any(), gomock.Eq(repertoireID.Hex()), gomock.Eq(2), gomock.Eq(username), gomock.Eq(time.Time{})).
+					Times(1).
+					Return(models.SeatProposal{RepertoireID: repertoireID, Seats: []string{"C4", "C5"}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.SeatProposal
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, []string{"C4", "C5"}, got.Seats)
+				require.Nil(t, got.HeldUntil)
+			},
+		},
+		{
+			name: "Hold",
+			body: gin.H{"partySize": 2, "hold": true},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					FindBestSeats(gomock.Any(), gomock.Eq(repertoireID.Hex()), gomock.Eq(2), gomock.Eq(username), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, id string, party int, username string, holdUntil time.Time) (models.SeatProposal, error) {
+						require.WithinDuration(t, time.Now().Add(10*time.Minute), holdUntil, time.Minute)
+						return models.SeatProposal{RepertoireID: repertoireID, Seats: []string{"C4", "C5"}, HeldUntil: &holdUntil}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got models.SeatProposal
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.NotNil(t, got.HeldUntil)
+			},
+		},
+		{
+			name: "InvalidPartySize",
+			body: gin.H{"partySize": 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					FindBestSeats(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoAdjacentSeats",
+			body: gin.H{"partySize": 6},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					FindBestSeats(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(models.SeatProposal{}, repository.ErrNoAdjacentSeats)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{"partySize": 2},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					FindBestSeats(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(models.SeatProposal{}, repository.ErrRepertoireNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/repertoires/" + repertoireID.Hex() + "/best-seats"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, username, util.UserRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/tijanadmi/movieginmongoapi/util"
+)
+
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	defaultCORSHeaders = []string{"Origin", "Content-Type", "Authorization", requestIDHeader}
+)
+
+// corsMiddleware allows the origins, methods and headers of the config.
+// Without origins every origin is allowed, and then no credentials are: a
+// browser must not send cookies of any site to the API. Credentials are only
+// allowed for origins listed by name.
+func corsMiddleware(config util.Config) (gin.HandlerFunc, error) {
+	corsConfig := cors.Config{
+		AllowOrigins:  config.CORSAllowedOrigins,
+		AllowMethods:  config.CORSAllowedMethods,
+		AllowHeaders:  config.CORSAllowedHeaders,
+		ExposeHeaders: []string{"Content-Length", requestIDHeader, "Retry-After"},
+	}
+	if len(corsConfig.AllowMethods) == 0 {
+		corsConfig.AllowMethods = defaultCORSMethods
+	}
+	if len(corsConfig.AllowHeaders) == 0 {
+		corsConfig.AllowHeaders = defaultCORSHeaders
+		if config.TenantHeader != "" {
+			corsConfig.AllowHeaders = append(slices.Clone(defaultCORSHeaders), config.TenantHeader)
+		}
+	}
+
+	if len(corsConfig.AllowOrigins) == 0 || slices.Contains(corsConfig.AllowOrigins, "*") {
+		corsConfig.AllowOrigins = nil
+		corsConfig.AllowAllOrigins = true
+	} else {
+		corsConfig.AllowCredentials = true
+	}
+
+	if err := corsConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid CORS settings: %w", err)
+	}
+	return cors.New(corsConfig), nil
+}
+
+// securityHeadersMiddleware keeps browsers from sniffing content types and
+// from framing the API. Responses over HTTPS, served directly or behind one of
+// the trusted proxies, also tell the browser to use only HTTPS for maxAge.
+// X-Forwarded-Proto of other clients is ignored, as they could set it on a
+// plain HTTP request.
+func securityHeadersMiddleware(maxAge time.Duration, trustedProxies []string) (gin.HandlerFunc, error) {
+	hsts := "max-age=" + strconv.Itoa(int(maxAge.Seconds())) + "; includeSubDomains"
+
+	proxies := make([]*net.IPNet, 0, len(trustedProxies))
+	for _, proxy := range trustedProxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		proxies = append(proxies, network)
+	}
+	fromTrustedProxy := func(ctx *gin.Context) bool {
+		ip := net.ParseIP(ctx.RemoteIP())
+		for _, network := range proxies {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(ctx *gin.Context) {
+		header := ctx.Writer.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("Referrer-Policy", "no-referrer")
+		https := ctx.Request.TLS != nil
+		if !https && ctx.GetHeader("X-Forwarded-Proto") == "https" {
+			https = fromTrustedProxy(ctx)
+		}
+		if https {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+		ctx.Next()
+	}, nil
+}
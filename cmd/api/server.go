@@ -1,16 +1,28 @@
 package api
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 
 	swaggerFiles "github.com/swaggo/files"     // swagger embed files
 	ginSwagger "github.com/swaggo/gin-swagger" // gin-swagger middleware
+	"github.com/tijanadmi/movieginmongoapi/metrics"
+	"github.com/tijanadmi/movieginmongoapi/ratelimit"
 	db "github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/storage"
 	"github.com/tijanadmi/movieginmongoapi/token"
+	"github.com/tijanadmi/movieginmongoapi/tracing"
 	"github.com/tijanadmi/movieginmongoapi/util"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // Server serves HTTP requests for our banking service.
@@ -18,11 +30,19 @@ type Server struct {
 	config     util.Config
 	store      db.Store
 	tokenMaker token.Maker
+	posters    storage.Storage
 	router     *gin.Engine
+	httpServer *http.Server
+	loginStore ratelimit.Store
+	loginGuard *ratelimit.LoginGuard
+
+	certificates *certificateReloader
+
+	readinessChecks []readinessCheck
 }
 
 // NewServer creates a new HTTP server and set up routing.
-func NewServer(config util.Config, store db.Store) (*Server, error) {
+func NewServer(config util.Config, store db.Store, posters storage.Storage) (*Server, error) {
 	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
@@ -32,33 +52,110 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 		config:     config,
 		store:      store,
 		tokenMaker: tokenMaker,
+		posters:    posters,
 	}
+	server.setupLoginGuard()
 	/*if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validCurrency)
 	}*/
 
-	server.setupRouter()
+	server.AddReadinessCheck("mongo", server.checkMongo)
+	server.AddReadinessCheck("migrations", server.checkMigrations)
+
+	if err := server.setupRouter(); err != nil {
+		return nil, err
+	}
+	if err := server.setupHTTPServer(); err != nil {
+		return nil, err
+	}
 	return server, nil
 }
 
-func (server *Server) setupRouter() {
-	router := gin.Default()
+// setupHTTPServer creates the http.Server with the timeouts of the config.
+// With TLS_CERT_FILE and TLS_KEY_FILE the server serves HTTPS.
+func (server *Server) setupHTTPServer() error {
+	server.httpServer = &http.Server{
+		Handler:           server.router,
+		ReadHeaderTimeout: server.config.HTTPReadTimeout,
+		ReadTimeout:       server.config.HTTPReadTimeout,
+		WriteTimeout:      server.config.HTTPWriteTimeout,
+		IdleTimeout:       server.config.HTTPIdleTimeout,
+	}
 
-	// Configure CORS
-	router.Use(cors.New(cors.Config{
-		//AllowOrigins:     []string{"http://localhost:3000"},
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-	}))
+	if server.config.TLSCertFile == "" && server.config.TLSKeyFile == "" {
+		return nil
+	}
+	if server.config.TLSCertFile == "" || server.config.TLSKeyFile == "" {
+		return errors.New("TLS needs both TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+	certificates, err := newCertificateReloader(server.config.TLSCertFile, server.config.TLSKeyFile, certificateCheckInterval)
+	if err != nil {
+		return err
+	}
+	server.certificates = certificates
+	server.httpServer.TLSConfig = &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certificates.GetCertificate,
+	}
+	return nil
+}
+
+// setupLoginGuard limits the login attempts in Redis when REDIS_ADDRESS is
+// set, so all instances share the limits, and in memory otherwise
+func (server *Server) setupLoginGuard() {
+	server.loginStore = ratelimit.NewMemoryStore()
+	if server.config.RedisAddress != "" {
+		server.loginStore = ratelimit.NewRedisStore(redis.NewClient(&redis.Options{Addr: server.config.RedisAddress}))
+	}
+
+	server.loginGuard = ratelimit.NewLoginGuard(server.loginStore, ratelimit.Limits{
+		PerIP:       server.config.LoginAttemptsPerIP,
+		PerUser:     server.config.LoginAttemptsPerUser,
+		MaxFailures: server.config.LoginMaxFailures,
+		Lockout:     server.config.LoginLockout,
+	})
+}
+
+func (server *Server) setupRouter() error {
+	cors, err := corsMiddleware(server.config)
+	if err != nil {
+		return err
+	}
+	securityHeaders, err := securityHeadersMiddleware(server.config.HSTSMaxAge, server.config.TrustedProxies)
+	if err != nil {
+		return err
+	}
+
+	router := gin.New()
+	// X-Forwarded-For decides the client IP of rate limits and logs only
+	// when it is set by a trusted proxy; by default no proxy is trusted
+	if err := router.SetTrustedProxies(server.config.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	router.Use(otelgin.Middleware(tracing.ServiceName, otelgin.WithFilter(traced)))
+	router.Use(requestIDMiddleware())
+	// probes and scrapes would drown the request log
+	router.Use(accessLogMiddleware("/healthz", "/readyz", "/metrics"), recoveryMiddleware())
+	router.Use(metrics.Middleware())
+	// store queries read the tenant from the request context
+	router.ContextWithFallback = true
+
+	router.Use(securityHeaders, cors)
+
+	// probes and scrapes come from the infrastructure, not from a tenant or a user
+	router.GET("/healthz", server.healthz)
+	router.GET("/readyz", server.readyz)
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	router.Use(tenantMiddleware(server.config.Tenants, server.config.TenantHeader))
 
 	router.POST("/users/login", server.loginUser)
 	router.POST("/users", server.InsertUser)
 
 	router.POST("/tokens/renew_access", server.renewAccessToken)
 
+	router.GET("/posters/:name", server.getPoster)
+
 	// router.GET("/halls/:id", server.getHallById)
 	// router.GET("/halls", server.listHalls)
 	// router.POST("/halls", server.InsertHall)
@@ -67,13 +164,18 @@ func (server *Server) setupRouter() {
 	// router.GET("/searchhalls/:name", server.searchHall)
 	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
 
+	authRoutes.GET("/cinemas", server.ListCinemas)
+	authRoutes.GET("/cinemas/:id", server.GetCinema)
+
 	authRoutes.GET("/halls/:id", server.getHallById)
 	authRoutes.GET("/halls", server.listHalls)
-	authRoutes.POST("/halls", server.InsertHall)
-	authRoutes.PUT("/halls/:id", server.UpdateHall)
-	authRoutes.DELETE("/halls/:id", server.DeleteHall)
 	authRoutes.GET("/searchhalls/:name", server.searchHall)
 
+	authRoutes.GET("/movies/search", server.searchMoviesText)
+	authRoutes.GET("/movies/now-showing", server.listNowShowing)
+	authRoutes.GET("/movies/coming-soon", server.listComingSoon)
+	authRoutes.GET("/movies/genre/:genre", server.listMoviesByGenre)
+	authRoutes.GET("/movies/person/:name", server.listMoviesByPerson)
 	authRoutes.GET("/movies/:id", server.searchMovies)
 	authRoutes.GET("/movies", server.listMovies)
 	authRoutes.PUT("/movies/:id", server.UpdateMovie)
@@ -83,22 +185,111 @@ func (server *Server) setupRouter() {
 	authRoutes.GET("/repertoires/:id", server.GetRepertoire)
 	authRoutes.GET("/repertoires/movie", server.GetAllRepertoireForMovie)
 	authRoutes.GET("/repertoires", server.ListRepertoires)
-	authRoutes.PUT("/repertoires/:id", server.UpdateRepertoire)
-	authRoutes.POST("/repertoires", server.AddRepertoire)
-	authRoutes.DELETE("/repertoires/:id", server.DeleteRepertoire)
-	authRoutes.DELETE("/repertoires/movie", server.DeleteRepertoireForMovie)
+	authRoutes.POST("/repertoires/:id/best-seats", server.FindBestSeats)
+
+	authRoutes.GET("/program", server.GetProgram)
 
 	authRoutes.POST("/reservation", server.AddReservation)
 	authRoutes.DELETE("/reservation/:id", server.CancelReservation)
 	authRoutes.GET("/reservationforuser", server.GetAllReservationsForUser)
+	authRoutes.GET("/notifications", server.ListNotifications)
+
+	authRoutes.POST("/movies/:id/reviews", server.AddReview)
+	authRoutes.GET("/movies/:id/reviews", server.ListReviewsForMovie)
+
+	adminRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker), adminMiddleware())
+
+	adminRoutes.PUT("/reservation/:id/checkin", server.CheckInReservation)
+
+	adminRoutes.GET("/reviews", server.ListReviews)
+	adminRoutes.PUT("/reviews/:id/status", server.ModerateReview)
+	adminRoutes.DELETE("/reviews/:id", server.DeleteReview)
+
+	adminRoutes.POST("/movies/:id/poster", server.uploadMoviePoster)
+	adminRoutes.PUT("/movies/:id/restore", server.RestoreMovie)
+	adminRoutes.PUT("/halls/:id/restore", server.RestoreHall)
+	adminRoutes.PUT("/repertoires/:id/restore", server.RestoreRepertoire)
+	adminRoutes.DELETE("/repertoires/movie", server.DeleteRepertoireForMovie)
+
+	adminRoutes.POST("/cinemas", server.AddCinema)
+	adminRoutes.DELETE("/cinemas/:id", server.DeleteCinema)
+	adminRoutes.PUT("/cinemas/:id/admins/:username", server.AddCinemaAdmin)
+	adminRoutes.DELETE("/cinemas/:id/admins/:username", server.RemoveCinemaAdmin)
+
+	adminRoutes.POST("/import/:kind", server.importRecords)
+	adminRoutes.GET("/export/:kind", server.exportRecords)
+
+	// admins of a cinema manage the cinema, its halls and the screenings in its halls
+	cinemaAdminRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker), cinemaAdminMiddleware(server.store, cinemaFromParam))
+
+	cinemaAdminRoutes.PUT("/cinemas/:id", server.UpdateCinema)
+
+	hallAdminRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker), cinemaAdminMiddleware(server.store, cinemaOfHall(server.store)))
+
+	hallAdminRoutes.PUT("/halls/:id", server.UpdateHall)
+	hallAdminRoutes.DELETE("/halls/:id", server.DeleteHall)
+
+	repertoireAdminRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker), cinemaAdminMiddleware(server.store, cinemaOfRepertoire(server.store)))
+
+	repertoireAdminRoutes.DELETE("/repertoires/:id", server.DeleteRepertoire)
+	repertoireAdminRoutes.POST("/repertoires/:id/blocked-seats", server.BlockSeats)
+	repertoireAdminRoutes.DELETE("/repertoires/:id/blocked-seats", server.UnblockSeats)
+
+	authRoutes.POST("/halls", cinemaAdminMiddleware(server.store, cinemaOfHallBody), server.InsertHall)
+	authRoutes.POST("/repertoires", cinemaAdminMiddleware(server.store, cinemaOfRepertoireHall(server.store)), server.AddRepertoire)
+	authRoutes.PUT("/repertoires/:id", cinemaAdminMiddleware(server.store, cinemaOfRepertoireUpdate(server.store)), server.UpdateRepertoire)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	server.router = router
+	return nil
 }
 
-// Start runs the HTTP server on a specific address.
+// Start runs the HTTP server on a specific address until Shutdown is called.
 func (server *Server) Start(address string) error {
-	return server.router.Run(address)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	return server.Serve(listener)
+}
+
+// Serve runs the HTTP server on listener until Shutdown is called.
+func (server *Server) Serve(listener net.Listener) error {
+	var err error
+	if server.httpServer.TLSConfig != nil {
+		err = server.httpServer.ServeTLS(listener, "", "")
+	} else {
+		err = server.httpServer.Serve(listener)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops accepting connections and waits for the requests in flight
+// to finish. Connections still open when ctx is done are closed.
+func (server *Server) Shutdown(ctx context.Context) error {
+	err := server.httpServer.Shutdown(ctx)
+	if err != nil {
+		server.httpServer.Close()
+	}
+	if closer, ok := server.loginStore.(io.Closer); ok {
+		closer.Close()
+	}
+	if server.certificates != nil {
+		server.certificates.Stop()
+	}
+	return err
+}
+
+// traced reports whether a request is traced; probes and scrapes are not
+func traced(request *http.Request) bool {
+	switch request.URL.Path {
+	case "/healthz", "/readyz", "/metrics":
+		return false
+	}
+	return true
 }
 
 func errorResponse(err error) gin.H {
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/tijanadmi/movieginmongoapi/storage"
+)
+
+func TestServerShutdownDrainsRequests(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	started := make(chan struct{})
+	server.router.GET("/slow", func(ctx *gin.Context) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		ctx.String(http.StatusOK, "done")
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	url := "http://" + listener.Addr().String()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	type result struct {
+		body string
+		err  error
+	}
+	response := make(chan result, 1)
+	go func() {
+		rsp, err := http.Get(url + "/slow")
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		defer rsp.Body.Close()
+		body, err := io.ReadAll(rsp.Body)
+		response <- result{body: string(body), err: err}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, server.Shutdown(ctx))
+
+	// the request in flight finishes, new connections are refused
+	got := <-response
+	require.NoError(t, got.err)
+	require.Equal(t, "done", got.body)
+	require.NoError(t, <-serveErr)
+
+	_, err = http.Get(url + "/slow")
+	require.Error(t, err)
+}
+
+func TestServerShutdownDeadline(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	server.router.GET("/stuck", func(ctx *gin.Context) {
+		close(started)
+		<-release
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(listener)
+
+	go http.Get("http://" + listener.Addr().String() + "/stuck")
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, server.Shutdown(ctx), context.DeadlineExceeded)
+}
+
+func TestServerTimeouts(t *testing.T) {
+	config := testConfig()
+	config.HTTPReadTimeout = 15 * time.Second
+	config.HTTPWriteTimeout = time.Minute
+	config.HTTPIdleTimeout = 2 * time.Minute
+	posters, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	server, err := NewServer(config, nil, posters)
+	require.NoError(t, err)
+	require.Equal(t, 15*time.Second, server.httpServer.ReadHeaderTimeout)
+	require.Equal(t, 15*time.Second, server.httpServer.ReadTimeout)
+	require.Equal(t, time.Minute, server.httpServer.WriteTimeout)
+	require.Equal(t, 2*time.Minute, server.httpServer.IdleTimeout)
+}
+
+func TestServerTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeCertificate(t, certFile, keyFile, "first", time.Now())
+
+	config := testConfig()
+	config.TLSCertFile = certFile
+	config.TLSKeyFile = keyFile
+	posters, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	server, err := NewServer(config, nil, posters)
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(listener)
+	defer server.Shutdown(context.Background())
+
+	client := &http.Client{Transport: &http.Transport{
+		// the test checks which certificate is served, not who signed it
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		DisableKeepAlives: true,
+	}}
+	servedCertificate := func() string {
+		rsp, err := client.Get("https://" + listener.Addr().String() + "/healthz")
+		require.NoError(t, err)
+		defer rsp.Body.Close()
+
+		require.Equal(t, http.StatusOK, rsp.StatusCode)
+		require.NotEmpty(t, rsp.Header.Get("Strict-Transport-Security"))
+		return rsp.TLS.PeerCertificates[0].Subject.CommonName
+	}
+	require.Equal(t, "first", servedCertificate())
+
+	// a renewed certificate is served without a restart
+	writeCertificate(t, certFile, keyFile, "second", time.Now().Add(time.Minute))
+	require.Equal(t, "first", servedCertificate())
+	server.certificates.reload()
+	require.Equal(t, "second", servedCertificate())
+}
+
+func TestServerTLSMissingKey(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	writeCertificate(t, certFile, filepath.Join(dir, "key.pem"), "first", time.Now())
+
+	config := testConfig()
+	config.TLSCertFile = certFile
+	config.TLSKeyFile = filepath.Join(dir, "missing.pem")
+	posters, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = NewServer(config, nil, posters)
+	require.Error(t, err)
+}
+
+// writeCertificate writes a self-signed certificate for commonName and its
+// key, both modified at modTime
+func writeCertificate(t *testing.T, certFile, keyFile, commonName string, modTime time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
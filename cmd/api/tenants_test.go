@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/tijanadmi/movieginmongoapi/db/mock"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	db "github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/storage"
+	"github.com/tijanadmi/movieginmongoapi/util"
+)
+
+// tenantContext matches a context scoped to the tenant
+type tenantContext string
+
+func (tenant tenantContext) Matches(x interface{}) bool {
+	ctx, ok := x.(context.Context)
+	return ok && db.TenantFromContext(ctx) == string(tenant)
+}
+
+func (tenant tenantContext) String() string {
+	return fmt.Sprintf("is a context of tenant %q", string(tenant))
+}
+
+func TestTenantIsolationAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		host          string
+		header        string
+		tokenTenant   string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "Header",
+			host:        "localhost:8080",
+			header:      "acme",
+			tokenTenant: "acme",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListHalls(tenantContext("acme")).
+					Times(1).
+					Return([]models.Hall{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "Host",
+			host:        "globex.movies.test:8080",
+			tokenTenant: "globex",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListHalls(tenantContext("globex")).
+					Times(1).
+					Return([]models.Hall{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "UnknownTenant",
+			host:        "localhost:8080",
+			header:      "initech",
+			tokenTenant: "initech",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListHalls(gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:        "NoTenant",
+			host:        "localhost:8080",
+			tokenTenant: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListHalls(gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:        "TokenOfOtherTenant",
+			host:        "localhost:8080",
+			header:      "globex",
+			tokenTenant: "acme",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListHalls(gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireBodyMatchErrorResponse(t, recorder.Body, apiErrorResponse{Error: errTokenTenant.Error()})
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			config := testConfig()
+			config.Tenants = []string{"acme", "globex"}
+			posters, err := storage.NewLocalStorage(t.TempDir())
+			require.NoError(t, err)
+
+			server, err := NewServer(config, store, posters)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/halls", nil)
+			require.NoError(t, err)
+			request.Host = tc.host
+			if tc.header != "" {
+				request.Header.Set(config.TenantHeader, tc.header)
+			}
+
+			accessToken, _, err := server.tokenMaker.CreateToken(username, util.UserRole, tc.tokenTenant, time.Minute)
+			require.NoError(t, err)
+			request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// certificateCheckInterval is how often the certificate files are checked for changes
+const certificateCheckInterval = 10 * time.Second
+
+// certificateReloader serves the certificate of certFile and keyFile and
+// loads it again when either file changes on disk, so a renewed certificate
+// is picked up without a restart. The files are checked in the background;
+// handshakes only read the current certificate.
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+
+	certificate atomic.Pointer[tls.Certificate]
+
+	// used only by the goroutine checking the files
+	modTime       time.Time
+	failedModTime time.Time
+	statFailed    bool
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// newCertificateReloader loads the certificate, which must be valid to start,
+// and checks the files for changes on every interval until Stop is called
+func newCertificateReloader(certFile, keyFile string, interval time.Duration) (*certificateReloader, error) {
+	reloader := &certificateReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	modTime, err := reloader.lastModified()
+	if err != nil {
+		return nil, err
+	}
+	if err := reloader.load(modTime); err != nil {
+		return nil, err
+	}
+
+	go reloader.run(interval)
+	return reloader, nil
+}
+
+// GetCertificate is used as tls.Config.GetCertificate
+func (r *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.certificate.Load(), nil
+}
+
+// Stop stops checking the files and waits for the check in progress
+func (r *certificateReloader) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+	<-r.done
+}
+
+func (r *certificateReloader) run(interval time.Duration) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.reload()
+		}
+	}
+}
+
+// reload loads the certificate when the files have changed. A certificate
+// that cannot be loaded, e.g. while it is half written, is logged once and
+// the previous one is kept until the files change again.
+func (r *certificateReloader) reload() {
+	modTime, err := r.lastModified()
+	if err != nil {
+		if !r.statFailed {
+			log.Error().Err(err).Msg("cannot reload TLS certificate")
+		}
+		r.statFailed = true
+		return
+	}
+	r.statFailed = false
+
+	if modTime.Equal(r.modTime) || modTime.Equal(r.failedModTime) {
+		return
+	}
+	if err := r.load(modTime); err != nil {
+		r.failedModTime = modTime
+		log.Error().Err(err).Msg("cannot reload TLS certificate")
+		return
+	}
+	log.Info().Str("cert_file", r.certFile).Msg("reloaded TLS certificate")
+}
+
+func (r *certificateReloader) load(modTime time.Time) error {
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("cannot load TLS certificate: %w", err)
+	}
+
+	r.certificate.Store(&certificate)
+	r.modTime = modTime
+	return nil
+}
+
+// lastModified returns the later modification time of the two files
+func (r *certificateReloader) lastModified() (time.Time, error) {
+	var modTime time.Time
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("cannot read TLS certificate: %w", err)
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	return modTime, nil
+}
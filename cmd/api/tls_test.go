@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer the reloader can log to while the test reads it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestCertificateReloader(t *testing.T) {
+	var logs syncBuffer
+	logger := log.Logger
+	log.Logger = zerolog.New(&logs)
+	defer func() { log.Logger = logger }()
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeCertificate(t, certFile, keyFile, "first", time.Now())
+
+	reloader, err := newCertificateReloader(certFile, keyFile, 10*time.Millisecond)
+	require.NoError(t, err)
+	defer reloader.Stop()
+
+	commonName := func() string {
+		certificate, err := reloader.GetCertificate(nil)
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+		require.NoError(t, err)
+		return leaf.Subject.CommonName
+	}
+	require.Equal(t, "first", commonName())
+
+	// a half written certificate keeps the previous one and is logged once
+	modTime := time.Now().Add(time.Minute)
+	require.NoError(t, os.WriteFile(certFile, []byte("-----BEGIN CERTIFICATE-----\n"), 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.Eventually(t, func() bool {
+		return strings.Contains(logs.String(), "cannot reload TLS certificate")
+	}, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, 1, strings.Count(logs.String(), "cannot reload TLS certificate"))
+	require.Equal(t, "first", commonName())
+
+	// the renewed certificate is picked up by the next check
+	writeCertificate(t, certFile, keyFile, "second", time.Now().Add(2*time.Minute))
+	require.Eventually(t, func() bool {
+		return commonName() == "second"
+	}, time.Second, 10*time.Millisecond)
+
+	reloader.Stop()
+	select {
+	case <-reloader.done:
+	default:
+		t.Fatal("reloader still running after Stop")
+	}
+}
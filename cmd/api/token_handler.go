@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/tijanadmi/movieginmongoapi/repository"
 )
 
 type renewAccessTokenRequest struct {
@@ -29,6 +30,11 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		return
 	}
 
+	if refreshPayload.Tenant != repository.TenantFromContext(ctx) {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errTokenTenant))
+		return
+	}
+
 	// session, err := server.store.GetSession(refreshPayload.ID)
 	// if err != nil {
 	// 	ctx.JSON(http.StatusNotFound, errorResponse(err))
@@ -62,6 +68,7 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
 		refreshPayload.Username,
 		refreshPayload.Role,
+		refreshPayload.Tenant,
 		server.config.AccessTokenDuration,
 	)
 	if err != nil {
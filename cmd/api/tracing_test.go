@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/tijanadmi/movieginmongoapi/db/mock"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	db "github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/util"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingAPI(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	// the global tracer of the store delegates to the first provider set
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListHalls(gomock.Any()).Times(1).Return([]models.Hall{}, nil)
+
+	server := newTestServer(t, db.NewInstrumentedStore(store))
+
+	request, err := http.NewRequest(http.MethodGet, "/halls", nil)
+	require.NoError(t, err)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.UserRole, time.Minute)
+	server.router.ServeHTTP(httptest.NewRecorder(), request)
+
+	// probes are not traced
+	probe, err := http.NewRequest(http.MethodGet, "/healthz", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(httptest.NewRecorder(), probe)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	var handler, operation sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		if span.SpanKind() == trace.SpanKindServer {
+			handler = span
+		} else {
+			operation = span
+		}
+	}
+	require.NotNil(t, handler)
+	require.NotNil(t, operation)
+
+	// the request continues the trace of the caller and the store operation
+	// is a child of the request
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", handler.SpanContext().TraceID().String())
+	require.Equal(t, "MongoStore.ListHalls", operation.Name())
+	require.Equal(t, handler.SpanContext().SpanID(), operation.Parent().SpanID())
+}
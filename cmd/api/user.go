@@ -1,14 +1,32 @@
 package api
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"sync"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
 	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/repository"
 	"github.com/tijanadmi/movieginmongoapi/util"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
+var (
+	// a failed login does not tell whether the username exists
+	errInvalidCredentials = errors.New("invalid username or password")
+	errTooManyLogins      = errors.New("too many login attempts, try again later")
+)
+
+// dummyPasswordHash is checked against when the user does not exist, so an
+// unknown username takes as long to reject as a wrong password
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := util.HashPassword(util.RandomString(16))
+	return hash
+})
+
 func newUserResponse(user *models.User) userResponse {
 	return userResponse{
 		Username: user.Username,
@@ -26,6 +44,7 @@ func newUserResponse(user *models.User) userResponse {
 // @Success 200 {object} loginUserResponse
 // @Failure 400 {object} apiErrorResponse
 // @Failure 401 {object} apiErrorResponse
+// @Failure 429 {object} apiErrorResponse
 // @Router /users/login [post]
 func (server *Server) loginUser(ctx *gin.Context) {
 	var req loginUserRequest
@@ -39,27 +58,50 @@ func (server *Server) loginUser(ctx *gin.Context) {
 		return
 	}
 
+	// usernames are limited per tenant
+	limitKey := repository.TenantFromContext(ctx) + "/" + req.Username
+	wait, err := server.loginGuard.Allow(ctx, ctx.ClientIP(), limitKey)
+	if err != nil {
+		// an unavailable limiter must not lock everybody out
+		zerolog.Ctx(ctx).Error().Err(err).Msg("cannot check login limits")
+	} else if wait > 0 {
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		ctx.JSON(http.StatusTooManyRequests, apiErrorResponse{Error: errTooManyLogins.Error()})
+		return
+	}
 
 	user, err := server.store.GetUserByUsername(ctx, req.Username)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			ctx.JSON(http.StatusNotFound, apiErrorResponse{Error: "user not found"})
-		} else {
-			ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
-		}
+	found := err == nil
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		ctx.JSON(http.StatusInternalServerError, apiErrorResponse{Error: err.Error()})
 		return
 	}
 
-	err = util.CheckPassword(req.Password, user.Password)
-	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, apiErrorResponse{Error: err.Error()})
+	passwordHash := dummyPasswordHash()
+	if found {
+		passwordHash = user.Password
+	}
+	if err := util.CheckPassword(req.Password, passwordHash); err != nil || !found {
+		if err := server.loginGuard.Fail(ctx, limitKey); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("cannot record failed login")
+		}
+		ctx.JSON(http.StatusUnauthorized, apiErrorResponse{Error: errInvalidCredentials.Error()})
 		return
 	}
 
+	if err := server.loginGuard.Succeed(ctx, limitKey); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("cannot reset failed logins")
+	}
+
+	role := util.UserRole
+	if util.HasRole(user.Roles, util.AdminRole) {
+		role = util.AdminRole
+	}
+
 	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
-		/*user.Role,*/
-		"user",
+		role,
+		repository.TenantFromContext(ctx),
 		server.config.AccessTokenDuration,
 	)
 	if err != nil {
@@ -69,8 +111,8 @@ func (server *Server) loginUser(ctx *gin.Context) {
 
 	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(
 		user.Username,
-		//user.Role,
-		"user",
+		role,
+		repository.TenantFromContext(ctx),
 		server.config.RefreshTokenDuration,
 	)
 	if err != nil {
@@ -122,6 +164,8 @@ func (server *Server) InsertUser(ctx *gin.Context) {
 	}
 
 	user.Password = hashedPassword
+	// self-registered users never get elevated roles
+	user.Roles = []string{util.UserRole}
 	/*user1, _ := server.store.GetUserByUsername(ctx, user.Username)
 
 	if user.Username == user1.Username {
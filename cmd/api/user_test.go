@@ -16,6 +16,7 @@ import (
 	mockdb "github.com/tijanadmi/movieginmongoapi/db/mock"
 	"github.com/tijanadmi/movieginmongoapi/models"
 	"github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/storage"
 	"github.com/tijanadmi/movieginmongoapi/util"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -233,7 +234,8 @@ func TestLoginUserAPI(t *testing.T) {
 					Return(nil, repository.ErrRecordNotFound)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireBodyMatchErrorResponse(t, recorder.Body, apiErrorResponse{Error: errInvalidCredentials.Error()})
 			},
 		},
 		{
@@ -250,6 +252,7 @@ func TestLoginUserAPI(t *testing.T) {
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireBodyMatchErrorResponse(t, recorder.Body, apiErrorResponse{Error: errInvalidCredentials.Error()})
 			},
 		},
 		{
@@ -312,6 +315,194 @@ func TestLoginUserAPI(t *testing.T) {
 	}
 }
 
+func TestLoginRateLimitAPI(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		config        func(config *util.Config)
+		attempts      []gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Lockout",
+			config: func(config *util.Config) {
+				config.LoginMaxFailures = 2
+			},
+			attempts: []gin.H{
+				{"username": user.Username, "password": "incorrect"},
+				{"username": user.Username, "password": "incorrect"},
+				// even the right password is refused while locked out
+				{"username": user.Username, "password": password},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq(user.Username)).
+					Times(2).
+					Return(&user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.Equal(t, "900", recorder.Header().Get("Retry-After"))
+				requireBodyMatchErrorResponse(t, recorder.Body, apiErrorResponse{Error: errTooManyLogins.Error()})
+			},
+		},
+		{
+			name: "LockoutOfUnknownUser",
+			config: func(config *util.Config) {
+				config.LoginMaxFailures = 2
+			},
+			attempts: []gin.H{
+				{"username": "NotFound", "password": password},
+				{"username": "NotFound", "password": password},
+				{"username": "NotFound", "password": password},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(nil, repository.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			},
+		},
+		{
+			name: "SuccessResetsFailures",
+			config: func(config *util.Config) {
+				config.LoginMaxFailures = 2
+			},
+			attempts: []gin.H{
+				{"username": user.Username, "password": "incorrect"},
+				{"username": user.Username, "password": password},
+				{"username": user.Username, "password": "incorrect"},
+				{"username": user.Username, "password": password},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq(user.Username)).
+					Times(4).
+					Return(&user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "PerIPLimit",
+			config: func(config *util.Config) {
+				config.LoginAttemptsPerIP = 2
+			},
+			attempts: []gin.H{
+				{"username": user.Username, "password": password},
+				{"username": "other1", "password": password},
+				{"username": "other2", "password": password},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(&user, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq("other1")).
+					Times(1).
+					Return(nil, repository.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.Equal(t, "30", recorder.Header().Get("Retry-After"))
+			},
+		},
+		{
+			name: "SpoofedForwardedFor",
+			config: func(config *util.Config) {
+				config.LoginAttemptsPerIP = 2
+				config.TrustedProxies = []string{"198.51.100.0/24"}
+			},
+			attempts: []gin.H{
+				{"username": user.Username, "password": password},
+				{"username": "other1", "password": password},
+				{"username": "other2", "password": password},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(&user, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq("other1")).
+					Times(1).
+					Return(nil, repository.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			},
+		},
+		{
+			name: "TrustedProxy",
+			config: func(config *util.Config) {
+				config.LoginAttemptsPerIP = 2
+				config.TrustedProxies = []string{"192.0.2.1"}
+			},
+			attempts: []gin.H{
+				{"username": user.Username, "password": password},
+				{"username": "other1", "password": password},
+				{"username": "other2", "password": password},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(&user, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(nil, repository.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			config := testConfig()
+			tc.config(&config)
+			posters, err := storage.NewLocalStorage(t.TempDir())
+			require.NoError(t, err)
+
+			server, err := NewServer(config, store, posters)
+			require.NoError(t, err)
+
+			var recorder *httptest.ResponseRecorder
+			for i, body := range tc.attempts {
+				data, err := json.Marshal(body)
+				require.NoError(t, err)
+
+				request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+				require.NoError(t, err)
+				// every attempt claims another client behind the same proxy
+				request.RemoteAddr = "192.0.2.1:40000"
+				request.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i+1))
+
+				recorder = httptest.NewRecorder()
+				server.router.ServeHTTP(recorder, request)
+			}
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
 func randomUser(t *testing.T) (user models.User, password string) {
 	password = util.RandomString(6)
 	hashedPassword, err := util.HashPassword(password)
@@ -37,6 +37,21 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AddCinema mocks base method.
+func (m *MockStore) AddCinema(arg0 context.Context, arg1 *models.Cinema) (*models.Cinema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCinema", arg0, arg1)
+	ret0, _ := ret[0].(*models.Cinema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCinema indicates an expected call of AddCinema.
+func (mr *MockStoreMockRecorder) AddCinema(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCinema", reflect.TypeOf((*MockStore)(nil).AddCinema), arg0, arg1)
+}
+
 // AddMovie mocks base method.
 func (m *MockStore) AddMovie(arg0 context.Context, arg1 *models.Movie) (*models.Movie, error) {
 	m.ctrl.T.Helper()
@@ -82,6 +97,36 @@ func (mr *MockStoreMockRecorder) AddReservation(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReservation", reflect.TypeOf((*MockStore)(nil).AddReservation), arg0, arg1)
 }
 
+// AddReview mocks base method.
+func (m *MockStore) AddReview(arg0 context.Context, arg1 *models.Review) (*models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReview", arg0, arg1)
+	ret0, _ := ret[0].(*models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddReview indicates an expected call of AddReview.
+func (mr *MockStoreMockRecorder) AddReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReview", reflect.TypeOf((*MockStore)(nil).AddReview), arg0, arg1)
+}
+
+// BlockSeats mocks base method.
+func (m *MockStore) BlockSeats(arg0 context.Context, arg1 string, arg2 []string, arg3, arg4 string) (*models.Repertoire, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockSeats", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*models.Repertoire)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockSeats indicates an expected call of BlockSeats.
+func (mr *MockStoreMockRecorder) BlockSeats(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSeats", reflect.TypeOf((*MockStore)(nil).BlockSeats), arg0, arg1, arg2, arg3, arg4)
+}
+
 // CancelReservation mocks base method.
 func (m *MockStore) CancelReservation(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -96,60 +141,92 @@ func (mr *MockStoreMockRecorder) CancelReservation(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelReservation", reflect.TypeOf((*MockStore)(nil).CancelReservation), arg0, arg1)
 }
 
-// DeleteHall mocks base method.
-func (m *MockStore) DeleteHall(arg0 context.Context, arg1 string) error {
+// CheckInReservation mocks base method.
+func (m *MockStore) CheckInReservation(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteHall", arg0, arg1)
+	ret := m.ctrl.Call(m, "CheckInReservation", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteHall indicates an expected call of DeleteHall.
-func (mr *MockStoreMockRecorder) DeleteHall(arg0, arg1 interface{}) *gomock.Call {
+// CheckInReservation indicates an expected call of CheckInReservation.
+func (mr *MockStoreMockRecorder) CheckInReservation(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHall", reflect.TypeOf((*MockStore)(nil).DeleteHall), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckInReservation", reflect.TypeOf((*MockStore)(nil).CheckInReservation), arg0, arg1)
 }
 
-// DeleteMovie mocks base method.
-func (m *MockStore) DeleteMovie(arg0 context.Context, arg1 string) error {
+// DeleteCinema mocks base method.
+func (m *MockStore) DeleteCinema(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteMovie", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteCinema", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// DeleteCinema indicates an expected call of DeleteCinema.
+func (mr *MockStoreMockRecorder) DeleteCinema(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCinema", reflect.TypeOf((*MockStore)(nil).DeleteCinema), arg0, arg1, arg2)
+}
+
+// DeleteHall mocks base method.
+func (m *MockStore) DeleteHall(arg0 context.Context, arg1, arg2 string, arg3 bool) (repository.Dependents, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHall", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(repository.Dependents)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteHall indicates an expected call of DeleteHall.
+func (mr *MockStoreMockRecorder) DeleteHall(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHall", reflect.TypeOf((*MockStore)(nil).DeleteHall), arg0, arg1, arg2, arg3)
+}
+
+// DeleteMovie mocks base method.
+func (m *MockStore) DeleteMovie(arg0 context.Context, arg1, arg2 string, arg3 bool) (repository.Dependents, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMovie", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(repository.Dependents)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // DeleteMovie indicates an expected call of DeleteMovie.
-func (mr *MockStoreMockRecorder) DeleteMovie(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) DeleteMovie(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMovie", reflect.TypeOf((*MockStore)(nil).DeleteMovie), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMovie", reflect.TypeOf((*MockStore)(nil).DeleteMovie), arg0, arg1, arg2, arg3)
 }
 
 // DeleteRepertoire mocks base method.
-func (m *MockStore) DeleteRepertoire(arg0 context.Context, arg1 string) error {
+func (m *MockStore) DeleteRepertoire(arg0 context.Context, arg1, arg2 string, arg3 bool) (repository.Dependents, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteRepertoire", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "DeleteRepertoire", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(repository.Dependents)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteRepertoire indicates an expected call of DeleteRepertoire.
-func (mr *MockStoreMockRecorder) DeleteRepertoire(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) DeleteRepertoire(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRepertoire", reflect.TypeOf((*MockStore)(nil).DeleteRepertoire), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRepertoire", reflect.TypeOf((*MockStore)(nil).DeleteRepertoire), arg0, arg1, arg2, arg3)
 }
 
 // DeleteRepertoireForMovie mocks base method.
-func (m *MockStore) DeleteRepertoireForMovie(arg0 context.Context, arg1 string) error {
+func (m *MockStore) DeleteRepertoireForMovie(arg0 context.Context, arg1, arg2 string, arg3 bool) (repository.Dependents, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteRepertoireForMovie", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "DeleteRepertoireForMovie", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(repository.Dependents)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteRepertoireForMovie indicates an expected call of DeleteRepertoireForMovie.
-func (mr *MockStoreMockRecorder) DeleteRepertoireForMovie(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) DeleteRepertoireForMovie(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRepertoireForMovie", reflect.TypeOf((*MockStore)(nil).DeleteRepertoireForMovie), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRepertoireForMovie", reflect.TypeOf((*MockStore)(nil).DeleteRepertoireForMovie), arg0, arg1, arg2, arg3)
 }
 
 // DeleteReservation mocks base method.
@@ -166,19 +243,62 @@ func (mr *MockStoreMockRecorder) DeleteReservation(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReservation", reflect.TypeOf((*MockStore)(nil).DeleteReservation), arg0, arg1)
 }
 
+// DeleteReview mocks base method.
+func (m *MockStore) DeleteReview(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockStoreMockRecorder) DeleteReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockStore)(nil).DeleteReview), arg0, arg1)
+}
+
+// EnsureIndexes mocks base method.
+func (m *MockStore) EnsureIndexes(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureIndexes", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureIndexes indicates an expected call of EnsureIndexes.
+func (mr *MockStoreMockRecorder) EnsureIndexes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureIndexes", reflect.TypeOf((*MockStore)(nil).EnsureIndexes), arg0)
+}
+
+// FindBestSeats mocks base method.
+func (m *MockStore) FindBestSeats(arg0 context.Context, arg1 string, arg2 int, arg3 string, arg4 time.Time) (models.SeatProposal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBestSeats", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(models.SeatProposal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBestSeats indicates an expected call of FindBestSeats.
+func (mr *MockStoreMockRecorder) FindBestSeats(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBestSeats", reflect.TypeOf((*MockStore)(nil).FindBestSeats), arg0, arg1, arg2, arg3, arg4)
+}
+
 // GetAllRepertoireForMovie mocks base method.
-func (m *MockStore) GetAllRepertoireForMovie(arg0 context.Context, arg1 string, arg2, arg3 time.Time) ([]models.Repertoire, error) {
+func (m *MockStore) GetAllRepertoireForMovie(arg0 context.Context, arg1 string, arg2, arg3 time.Time, arg4 string) ([]models.Repertoire, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllRepertoireForMovie", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "GetAllRepertoireForMovie", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].([]models.Repertoire)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllRepertoireForMovie indicates an expected call of GetAllRepertoireForMovie.
-func (mr *MockStoreMockRecorder) GetAllRepertoireForMovie(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) GetAllRepertoireForMovie(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllRepertoireForMovie", reflect.TypeOf((*MockStore)(nil).GetAllRepertoireForMovie), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllRepertoireForMovie", reflect.TypeOf((*MockStore)(nil).GetAllRepertoireForMovie), arg0, arg1, arg2, arg3, arg4)
 }
 
 // GetAllReservationsForUser mocks base method.
@@ -196,6 +316,21 @@ func (mr *MockStoreMockRecorder) GetAllReservationsForUser(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllReservationsForUser", reflect.TypeOf((*MockStore)(nil).GetAllReservationsForUser), arg0, arg1)
 }
 
+// GetCinema mocks base method.
+func (m *MockStore) GetCinema(arg0 context.Context, arg1 string) (*models.Cinema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCinema", arg0, arg1)
+	ret0, _ := ret[0].(*models.Cinema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCinema indicates an expected call of GetCinema.
+func (mr *MockStoreMockRecorder) GetCinema(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCinema", reflect.TypeOf((*MockStore)(nil).GetCinema), arg0, arg1)
+}
+
 // GetHall mocks base method.
 func (m *MockStore) GetHall(arg0 context.Context, arg1 string) ([]models.Hall, error) {
 	m.ctrl.T.Helper()
@@ -241,6 +376,21 @@ func (mr *MockStoreMockRecorder) GetMovie(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMovie", reflect.TypeOf((*MockStore)(nil).GetMovie), arg0, arg1)
 }
 
+// GetProgram mocks base method.
+func (m *MockStore) GetProgram(arg0 context.Context, arg1 time.Time, arg2 string) ([]models.ProgramHall, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProgram", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.ProgramHall)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProgram indicates an expected call of GetProgram.
+func (mr *MockStoreMockRecorder) GetProgram(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProgram", reflect.TypeOf((*MockStore)(nil).GetProgram), arg0, arg1, arg2)
+}
+
 // GetRepertoire mocks base method.
 func (m *MockStore) GetRepertoire(arg0 context.Context, arg1 string) (*models.Repertoire, error) {
 	m.ctrl.T.Helper()
@@ -346,6 +496,36 @@ func (mr *MockStoreMockRecorder) InsertUser(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUser", reflect.TypeOf((*MockStore)(nil).InsertUser), arg0, arg1)
 }
 
+// ListCinemas mocks base method.
+func (m *MockStore) ListCinemas(arg0 context.Context) ([]models.Cinema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCinemas", arg0)
+	ret0, _ := ret[0].([]models.Cinema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCinemas indicates an expected call of ListCinemas.
+func (mr *MockStoreMockRecorder) ListCinemas(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCinemas", reflect.TypeOf((*MockStore)(nil).ListCinemas), arg0)
+}
+
+// ListComingSoon mocks base method.
+func (m *MockStore) ListComingSoon(arg0 context.Context, arg1 time.Time) ([]models.MovieSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListComingSoon", arg0, arg1)
+	ret0, _ := ret[0].([]models.MovieSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListComingSoon indicates an expected call of ListComingSoon.
+func (mr *MockStoreMockRecorder) ListComingSoon(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComingSoon", reflect.TypeOf((*MockStore)(nil).ListComingSoon), arg0, arg1)
+}
+
 // ListHalls mocks base method.
 func (m *MockStore) ListHalls(arg0 context.Context) ([]models.Hall, error) {
 	m.ctrl.T.Helper()
@@ -376,6 +556,66 @@ func (mr *MockStoreMockRecorder) ListMovies(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMovies", reflect.TypeOf((*MockStore)(nil).ListMovies), arg0)
 }
 
+// ListMoviesByGenre mocks base method.
+func (m *MockStore) ListMoviesByGenre(arg0 context.Context, arg1 string) ([]models.Movie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMoviesByGenre", arg0, arg1)
+	ret0, _ := ret[0].([]models.Movie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMoviesByGenre indicates an expected call of ListMoviesByGenre.
+func (mr *MockStoreMockRecorder) ListMoviesByGenre(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMoviesByGenre", reflect.TypeOf((*MockStore)(nil).ListMoviesByGenre), arg0, arg1)
+}
+
+// ListMoviesByPerson mocks base method.
+func (m *MockStore) ListMoviesByPerson(arg0 context.Context, arg1, arg2 string) ([]models.Movie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMoviesByPerson", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Movie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMoviesByPerson indicates an expected call of ListMoviesByPerson.
+func (mr *MockStoreMockRecorder) ListMoviesByPerson(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMoviesByPerson", reflect.TypeOf((*MockStore)(nil).ListMoviesByPerson), arg0, arg1, arg2)
+}
+
+// ListNotifications mocks base method.
+func (m *MockStore) ListNotifications(arg0 context.Context, arg1 string) ([]models.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", arg0, arg1)
+	ret0, _ := ret[0].([]models.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockStoreMockRecorder) ListNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockStore)(nil).ListNotifications), arg0, arg1)
+}
+
+// ListNowShowing mocks base method.
+func (m *MockStore) ListNowShowing(arg0 context.Context, arg1, arg2 time.Time) ([]models.MovieSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNowShowing", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.MovieSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNowShowing indicates an expected call of ListNowShowing.
+func (mr *MockStoreMockRecorder) ListNowShowing(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNowShowing", reflect.TypeOf((*MockStore)(nil).ListNowShowing), arg0, arg1, arg2)
+}
+
 // ListRepertoires mocks base method.
 func (m *MockStore) ListRepertoires(arg0 context.Context) ([]models.Repertoire, error) {
 	m.ctrl.T.Helper()
@@ -391,6 +631,136 @@ func (mr *MockStoreMockRecorder) ListRepertoires(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRepertoires", reflect.TypeOf((*MockStore)(nil).ListRepertoires), arg0)
 }
 
+// ListReviews mocks base method.
+func (m *MockStore) ListReviews(arg0 context.Context, arg1 string) ([]models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReviews", arg0, arg1)
+	ret0, _ := ret[0].([]models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReviews indicates an expected call of ListReviews.
+func (mr *MockStoreMockRecorder) ListReviews(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReviews", reflect.TypeOf((*MockStore)(nil).ListReviews), arg0, arg1)
+}
+
+// ListReviewsForMovie mocks base method.
+func (m *MockStore) ListReviewsForMovie(arg0 context.Context, arg1 string) ([]models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReviewsForMovie", arg0, arg1)
+	ret0, _ := ret[0].([]models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReviewsForMovie indicates an expected call of ListReviewsForMovie.
+func (mr *MockStoreMockRecorder) ListReviewsForMovie(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReviewsForMovie", reflect.TypeOf((*MockStore)(nil).ListReviewsForMovie), arg0, arg1)
+}
+
+// PendingMigrations mocks base method.
+func (m *MockStore) PendingMigrations(arg0 context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingMigrations", arg0)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingMigrations indicates an expected call of PendingMigrations.
+func (mr *MockStoreMockRecorder) PendingMigrations(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingMigrations", reflect.TypeOf((*MockStore)(nil).PendingMigrations), arg0)
+}
+
+// Ping mocks base method.
+func (m *MockStore) Ping(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockStoreMockRecorder) Ping(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockStore)(nil).Ping), arg0)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockStore) PurgeDeleted(arg0 context.Context, arg1 time.Time) (repository.PurgeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", arg0, arg1)
+	ret0, _ := ret[0].(repository.PurgeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockStoreMockRecorder) PurgeDeleted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockStore)(nil).PurgeDeleted), arg0, arg1)
+}
+
+// RestoreHall mocks base method.
+func (m *MockStore) RestoreHall(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreHall", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreHall indicates an expected call of RestoreHall.
+func (mr *MockStoreMockRecorder) RestoreHall(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreHall", reflect.TypeOf((*MockStore)(nil).RestoreHall), arg0, arg1)
+}
+
+// RestoreMovie mocks base method.
+func (m *MockStore) RestoreMovie(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreMovie", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreMovie indicates an expected call of RestoreMovie.
+func (mr *MockStoreMockRecorder) RestoreMovie(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreMovie", reflect.TypeOf((*MockStore)(nil).RestoreMovie), arg0, arg1)
+}
+
+// RestoreRepertoire mocks base method.
+func (m *MockStore) RestoreRepertoire(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreRepertoire", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreRepertoire indicates an expected call of RestoreRepertoire.
+func (mr *MockStoreMockRecorder) RestoreRepertoire(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreRepertoire", reflect.TypeOf((*MockStore)(nil).RestoreRepertoire), arg0, arg1)
+}
+
+// RunMigrations mocks base method.
+func (m *MockStore) RunMigrations(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunMigrations", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunMigrations indicates an expected call of RunMigrations.
+func (mr *MockStoreMockRecorder) RunMigrations(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunMigrations", reflect.TypeOf((*MockStore)(nil).RunMigrations), arg0)
+}
+
 // SearchMovies mocks base method.
 func (m *MockStore) SearchMovies(arg0 context.Context, arg1 string) ([]models.Movie, error) {
 	m.ctrl.T.Helper()
@@ -406,6 +776,80 @@ func (mr *MockStoreMockRecorder) SearchMovies(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchMovies", reflect.TypeOf((*MockStore)(nil).SearchMovies), arg0, arg1)
 }
 
+// SearchMoviesText mocks base method.
+func (m *MockStore) SearchMoviesText(arg0 context.Context, arg1 repository.SearchMoviesParams) ([]models.Movie, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchMoviesText", arg0, arg1)
+	ret0, _ := ret[0].([]models.Movie)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchMoviesText indicates an expected call of SearchMoviesText.
+func (mr *MockStoreMockRecorder) SearchMoviesText(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchMoviesText", reflect.TypeOf((*MockStore)(nil).SearchMoviesText), arg0, arg1)
+}
+
+// SetCinemaAdmin mocks base method.
+func (m *MockStore) SetCinemaAdmin(arg0 context.Context, arg1, arg2 string, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCinemaAdmin", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCinemaAdmin indicates an expected call of SetCinemaAdmin.
+func (mr *MockStoreMockRecorder) SetCinemaAdmin(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCinemaAdmin", reflect.TypeOf((*MockStore)(nil).SetCinemaAdmin), arg0, arg1, arg2, arg3)
+}
+
+// SetReviewStatus mocks base method.
+func (m *MockStore) SetReviewStatus(arg0 context.Context, arg1, arg2, arg3 string) (*models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReviewStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetReviewStatus indicates an expected call of SetReviewStatus.
+func (mr *MockStoreMockRecorder) SetReviewStatus(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReviewStatus", reflect.TypeOf((*MockStore)(nil).SetReviewStatus), arg0, arg1, arg2, arg3)
+}
+
+// UnblockSeats mocks base method.
+func (m *MockStore) UnblockSeats(arg0 context.Context, arg1 string, arg2 []string) (*models.Repertoire, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnblockSeats", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Repertoire)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnblockSeats indicates an expected call of UnblockSeats.
+func (mr *MockStoreMockRecorder) UnblockSeats(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockSeats", reflect.TypeOf((*MockStore)(nil).UnblockSeats), arg0, arg1, arg2)
+}
+
+// UpdateCinema mocks base method.
+func (m *MockStore) UpdateCinema(arg0 context.Context, arg1 string, arg2 models.Cinema) (*models.Cinema, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCinema", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.Cinema)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCinema indicates an expected call of UpdateCinema.
+func (mr *MockStoreMockRecorder) UpdateCinema(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCinema", reflect.TypeOf((*MockStore)(nil).UpdateCinema), arg0, arg1, arg2)
+}
+
 // UpdateHall mocks base method.
 func (m *MockStore) UpdateHall(arg0 context.Context, arg1 string, arg2 models.Hall) (models.Hall, error) {
 	m.ctrl.T.Helper()
@@ -436,6 +880,20 @@ func (mr *MockStoreMockRecorder) UpdateMovie(arg0, arg1, arg2 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMovie", reflect.TypeOf((*MockStore)(nil).UpdateMovie), arg0, arg1, arg2)
 }
 
+// UpdateMoviePoster mocks base method.
+func (m *MockStore) UpdateMoviePoster(arg0 context.Context, arg1, arg2 string, arg3 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMoviePoster", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMoviePoster indicates an expected call of UpdateMoviePoster.
+func (mr *MockStoreMockRecorder) UpdateMoviePoster(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMoviePoster", reflect.TypeOf((*MockStore)(nil).UpdateMoviePoster), arg0, arg1, arg2, arg3)
+}
+
 // UpdateRepertoire mocks base method.
 func (m *MockStore) UpdateRepertoire(arg0 context.Context, arg1 string, arg2 models.Repertoire) (*models.Repertoire, error) {
 	m.ctrl.T.Helper()
@@ -450,3 +908,17 @@ func (mr *MockStoreMockRecorder) UpdateRepertoire(arg0, arg1, arg2 interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRepertoire", reflect.TypeOf((*MockStore)(nil).UpdateRepertoire), arg0, arg1, arg2)
 }
+
+// WithTransaction mocks base method.
+func (m *MockStore) WithTransaction(arg0 context.Context, arg1 func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTransaction", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTransaction indicates an expected call of WithTransaction.
+func (mr *MockStoreMockRecorder) WithTransaction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTransaction", reflect.TypeOf((*MockStore)(nil).WithTransaction), arg0, arg1)
+}
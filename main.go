@@ -2,8 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -11,9 +16,13 @@ import (
 	"github.com/tijanadmi/movieginmongoapi/cmd/api"
 	"github.com/tijanadmi/movieginmongoapi/docs" // Swagger generated files
 	db "github.com/tijanadmi/movieginmongoapi/repository"
+	"github.com/tijanadmi/movieginmongoapi/storage"
+	"github.com/tijanadmi/movieginmongoapi/tracing"
 	"github.com/tijanadmi/movieginmongoapi/util"
+	"github.com/tijanadmi/movieginmongoapi/worker"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
 const (
@@ -45,32 +54,145 @@ func main() {
 	if config.Environment == "development" {
 		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
+	// store operations outside of a request log to the global logger
+	zerolog.DefaultContextLogger = &log.Logger
+
+	shutdownTracing, err := tracing.Setup(context.Background(), config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot set up tracing")
+	}
 
 	client, err := connectToMongo(config.MongoURL, config.Username, config.Password)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Unable to connect to database: %v\n", err)
-		os.Exit(1)
+		log.Fatal().Err(err).Msg("cannot connect to database")
 	}
 
-	// create a context in order to disconnect
+	// migrations and indexes must be done before serving
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
-	// close connection
-	defer func() {
+	store := db.NewInstrumentedStore(db.NewStore(client, config.Database))
+	for _, tenantCtx := range tenantContexts(ctx, config.Tenants) {
+		if err = store.RunMigrations(tenantCtx); err != nil {
+			zerolog.Ctx(tenantCtx).Fatal().Err(err).Msg("cannot run migrations")
+		}
+		if err = store.EnsureIndexes(tenantCtx); err != nil {
+			zerolog.Ctx(tenantCtx).Fatal().Err(err).Msg("cannot create indexes")
+		}
+	}
+
+	if code, ok := runCommand(context.Background(), store, config.Tenants, os.Args[1:]); ok {
 		if err = client.Disconnect(ctx); err != nil {
-			panic(err)
+			log.Error().Err(err).Msg("cannot disconnect from database")
+		}
+		if err = shutdownTracing(ctx); err != nil {
+			log.Error().Err(err).Msg("cannot flush traces")
 		}
+		os.Exit(code)
+	}
+
+	posters, err := newPosterStorage(config, client)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create poster storage")
+	}
+
+	server, err := api.NewServer(config, store, posters)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create server")
+	}
+
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	var purgers []*worker.Purger
+	for _, tenantCtx := range tenantContexts(workersCtx, config.Tenants) {
+		purger := worker.NewPurger(store, config.SoftDeleteRetention, config.PurgeInterval)
+		purgers = append(purgers, purger)
+
+		workers.Add(1)
+		go func(ctx context.Context) {
+			defer workers.Done()
+			purger.Run(ctx)
+		}(tenantCtx)
+	}
+	server.AddReadinessCheck("workers", func(context.Context) error {
+		for _, purger := range purgers {
+			if !purger.Running() {
+				return errors.New("purger is not running")
+			}
+		}
+		return nil
+	})
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Info().Str("address", config.HTTPServerAddress).Bool("tls", config.TLSCertFile != "").Msg("starting HTTP server")
+		serverErr <- server.Start(config.HTTPServerAddress)
+	}()
+
+	select {
+	case err = <-serverErr:
+		log.Error().Err(err).Msg("cannot start server")
+	case <-signalCtx.Done():
+		log.Info().Msg("shutting down")
+	}
+
+	shutdown(server, client, stopWorkers, &workers, shutdownTracing, config.ShutdownTimeout)
+}
+
+// shutdown drains the HTTP server, stops the background workers, disconnects
+// from the database and flushes the traces, all within timeout
+func shutdown(server *api.Server, client *mongo.Client, stopWorkers context.CancelFunc, workers *sync.WaitGroup, shutdownTracing func(context.Context) error, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("cannot drain HTTP server")
+	}
+
+	stopWorkers()
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
 	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Error().Err(ctx.Err()).Msg("background workers did not stop")
+	}
 
-	store := db.NewStore(client, config.Database)
-	runGinServer(config, store)
+	if err := client.Disconnect(ctx); err != nil {
+		log.Error().Err(err).Msg("cannot disconnect from database")
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error().Err(err).Msg("cannot flush traces")
+	}
+	log.Info().Msg("server stopped")
+}
 
+// tenantContexts returns a context for each tenant, with a logger that names
+// the tenant, or only ctx when the deployment has no tenants
+func tenantContexts(ctx context.Context, tenants []string) []context.Context {
+	if len(tenants) == 0 {
+		return []context.Context{ctx}
+	}
+
+	contexts := make([]context.Context, 0, len(tenants))
+	for _, tenant := range tenants {
+		logger := log.With().Str("tenant", tenant).Logger()
+		contexts = append(contexts, logger.WithContext(db.WithTenant(ctx, tenant)))
+	}
+	return contexts
 }
 
 func connectToMongo(mongoURL string, username string, password string) (*mongo.Client, error) {
 	// create connection options
 	clientOptions := options.Client().ApplyURI(mongoURL)
+	// every database command gets a span under the span of its store operation
+	clientOptions.SetMonitor(otelmongo.NewMonitor())
 	// clientOptions.SetAuth(options.Credential{
 	// 	Username: username,
 	// 	Password: password,
@@ -79,7 +201,6 @@ func connectToMongo(mongoURL string, username string, password string) (*mongo.C
 	// connect
 	c, err := mongo.Connect(context.TODO(), clientOptions)
 	if err != nil {
-		//log.Println("Error connecting:", err)
 		log.Fatal().Err(err).Msg("error connecting")
 		return nil, err
 	}
@@ -87,14 +208,19 @@ func connectToMongo(mongoURL string, username string, password string) (*mongo.C
 	return c, nil
 }
 
-func runGinServer(config util.Config, store db.Store) {
-	server, err := api.NewServer(config, store)
-	if err != nil {
-		log.Fatal().Err(err).Msg("cannot create server")
-	}
-
-	err = server.Start(config.HTTPServerAddress)
-	if err != nil {
-		log.Fatal().Err(err).Msg("cannot start server")
+// newPosterStorage creates the poster storage selected by POSTER_STORAGE, "local" or "gridfs".
+// Every tenant has its own posters: in a subdirectory of POSTER_DIR, or in the GridFS bucket of its database.
+func newPosterStorage(config util.Config, client *mongo.Client) (storage.Storage, error) {
+	switch config.PosterStorage {
+	case "local":
+		return storage.NewPartitionedStorage(db.TenantFromContext, func(tenant string) (storage.Storage, error) {
+			return storage.NewLocalStorage(filepath.Join(config.PosterDir, tenant))
+		}), nil
+	case "gridfs":
+		return storage.NewPartitionedStorage(db.TenantFromContext, func(tenant string) (storage.Storage, error) {
+			return storage.NewGridFSStorage(client.Database(db.TenantDatabase(config.Database, tenant)), "posters")
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown poster storage %q", config.PosterStorage)
 	}
 }
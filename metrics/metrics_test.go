@@ -0,0 +1,66 @@
+package metrics_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/tijanadmi/movieginmongoapi/db/mock"
+	"github.com/tijanadmi/movieginmongoapi/metrics"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(metrics.Middleware())
+	router.GET("/halls/:id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNotFound)
+	})
+
+	routeCount := func() float64 {
+		return testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/halls/:id", "404"))
+	}
+	unmatchedCount := func() float64 {
+		return testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, "unmatched", "404"))
+	}
+	routeBefore, unmatchedBefore := routeCount(), unmatchedCount()
+
+	for _, path := range []string{"/halls/1", "/halls/2", "/no/such/path"} {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	// requests are counted by route, not by path
+	require.Equal(t, routeBefore+2, routeCount())
+	require.Equal(t, unmatchedBefore+1, unmatchedCount())
+}
+
+func TestInstrumentedStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := mockdb.NewMockStore(ctrl)
+	mock.EXPECT().ListHalls(gomock.Any()).Times(1).Return([]models.Hall{}, nil)
+	mock.EXPECT().GetCinema(gomock.Any(), gomock.Any()).Times(1).Return(nil, repository.ErrCinemaNotFound)
+
+	seriesBefore := testutil.CollectAndCount(metrics.MongoOperationDuration)
+	store := repository.NewInstrumentedStore(mock)
+
+	halls, err := store.ListHalls(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, halls)
+
+	// errors are passed through unchanged
+	_, err = store.GetCinema(context.Background(), "unknown")
+	require.ErrorIs(t, err, repository.ErrCinemaNotFound)
+
+	// one series for each operation and outcome
+	require.Equal(t, seriesBefore+2, testutil.CollectAndCount(metrics.MongoOperationDuration))
+}
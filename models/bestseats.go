@@ -0,0 +1,106 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SeatProposal is a set of adjacent seats proposed for a party. HeldUntil is
+// set when the seats are held for the user.
+type SeatProposal struct {
+	RepertoireID primitive.ObjectID `json:"repertoireId"`
+	Seats        []string           `json:"seats"`
+	HeldUntil    *time.Time         `json:"heldUntil,omitempty"`
+}
+
+// gapPenalty outweighs any distance from the center, so a block leaving a
+// single free seat next to it is only proposed when there is nothing else
+const gapPenalty = 1000
+
+// BestAvailableSeats picks party adjacent seats from the layout, skipping the
+// unavailable ones. Seats are adjacent when they are next to each other in the
+// same row, so aisles split a row. Blocks closer to the center of the hall
+// score better, and blocks that would leave a single seat free between them
+// and a taken seat or an aisle are avoided. Wheelchair spaces are never
+// proposed. It returns nil when no block of party seats is free.
+func BestAvailableSeats(layout []Seat, unavailable map[string]bool, party int) []string {
+	if party <= 0 || len(layout) == 0 {
+		return nil
+	}
+
+	minRow, maxRow := layout[0].Row, layout[0].Row
+	minCol, maxCol := layout[0].Col, layout[0].Col
+	rows := make(map[int][]Seat)
+	for _, seat := range layout {
+		minRow, maxRow = min(minRow, seat.Row), max(maxRow, seat.Row)
+		minCol, maxCol = min(minCol, seat.Col), max(maxCol, seat.Col)
+		rows[seat.Row] = append(rows[seat.Row], seat)
+	}
+	centerRow := float64(minRow+maxRow) / 2
+	centerCol := float64(minCol+maxCol) / 2
+
+	var best []Seat
+	bestScore := math.Inf(1)
+	for row, seats := range rows {
+		sort.Slice(seats, func(i, j int) bool { return seats[i].Col < seats[j].Col })
+
+		for _, run := range freeRuns(seats, unavailable) {
+			for start := 0; start+party <= len(run); start++ {
+				block := run[start : start+party]
+
+				middle := float64(block[0].Col+block[party-1].Col) / 2
+				score := math.Abs(middle-centerCol) + math.Abs(float64(row)-centerRow)
+				if left, right := start, len(run)-start-party; left == 1 || right == 1 {
+					score += gapPenalty
+				}
+
+				if score < bestScore || score == bestScore && before(block[0], best[0]) {
+					best, bestScore = block, score
+				}
+			}
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+	labels := make([]string, len(best))
+	for i, seat := range best {
+		labels[i] = seat.Label
+	}
+	return labels
+}
+
+// freeRuns splits a row sorted by column into runs of free seats standing
+// next to each other
+func freeRuns(row []Seat, unavailable map[string]bool) [][]Seat {
+	var runs [][]Seat
+	var run []Seat
+	for _, seat := range row {
+		free := !seat.Disabled && seat.Category != SeatWheelchair && !unavailable[seat.Label]
+		if !free || len(run) > 0 && seat.Col != run[len(run)-1].Col+1 {
+			if len(run) > 0 {
+				runs = append(runs, run)
+			}
+			run = nil
+		}
+		if free {
+			run = append(run, seat)
+		}
+	}
+	if len(run) > 0 {
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// before orders seats front to back and left to right, to break ties
+func before(a, b Seat) bool {
+	if a.Row != b.Row {
+		return a.Row < b.Row
+	}
+	return a.Col < b.Col
+}
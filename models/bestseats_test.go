@@ -0,0 +1,115 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBestAvailableSeats(t *testing.T) {
+	rows := []string{"A", "B", "C", "D", "E"}
+	cols := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+
+	// a hall of 3 rows with an aisle between seats 3 and 4
+	withAisle := BuildLayout([]string{"A", "B", "C"}, []int{1, 2, 3, 4, 5, 6})
+	for i := range withAisle {
+		if withAisle[i].Col >= 3 {
+			withAisle[i].Col++
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		layout      []Seat
+		unavailable []string
+		party       int
+		want        []string
+	}{
+		{
+			name:   "EmptyHallTakesTheCenter",
+			layout: BuildLayout(rows, cols),
+			party:  3,
+			want:   []string{"C4", "C5", "C6"},
+		},
+		{
+			name:   "EvenPartyBreaksTieToTheLeft",
+			layout: BuildLayout(rows, cols),
+			party:  2,
+			want:   []string{"C4", "C5"},
+		},
+		{
+			name:        "CenterTakenMovesToNextRow",
+			layout:      BuildLayout(rows, cols),
+			unavailable: []string{"C4", "C5", "C6"},
+			party:       3,
+			want:        []string{"B4", "B5", "B6"},
+		},
+		{
+			name:        "AvoidsSingleSeatGaps",
+			layout:      BuildLayout([]string{"A"}, cols),
+			unavailable: []string{"A2"},
+			party:       3,
+			// A4-A6 is in the center but would leave A3 alone
+			want: []string{"A3", "A4", "A5"},
+		},
+		{
+			name:        "GapWhenUnavoidable",
+			layout:      BuildLayout([]string{"A"}, []int{1, 2, 3, 4, 5, 6, 7, 8}),
+			unavailable: []string{"A1", "A8"},
+			party:       5,
+			want:        []string{"A2", "A3", "A4", "A5", "A6"},
+		},
+		{
+			name:   "AisleSplitsTheRow",
+			layout: withAisle,
+			party:  4,
+			want:   nil,
+		},
+		{
+			name: "SkipsDisabledAndWheelchairSeats",
+			layout: []Seat{
+				{Row: 0, Col: 0, Label: "A1", Category: SeatStandard},
+				{Row: 0, Col: 1, Label: "A2", Category: SeatStandard, Disabled: true},
+				{Row: 0, Col: 2, Label: "A3", Category: SeatWheelchair, Accessible: true},
+				{Row: 0, Col: 3, Label: "A4", Category: SeatStandard},
+				{Row: 0, Col: 4, Label: "A5", Category: SeatStandard},
+			},
+			party: 2,
+			want:  []string{"A4", "A5"},
+		},
+		{
+			name:        "Full",
+			layout:      BuildLayout([]string{"A"}, []int{1, 2}),
+			unavailable: []string{"A1"},
+			party:       2,
+			want:        nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			unavailable := make(map[string]bool)
+			for _, seat := range tc.unavailable {
+				unavailable[seat] = true
+			}
+			require.Equal(t, tc.want, BestAvailableSeats(tc.layout, unavailable, tc.party))
+		})
+	}
+}
+
+func TestUnavailableSeats(t *testing.T) {
+	now := time.Now()
+	repertoire := Repertoire{
+		ReservSeats:  []string{"A1"},
+		BlockedSeats: []BlockedSeat{{Seat: "A2"}},
+		HeldSeats: []SeatHold{
+			{Seat: "A3", Username: "ana", ExpiresAt: now.Add(time.Minute)},
+			{Seat: "A4", Username: "ana", ExpiresAt: now.Add(-time.Minute)},
+			{Seat: "A5", Username: "marko", ExpiresAt: now.Add(time.Minute)},
+		},
+	}
+
+	require.Equal(t, map[string]bool{"A1": true, "A2": true, "A5": true}, repertoire.UnavailableSeats("ana", now))
+	require.Equal(t, map[string]bool{"A1": true, "A2": true, "A3": true}, repertoire.UnavailableSeats("marko", now))
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	// cinemas can name any IANA time zone, also where the system has no tzdata
+	_ "time/tzdata"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Cinema is one location of the chain. It owns halls, and with them the
+// repertoires scheduled in them. Dates and times of its screenings are local
+// to TimeZone.
+type Cinema struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Name      string             `bson:"name" json:"name"`
+	City      string             `bson:"city,omitempty" json:"city,omitempty"`
+	Address   string             `bson:"address,omitempty" json:"address,omitempty"`
+	TimeZone  string             `bson:"timeZone" json:"timeZone"`
+	Pricing   Pricing            `bson:"pricing" json:"pricing"`
+	CreatedAt time.Time          `bson:"creation_date,omitempty" json:"creation_date,omitempty"`
+	DeletedAt *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy string             `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+}
+
+// Pricing holds the ticket prices of a cinema per seat category, in the
+// smallest unit of Currency. Categories without a price cost as standard seats.
+type Pricing struct {
+	Currency string           `bson:"currency,omitempty" json:"currency,omitempty"`
+	Prices   map[string]int64 `bson:"prices,omitempty" json:"prices,omitempty"`
+}
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ValidateCinema checks the name, the time zone and the prices of a cinema
+func ValidateCinema(cinema *Cinema) error {
+	if cinema.Name == "" {
+		return errors.New("name is required")
+	}
+	if cinema.TimeZone == "" {
+		return errors.New("timeZone is required")
+	}
+	if _, err := time.LoadLocation(cinema.TimeZone); err != nil {
+		return fmt.Errorf("unknown timeZone %q", cinema.TimeZone)
+	}
+
+	if len(cinema.Pricing.Prices) == 0 {
+		return nil
+	}
+	if !currencyCode.MatchString(cinema.Pricing.Currency) {
+		return errors.New("pricing currency must be a 3 letter ISO 4217 code")
+	}
+	for category, price := range cinema.Pricing.Prices {
+		if !IsValidSeatCategory(category) {
+			return fmt.Errorf("pricing has unknown seat category %q", category)
+		}
+		if price < 0 {
+			return fmt.Errorf("price of %s seats must not be negative", category)
+		}
+	}
+	return nil
+}
+
+// Location returns the time zone of the cinema, UTC when it has none
+func (c *Cinema) Location() *time.Location {
+	location, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// Today returns the current date at the cinema, as the UTC midnight the
+// repertoires are stored with
+func (c *Cinema) Today(now time.Time) time.Time {
+	year, month, day := now.In(c.Location()).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Price returns the price of a seat of the given category
+func (c *Cinema) Price(category string) int64 {
+	if price, ok := c.Pricing.Prices[category]; ok {
+		return price
+	}
+	return c.Pricing.Prices[SeatStandard]
+}
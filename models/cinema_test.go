@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCinema(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cinema Cinema
+		err    string
+	}{
+		{
+			name: "OK",
+			cinema: Cinema{Name: "Beograd", TimeZone: "Europe/Belgrade", Pricing: Pricing{
+				Currency: "RSD",
+				Prices:   map[string]int64{SeatStandard: 60000, SeatVIP: 90000},
+			}},
+		},
+		{
+			name:   "WithoutPricing",
+			cinema: Cinema{Name: "Novi Sad", TimeZone: "Europe/Belgrade"},
+		},
+		{
+			name:   "NoName",
+			cinema: Cinema{TimeZone: "UTC"},
+			err:    "name is required",
+		},
+		{
+			name:   "NoTimeZone",
+			cinema: Cinema{Name: "Beograd"},
+			err:    "timeZone is required",
+		},
+		{
+			name:   "UnknownTimeZone",
+			cinema: Cinema{Name: "Beograd", TimeZone: "Europe/Atlantis"},
+			err:    `unknown timeZone "Europe/Atlantis"`,
+		},
+		{
+			name:   "InvalidCurrency",
+			cinema: Cinema{Name: "Beograd", TimeZone: "UTC", Pricing: Pricing{Currency: "din", Prices: map[string]int64{SeatStandard: 1}}},
+			err:    "pricing currency must be a 3 letter ISO 4217 code",
+		},
+		{
+			name:   "UnknownCategory",
+			cinema: Cinema{Name: "Beograd", TimeZone: "UTC", Pricing: Pricing{Currency: "EUR", Prices: map[string]int64{"balcony": 1}}},
+			err:    `pricing has unknown seat category "balcony"`,
+		},
+		{
+			name:   "NegativePrice",
+			cinema: Cinema{Name: "Beograd", TimeZone: "UTC", Pricing: Pricing{Currency: "EUR", Prices: map[string]int64{SeatVIP: -1}}},
+			err:    "price of vip seats must not be negative",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateCinema(&tc.cinema)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestCinemaToday(t *testing.T) {
+	// 23:30 UTC on May 31 is already June 1 in Belgrade, but not in New York
+	now := time.Date(2024, time.May, 31, 23, 30, 0, 0, time.UTC)
+
+	belgrade := Cinema{TimeZone: "Europe/Belgrade"}
+	require.Equal(t, time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), belgrade.Today(now))
+
+	newYork := Cinema{TimeZone: "America/New_York"}
+	require.Equal(t, time.Date(2024, time.May, 31, 0, 0, 0, 0, time.UTC), newYork.Today(now))
+}
+
+func TestCinemaPrice(t *testing.T) {
+	cinema := Cinema{Pricing: Pricing{Currency: "RSD", Prices: map[string]int64{SeatStandard: 600, SeatVIP: 900}}}
+	require.Equal(t, int64(900), cinema.Price(SeatVIP))
+	require.Equal(t, int64(600), cinema.Price(SeatCouple))
+}
@@ -6,13 +6,19 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// Hall predstavlja podatke o bioskopskoj sali
+// Hall predstavlja podatke o bioskopskoj sali. CinemaID is the cinema the
+// hall belongs to. Seats is the seat map; when it is not given it is
+// generated from Rows and Cols.
 type Hall struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	CinemaID  primitive.ObjectID `bson:"cinemaId,omitempty" json:"cinemaId,omitempty"`
 	Name      string             `bson:"name,omitempty" json:"name,omitempty"`
 	Rows      []string           `bson:"rows,omitempty" json:"rows,omitempty"`
 	Cols      []int              `bson:"cols,omitempty" json:"cols,omitempty"`
+	Seats     []Seat             `bson:"seats,omitempty" json:"seats,omitempty"`
 	CreatedAt time.Time          `bson:"creation_date,omitempty" json:"creation_date,omitempty"`
+	DeletedAt *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy string             `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
 }
 
 // type ListLimitOffsetParams struct {
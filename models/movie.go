@@ -8,22 +8,67 @@ import (
 
 // Movie predstavlja podatke o filmu
 type Movie struct {
-	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Title      string             `bson:"title,omitempty" json:"title,omitempty"`
-	Duration   int32              `bson:"duration,omitempty" json:"duration,omitempty"`
-	Genre      string             `bson:"genre,omitempty" json:"genre,omitempty"`
-	Directors  string             `bson:"directors,omitempty" json:"directors,omitempty"`
-	Actors     string             `bson:"actors,omitempty" json:"actors,omitempty"`
-	Screening  time.Time          `bson:"screening,omitempty" json:"screening,omitempty"`
-	Plot       string             `bson:"plot,omitempty" json:"plot,omitempty"`
-	Poster     string             `bson:"poster,omitempty" json:"poster,omitempty"`
-	CreatedAt  time.Time          `bson:"creation_date,omitempty" json:"creation_date,omitempty"`
-	Screenings []Screening        `bson:"screenings" json:"screenings,omitempty"`
+	ID           primitive.ObjectID          `bson:"_id,omitempty" json:"id,omitempty"`
+	Title        string                      `bson:"title,omitempty" json:"title,omitempty"`
+	Duration     int32                       `bson:"duration,omitempty" json:"duration,omitempty"`
+	Genre        StringList                  `bson:"genre,omitempty" json:"genre,omitempty" swaggertype:"array,string"`
+	Directors    StringList                  `bson:"directors,omitempty" json:"directors,omitempty" swaggertype:"array,string"`
+	Actors       StringList                  `bson:"actors,omitempty" json:"actors,omitempty" swaggertype:"array,string"`
+	Screening    time.Time                   `bson:"screening,omitempty" json:"screening,omitempty"`
+	Plot         string                      `bson:"plot,omitempty" json:"plot,omitempty"`
+	Translations map[string]MovieTranslation `bson:"translations,omitempty" json:"translations,omitempty"`
+	Poster       string                      `bson:"poster,omitempty" json:"poster,omitempty"`
+	PosterSizes  map[string]string           `bson:"poster_sizes,omitempty" json:"poster_sizes,omitempty"`
+	CreatedAt    time.Time                   `bson:"creation_date,omitempty" json:"creation_date,omitempty"`
+	Screenings   []Screening                 `bson:"screenings" json:"screenings,omitempty"`
+	Score        float64                     `bson:"score,omitempty" json:"score,omitempty"`
+	AvgRating    float64                     `bson:"avgRating,omitempty" json:"avgRating,omitempty"`
+	NumOfRatings int                         `bson:"numOfRatings,omitempty" json:"numOfRatings,omitempty"`
+	AgeRating    int                         `bson:"ageRating,omitempty" json:"ageRating,omitempty" binding:"omitempty,oneof=12 14 16 18"`
+	DeletedAt    *time.Time                  `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy    string                      `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
 }
 
 // Screening represents the structure of screening subdocuments
 type Screening struct {
-	Date time.Time `bson:"date"`
-	Time string    `bson:"time"`
-	Hall string    `bson:"hall"`
+	Date   time.Time          `bson:"date"`
+	Time   string             `bson:"time"`
+	HallID primitive.ObjectID `bson:"hallId,omitempty"`
+	Hall   string             `bson:"hall"`
+}
+
+// MovieSchedule is a movie together with its upcoming showtimes
+type MovieSchedule struct {
+	Movie    `bson:",inline"`
+	Schedule []ScheduleDay `bson:"schedule" json:"schedule"`
+}
+
+// ScheduleDay groups the showtimes of one day by hall
+type ScheduleDay struct {
+	Date  time.Time      `bson:"date" json:"date"`
+	Halls []ScheduleHall `bson:"halls" json:"halls"`
+}
+
+// ScheduleHall lists the start times of a movie in one hall
+type ScheduleHall struct {
+	HallID primitive.ObjectID `bson:"hallId" json:"hallId"`
+	Hall   string             `bson:"hall" json:"hall"`
+	Times  []string           `bson:"times" json:"times"`
+}
+
+// AgeRatings are the allowed movie age ratings (the minimum age of a viewer);
+// a zero AgeRating means the movie is suitable for all ages.
+var AgeRatings = []int{12, 14, 16, 18}
+
+// IsValidAgeRating reports whether rating is zero or one of AgeRatings
+func IsValidAgeRating(rating int) bool {
+	if rating == 0 {
+		return true
+	}
+	for _, allowed := range AgeRatings {
+		if allowed == rating {
+			return true
+		}
+	}
+	return false
 }
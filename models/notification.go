@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// NotificationScreeningCancelled is sent when a screening with the user's
+// reservation is cancelled
+const NotificationScreeningCancelled = "screening_cancelled"
+
+// Notification is a message for a single user
+type Notification struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Username      string             `bson:"username" json:"username"`
+	Type          string             `bson:"type" json:"type"`
+	Message       string             `bson:"message" json:"message"`
+	ReservationID primitive.ObjectID `bson:"reservationId,omitempty" json:"reservationId,omitempty"`
+	CreatedAt     time.Time          `bson:"creation_date" json:"creation_date"`
+}
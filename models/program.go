@@ -0,0 +1,26 @@
+package models
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProgramHall predstavlja program jedne sale za jedan dan
+type ProgramHall struct {
+	HallID     primitive.ObjectID `bson:"hallId" json:"hallId"`
+	Hall       string             `bson:"hall" json:"hall"`
+	Screenings []ProgramScreening `bson:"screenings" json:"screenings"`
+}
+
+// ProgramScreening is a single screening of the daily program with its occupancy
+type ProgramScreening struct {
+	RepertoireID      primitive.ObjectID `bson:"repertoireId" json:"repertoireId"`
+	MovieID           primitive.ObjectID `bson:"movieId" json:"movieId"`
+	MovieTitle        string             `bson:"movieTitle" json:"movieTitle"`
+	Duration          int32              `bson:"duration" json:"duration"`
+	Time              string             `bson:"time" json:"time"`
+	NumOfTickets      int                `bson:"numOfTickets" json:"numOfTickets"`
+	NumOfResTickets   int                `bson:"numOfResTickets" json:"numOfResTickets"`
+	NumOfBlockedSeats int                `bson:"numOfBlockedSeats" json:"numOfBlockedSeats"`
+	AvailableTickets  int                `bson:"availableTickets" json:"availableTickets"`
+	Occupancy         float64            `bson:"occupancy" json:"occupancy"`
+}
@@ -6,16 +6,85 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// Repertoire predstavlja jednu projekciju filma
+// Repertoire predstavlja jednu projekciju filma. HallID references the hall,
+// Hall keeps its name for display and CinemaID is the cinema of the hall. BlockedSeats are kept out of sale for this
+// screening only and count against NumOfTickets like reserved ones. HeldSeats
+// are kept for one user for a short time, see SeatHold.
 type Repertoire struct {
 	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	MovieID         primitive.ObjectID `bson:"movieId,omitempty" json:"movieId,omitempty"`
 	DateSt          string             `bson:"dateSt,omitempty" json:"dateSt,omitempty"`
 	Date            time.Time          `bson:"date,omitempty" json:"date,omitempty"`
 	Time            string             `bson:"time,omitempty" json:"time,omitempty"`
+	CinemaID        primitive.ObjectID `bson:"cinemaId,omitempty" json:"cinemaId,omitempty"`
+	HallID          primitive.ObjectID `bson:"hallId,omitempty" json:"hallId,omitempty"`
 	Hall            string             `bson:"hall,omitempty" json:"hall,omitempty"`
 	NumOfTickets    int                `bson:"numOfTickets,omitempty" json:"numOfTickets,omitempty"`
 	NumOfResTickets int                `bson:"numOfResTickets" json:"numOfResTickets"`
 	CreatedAt       time.Time          `bson:"creation_date,omitempty" json:"creation_date,omitempty"`
 	ReservSeats     []string           `bson:"reservSeats,omitempty" json:"reservSeats,omitempty"`
+	BlockedSeats    []BlockedSeat      `bson:"blockedSeats,omitempty" json:"blockedSeats,omitempty"`
+	HeldSeats       []SeatHold         `bson:"heldSeats,omitempty" json:"heldSeats,omitempty"`
+	DeletedAt       *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy       string             `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+}
+
+// BlockedSeat is a seat taken out of sale for one screening, e.g. because it
+// is broken or has to stay empty for distancing
+type BlockedSeat struct {
+	Seat      string    `bson:"seat" json:"seat"`
+	Reason    string    `bson:"reason" json:"reason"`
+	BlockedBy string    `bson:"blockedBy,omitempty" json:"blockedBy,omitempty"`
+	BlockedAt time.Time `bson:"blockedAt" json:"blockedAt"`
+}
+
+// SeatHold keeps a seat for a user until ExpiresAt, so nobody else can book
+// it meanwhile. Expired holds are ignored and dropped on the next hold.
+type SeatHold struct {
+	Seat      string    `bson:"seat" json:"seat"`
+	Username  string    `bson:"username" json:"-"`
+	ExpiresAt time.Time `bson:"expiresAt" json:"expiresAt"`
+}
+
+// IsBlocked reports whether the seat is blocked for this screening
+func (r *Repertoire) IsBlocked(seat string) bool {
+	for _, blocked := range r.BlockedSeats {
+		if blocked.Seat == seat {
+			return true
+		}
+	}
+	return false
+}
+
+// AvailableTickets returns the number of tickets that can still be sold
+func (r *Repertoire) AvailableTickets() int {
+	available := r.NumOfTickets - r.NumOfResTickets - len(r.BlockedSeats)
+	if available < 0 {
+		return 0
+	}
+	return available
+}
+
+// HeldByOthers returns the seats held at now for users other than username
+func (r *Repertoire) HeldByOthers(username string, now time.Time) map[string]bool {
+	held := make(map[string]bool)
+	for _, hold := range r.HeldSeats {
+		if hold.Username != username && hold.ExpiresAt.After(now) {
+			held[hold.Seat] = true
+		}
+	}
+	return held
+}
+
+// UnavailableSeats returns the seats username cannot book at now: the
+// reserved, the blocked and the ones held for someone else
+func (r *Repertoire) UnavailableSeats(username string, now time.Time) map[string]bool {
+	unavailable := r.HeldByOthers(username, now)
+	for _, seat := range r.ReservSeats {
+		unavailable[seat] = true
+	}
+	for _, blocked := range r.BlockedSeats {
+		unavailable[blocked.Seat] = true
+	}
+	return unavailable
 }
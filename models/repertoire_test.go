@@ -0,0 +1,21 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepertoireBlockedSeats(t *testing.T) {
+	repertoire := Repertoire{
+		NumOfTickets:    4,
+		NumOfResTickets: 2,
+		BlockedSeats:    []BlockedSeat{{Seat: "A1", Reason: "broken"}},
+	}
+	require.True(t, repertoire.IsBlocked("A1"))
+	require.False(t, repertoire.IsBlocked("A2"))
+	require.Equal(t, 1, repertoire.AvailableTickets())
+
+	repertoire.BlockedSeats = append(repertoire.BlockedSeats, BlockedSeat{Seat: "A2"}, BlockedSeat{Seat: "A3"})
+	require.Zero(t, repertoire.AvailableTickets())
+}
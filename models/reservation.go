@@ -16,7 +16,15 @@ type Reservation struct {
 	MovieTitle    string             `bson:"movieTitle,omitempty" json:"movieTitle,omitempty"`
 	Date          time.Time          `bson:"date,omitempty" json:"date,omitempty"`
 	Time          string             `bson:"time,omitempty" json:"time,omitempty"`
+	CinemaID      primitive.ObjectID `bson:"cinemaId,omitempty" json:"cinemaId,omitempty"`
+	HallID        primitive.ObjectID `bson:"hallId,omitempty" json:"hallId,omitempty"`
 	Hall          string             `bson:"hall,omitempty" json:"hall,omitempty"`
 	CreationDate  time.Time          `bson:"creationDate,omitempty" json:"creationDate,omitempty"`
 	ReservSeats   []string           `bson:"reservSeats,omitempty" json:"reservSeats,omitempty"`
+	CheckedIn     bool               `bson:"checkedIn,omitempty" json:"checkedIn,omitempty"`
+	// RequiresIDCheck tells the staff to check the viewer's ID at the entrance
+	RequiresIDCheck bool `bson:"requiresIdCheck,omitempty" json:"requiresIdCheck,omitempty"`
+	// Price is the total of the seats by the pricing of the cinema
+	Price    int64  `bson:"price,omitempty" json:"price,omitempty"`
+	Currency string `bson:"currency,omitempty" json:"currency,omitempty"`
 }
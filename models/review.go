@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Review statuses; only published reviews are shown and counted in the average rating
+const (
+	ReviewStatusPublished = "published"
+	ReviewStatusHidden    = "hidden"
+)
+
+// Review predstavlja ocenu i komentar korisnika za film
+type Review struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	MovieID     primitive.ObjectID `bson:"movieId,omitempty" json:"movieId,omitempty"`
+	UserID      primitive.ObjectID `bson:"userId,omitempty" json:"userId,omitempty"`
+	Username    string             `bson:"username,omitempty" json:"username,omitempty"`
+	Rating      int                `bson:"rating" json:"rating"`
+	Comment     string             `bson:"comment,omitempty" json:"comment,omitempty"`
+	Status      string             `bson:"status,omitempty" json:"status,omitempty"`
+	CreatedAt   time.Time          `bson:"creation_date,omitempty" json:"creation_date,omitempty"`
+	ModeratedBy string             `bson:"moderatedBy,omitempty" json:"moderatedBy,omitempty"`
+	ModeratedAt time.Time          `bson:"moderatedAt,omitempty" json:"moderatedAt,omitempty"`
+}
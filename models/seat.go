@@ -0,0 +1,164 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Seat categories
+const (
+	SeatStandard   = "standard"
+	SeatVIP        = "vip"
+	SeatCouple     = "couple"
+	SeatWheelchair = "wheelchair"
+)
+
+// SeatCategories are the allowed seat categories
+var SeatCategories = []string{SeatStandard, SeatVIP, SeatCouple, SeatWheelchair}
+
+// Seat is one place in the seat map of a hall. Row and Col are its position in
+// the grid, counted from zero, so aisles and missing seats are simply gaps.
+// Label is what tickets and reservations refer to, e.g. "A1".
+type Seat struct {
+	Row        int    `bson:"row" json:"row"`
+	Col        int    `bson:"col" json:"col"`
+	Label      string `bson:"label" json:"label"`
+	Category   string `bson:"category" json:"category"`
+	Accessible bool   `bson:"accessible,omitempty" json:"accessible,omitempty"`
+	// Disabled seats are shown in the map but never sold, e.g. broken ones
+	Disabled bool `bson:"disabled,omitempty" json:"disabled,omitempty"`
+}
+
+// BuildLayout generates a rectangular seat map of standard seats, labeled by
+// the row name followed by the column number
+func BuildLayout(rows []string, cols []int) []Seat {
+	seats := make([]Seat, 0, len(rows)*len(cols))
+	for i, row := range rows {
+		for j, col := range cols {
+			seats = append(seats, Seat{
+				Row:      i,
+				Col:      j,
+				Label:    row + strconv.Itoa(col),
+				Category: SeatStandard,
+			})
+		}
+	}
+	return seats
+}
+
+// IsValidSeatCategory reports whether category is one of SeatCategories
+func IsValidSeatCategory(category string) bool {
+	for _, allowed := range SeatCategories {
+		if allowed == category {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateLayout checks that every seat has a label and a known category, that
+// no two seats share a label or a position and that some seat can be sold
+func ValidateLayout(seats []Seat) error {
+	if len(seats) == 0 {
+		return errors.New("layout has no seats")
+	}
+
+	type position struct{ row, col int }
+	labels := make(map[string]bool, len(seats))
+	positions := make(map[position]string, len(seats))
+	sellable := 0
+	for _, seat := range seats {
+		if seat.Label == "" {
+			return fmt.Errorf("seat at row %d, col %d has no label", seat.Row, seat.Col)
+		}
+		if seat.Row < 0 || seat.Col < 0 {
+			return fmt.Errorf("seat %s has a negative position", seat.Label)
+		}
+		if !IsValidSeatCategory(seat.Category) {
+			return fmt.Errorf("seat %s has unknown category %q", seat.Label, seat.Category)
+		}
+		if seat.Category == SeatWheelchair && !seat.Accessible {
+			return fmt.Errorf("wheelchair space %s must be accessible", seat.Label)
+		}
+		if labels[seat.Label] {
+			return fmt.Errorf("duplicate seat %s", seat.Label)
+		}
+		labels[seat.Label] = true
+
+		p := position{seat.Row, seat.Col}
+		if other, ok := positions[p]; ok {
+			return fmt.Errorf("seats %s and %s have the same position", other, seat.Label)
+		}
+		positions[p] = seat.Label
+
+		if !seat.Disabled {
+			sellable++
+		}
+	}
+
+	if sellable == 0 {
+		return errors.New("layout has no sellable seats")
+	}
+	return nil
+}
+
+// ValidateHall checks the name, the cinema and the seat map of a hall. A hall
+// without Seats needs valid Rows and Cols to generate them from.
+func ValidateHall(hall *Hall) error {
+	if hall.Name == "" {
+		return errors.New("name is required")
+	}
+	if hall.CinemaID.IsZero() {
+		return errors.New("cinemaId is required")
+	}
+	if len(hall.Seats) > 0 {
+		return ValidateLayout(hall.Seats)
+	}
+
+	if len(hall.Rows) == 0 {
+		return errors.New("rows are required")
+	}
+	if len(hall.Cols) == 0 {
+		return errors.New("cols are required")
+	}
+
+	rows := make(map[string]bool, len(hall.Rows))
+	for _, name := range hall.Rows {
+		if rows[name] {
+			return fmt.Errorf("duplicate row %q", name)
+		}
+		rows[name] = true
+	}
+	cols := make(map[int]bool, len(hall.Cols))
+	for _, col := range hall.Cols {
+		if col <= 0 {
+			return fmt.Errorf("invalid column %d", col)
+		}
+		if cols[col] {
+			return fmt.Errorf("duplicate column %d", col)
+		}
+		cols[col] = true
+	}
+	return nil
+}
+
+// Layout returns the seat map of the hall; halls stored before seat maps
+// existed get one generated from Rows and Cols
+func (h *Hall) Layout() []Seat {
+	if len(h.Seats) > 0 {
+		return h.Seats
+	}
+	return BuildLayout(h.Rows, h.Cols)
+}
+
+// SellableSeats returns the labels of the seats that can be sold
+func (h *Hall) SellableSeats() []string {
+	labels := make([]string, 0)
+	for _, seat := range h.Layout() {
+		if !seat.Disabled {
+			labels = append(labels, seat.Label)
+		}
+	}
+	return labels
+}
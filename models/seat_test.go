@@ -0,0 +1,111 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBuildLayout(t *testing.T) {
+	seats := BuildLayout([]string{"A", "B"}, []int{1, 2, 3})
+	require.Len(t, seats, 6)
+	require.Equal(t, Seat{Row: 0, Col: 0, Label: "A1", Category: SeatStandard}, seats[0])
+	require.Equal(t, Seat{Row: 1, Col: 2, Label: "B3", Category: SeatStandard}, seats[5])
+	require.NoError(t, ValidateLayout(seats))
+}
+
+func TestValidateLayout(t *testing.T) {
+	testCases := []struct {
+		name  string
+		seats []Seat
+		err   string
+	}{
+		{
+			name: "WithAisleAndSpecialSeats",
+			seats: []Seat{
+				{Row: 0, Col: 0, Label: "A1", Category: SeatVIP},
+				{Row: 0, Col: 2, Label: "A2", Category: SeatCouple},
+				{Row: 1, Col: 0, Label: "B1", Category: SeatWheelchair, Accessible: true},
+				{Row: 1, Col: 2, Label: "B2", Category: SeatStandard, Disabled: true},
+			},
+		},
+		{
+			name:  "Empty",
+			seats: nil,
+			err:   "layout has no seats",
+		},
+		{
+			name:  "NoLabel",
+			seats: []Seat{{Row: 2, Col: 3, Category: SeatStandard}},
+			err:   "seat at row 2, col 3 has no label",
+		},
+		{
+			name:  "UnknownCategory",
+			seats: []Seat{{Label: "A1", Category: "balcony"}},
+			err:   `seat A1 has unknown category "balcony"`,
+		},
+		{
+			name:  "WheelchairNotAccessible",
+			seats: []Seat{{Label: "A1", Category: SeatWheelchair}},
+			err:   "wheelchair space A1 must be accessible",
+		},
+		{
+			name: "DuplicateLabel",
+			seats: []Seat{
+				{Row: 0, Col: 0, Label: "A1", Category: SeatStandard},
+				{Row: 0, Col: 1, Label: "A1", Category: SeatStandard},
+			},
+			err: "duplicate seat A1",
+		},
+		{
+			name: "SamePosition",
+			seats: []Seat{
+				{Row: 0, Col: 0, Label: "A1", Category: SeatStandard},
+				{Row: 0, Col: 0, Label: "A2", Category: SeatStandard},
+			},
+			err: "seats A1 and A2 have the same position",
+		},
+		{
+			name:  "NothingSellable",
+			seats: []Seat{{Label: "A1", Category: SeatStandard, Disabled: true}},
+			err:   "layout has no sellable seats",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateLayout(tc.seats)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestValidateHall(t *testing.T) {
+	cinemaID := primitive.NewObjectID()
+
+	require.EqualError(t, ValidateHall(&Hall{CinemaID: cinemaID, Rows: []string{"A"}, Cols: []int{1}}), "name is required")
+	require.EqualError(t, ValidateHall(&Hall{Name: "Sala 1", Rows: []string{"A"}, Cols: []int{1}}), "cinemaId is required")
+	require.EqualError(t, ValidateHall(&Hall{Name: "Sala 1", CinemaID: cinemaID, Cols: []int{1}}), "rows are required")
+	require.EqualError(t, ValidateHall(&Hall{Name: "Sala 1", CinemaID: cinemaID, Rows: []string{"A"}, Cols: []int{0}}), "invalid column 0")
+	require.NoError(t, ValidateHall(&Hall{Name: "Sala 1", CinemaID: cinemaID, Rows: []string{"A"}, Cols: []int{1, 2}}))
+
+	// a seat map makes rows and cols optional
+	require.NoError(t, ValidateHall(&Hall{Name: "Sala 1", CinemaID: cinemaID, Seats: []Seat{{Label: "A1", Category: SeatStandard}}}))
+}
+
+func TestSellableSeats(t *testing.T) {
+	legacy := Hall{Rows: []string{"A"}, Cols: []int{1, 2}}
+	require.Equal(t, []string{"A1", "A2"}, legacy.SellableSeats())
+
+	hall := Hall{Seats: []Seat{
+		{Row: 0, Col: 0, Label: "A1", Category: SeatStandard},
+		{Row: 0, Col: 1, Label: "A2", Category: SeatStandard, Disabled: true},
+		{Row: 0, Col: 3, Label: "A3", Category: SeatWheelchair, Accessible: true},
+	}}
+	require.Equal(t, []string{"A1", "A3"}, hall.SellableSeats())
+}
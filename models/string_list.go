@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// StringList is a list of names (genres, directors, actors).
+// It also accepts the older comma-separated string form, both in JSON input
+// and in documents that have not been migrated yet.
+type StringList []string
+
+// SplitStringList splits a comma-separated string into a StringList, dropping empty entries
+func SplitStringList(value string) StringList {
+	list := make(StringList, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// UnmarshalJSON accepts either a JSON array of strings or a comma-separated string
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*l = SplitStringList(value)
+		return nil
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("expected a list of strings or a comma-separated string: %w", err)
+	}
+	*l = values
+	return nil
+}
+
+// UnmarshalBSONValue accepts either a BSON array of strings or a comma-separated string
+func (l *StringList) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	raw := bson.RawValue{Type: t, Value: data}
+	switch t {
+	case bsontype.Null, bsontype.Undefined:
+		*l = nil
+		return nil
+	case bsontype.String:
+		*l = SplitStringList(raw.StringValue())
+		return nil
+	}
+
+	var values []string
+	if err := raw.Unmarshal(&values); err != nil {
+		return err
+	}
+	*l = values
+	return nil
+}
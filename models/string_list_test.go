@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestStringListUnmarshalJSON(t *testing.T) {
+	var movie Movie
+	err := json.Unmarshal([]byte(`{"genre":"drama, komedija ,","actors":["Leonardo Di Kaprio","Kejt Vinslet"]}`), &movie)
+	require.NoError(t, err)
+	require.Equal(t, StringList{"drama", "komedija"}, movie.Genre)
+	require.Equal(t, StringList{"Leonardo Di Kaprio", "Kejt Vinslet"}, movie.Actors)
+	require.Nil(t, movie.Directors)
+
+	err = json.Unmarshal([]byte(`{"genre":42}`), &movie)
+	require.Error(t, err)
+}
+
+func TestStringListUnmarshalBSON(t *testing.T) {
+	legacy, err := bson.Marshal(bson.M{"directors": "Kameron, Spilberg"})
+	require.NoError(t, err)
+
+	var movie Movie
+	require.NoError(t, bson.Unmarshal(legacy, &movie))
+	require.Equal(t, StringList{"Kameron", "Spilberg"}, movie.Directors)
+
+	current, err := bson.Marshal(Movie{Actors: StringList{"Leonardo Di Kaprio"}})
+	require.NoError(t, err)
+
+	movie = Movie{}
+	require.NoError(t, bson.Unmarshal(current, &movie))
+	require.Equal(t, StringList{"Leonardo Di Kaprio"}, movie.Actors)
+}
@@ -0,0 +1,70 @@
+package models
+
+import "github.com/tijanadmi/movieginmongoapi/util"
+
+// Supported content languages. The Title and Plot of a movie are written in
+// DefaultLanguage; other languages are kept in Movie.Translations.
+const (
+	LanguageSerbianLatin    = "sr-Latn"
+	LanguageSerbianCyrillic = "sr-Cyrl"
+	LanguageEnglish         = "en"
+
+	DefaultLanguage = LanguageSerbianLatin
+)
+
+// SupportedLanguages lists the content languages, the default one first
+var SupportedLanguages = []string{LanguageSerbianLatin, LanguageSerbianCyrillic, LanguageEnglish}
+
+// MovieTranslation holds the localized metadata of a movie
+type MovieTranslation struct {
+	Title string `bson:"title,omitempty" json:"title,omitempty"`
+	Plot  string `bson:"plot,omitempty" json:"plot,omitempty"`
+}
+
+// IsSupportedLanguage reports whether lang is one of SupportedLanguages
+func IsSupportedLanguage(lang string) bool {
+	for _, supported := range SupportedLanguages {
+		if supported == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// Localize replaces the title and plot of the movie with their translation to
+// lang. A missing Serbian translation is transliterated from the other Serbian
+// script, and anything still missing falls back to the default language. Text
+// that is not Serbian, e.g. an original English title, is never transliterated.
+func (m *Movie) Localize(lang string) {
+	m.Title = m.localized(lang, func(t MovieTranslation) string { return t.Title }, m.Title)
+	m.Plot = m.localized(lang, func(t MovieTranslation) string { return t.Plot }, m.Plot)
+}
+
+func (m *Movie) localized(lang string, field func(MovieTranslation) string, fallback string) string {
+	if value := field(m.Translations[lang]); value != "" {
+		return value
+	}
+
+	switch lang {
+	case LanguageSerbianCyrillic:
+		if value := field(m.Translations[LanguageSerbianLatin]); value != "" {
+			return toCyrillic(value)
+		}
+		return toCyrillic(fallback)
+	case LanguageSerbianLatin:
+		if fallback == "" {
+			return util.ToLatin(field(m.Translations[LanguageSerbianCyrillic]))
+		}
+		return util.ToLatin(fallback)
+	}
+
+	return fallback
+}
+
+// toCyrillic transliterates s only when it is written in Serbian Latin
+func toCyrillic(s string) string {
+	if !util.IsSerbianLatin(s) {
+		return s
+	}
+	return util.ToCyrillic(s)
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieLocalize(t *testing.T) {
+	newMovie := func() Movie {
+		return Movie{
+			Title: "Ko to tamo peva",
+			Plot:  "Putovanje autobusom",
+			Translations: map[string]MovieTranslation{
+				LanguageEnglish: {Title: "Who's Singin' Over There?"},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name  string
+		lang  string
+		title string
+		plot  string
+	}{
+		{
+			name:  "Default",
+			lang:  DefaultLanguage,
+			title: "Ko to tamo peva",
+			plot:  "Putovanje autobusom",
+		},
+		{
+			name:  "Translated",
+			lang:  LanguageEnglish,
+			title: "Who's Singin' Over There?",
+			plot:  "Putovanje autobusom",
+		},
+		{
+			name:  "Transliterated",
+			lang:  LanguageSerbianCyrillic,
+			title: "Ко то тамо пева",
+			plot:  "Путовање аутобусом",
+		},
+		{
+			name:  "Unsupported",
+			lang:  "de",
+			title: "Ko to tamo peva",
+			plot:  "Putovanje autobusom",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			movie := newMovie()
+			movie.Localize(tc.lang)
+			require.Equal(t, tc.title, movie.Title)
+			require.Equal(t, tc.plot, movie.Plot)
+		})
+	}
+}
+
+func TestMovieLocalizeFromOtherScript(t *testing.T) {
+	movie := Movie{
+		Title: "Maratonci trče počasni krug",
+		Translations: map[string]MovieTranslation{
+			LanguageSerbianCyrillic: {Title: "Маратонци", Plot: "Породица Топаловић"},
+		},
+	}
+
+	movie.Localize(LanguageSerbianLatin)
+	require.Equal(t, "Maratonci trče počasni krug", movie.Title)
+	require.Equal(t, "Porodica Topalović", movie.Plot)
+}
+
+func TestMovieLocalizeForeignTitle(t *testing.T) {
+	movie := Movie{
+		Title: "The Matrix",
+		Plot:  "Haker saznaje istinu o svetu",
+	}
+
+	movie.Localize(LanguageSerbianCyrillic)
+	require.Equal(t, "The Matrix", movie.Title)
+	require.Equal(t, "Хакер сазнаје истину о свету", movie.Plot)
+
+	// an explicit translation is preferred over the original title
+	movie = Movie{
+		Title: "The Matrix",
+		Translations: map[string]MovieTranslation{
+			LanguageSerbianCyrillic: {Title: "Матрикс"},
+		},
+	}
+
+	movie.Localize(LanguageSerbianCyrillic)
+	require.Equal(t, "Матрикс", movie.Title)
+}
@@ -13,4 +13,5 @@ type User struct {
 	DateOfCreation   time.Time          `bson:"creation_date,omitempty" json:"creation_date,omitempty"`
 	DateOfLastUpdate time.Time          `bson:"update_date,omitempty" json:"update_date,omitempty"`
 	Roles            []string           `bson:"roles,omitempty" json:"roles,omitempty"`
+	DateOfBirth      time.Time          `bson:"dateOfBirth,omitempty" json:"dateOfBirth,omitempty"`
 }
@@ -0,0 +1,143 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often the memory store drops the entries that expired
+const pruneInterval = time.Minute
+
+type bucket struct {
+	tokens   float64
+	limit    int
+	interval time.Duration
+	updated  time.Time
+}
+
+type failures struct {
+	count   int
+	expires time.Time
+}
+
+// MemoryStore keeps the limits in the memory of the process, so every
+// instance of the API limits on its own
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	failures  map[string]failures
+	locks     map[string]time.Time
+	now       func() time.Time
+	lastPrune time.Time
+}
+
+// NewMemoryStore creates an empty memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  make(map[string]*bucket),
+		failures: make(map[string]failures),
+		locks:    make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit int, interval time.Duration) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.prune(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit), limit: limit, interval: interval, updated: now}
+		s.buckets[key] = b
+	}
+
+	// tokens per nanosecond
+	rate := float64(limit) / float64(interval)
+	b.tokens = math.Min(float64(limit), b.tokens+float64(now.Sub(b.updated))*rate)
+	b.updated = now
+
+	if b.tokens < 1 {
+		return time.Duration(math.Ceil((1 - b.tokens) / rate)), nil
+	}
+	b.tokens--
+	return 0, nil
+}
+
+func (s *MemoryStore) Fail(_ context.Context, key string, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	f := s.failures[key]
+	if !now.Before(f.expires) {
+		f = failures{expires: now.Add(window)}
+	}
+	f.count++
+	s.failures[key] = f
+
+	return f.count, nil
+}
+
+func (s *MemoryStore) Lock(_ context.Context, key string, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.locks[key] = s.now().Add(d)
+	return nil
+}
+
+func (s *MemoryStore) Locked(_ context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.locks[key]
+	if !ok {
+		return 0, nil
+	}
+
+	wait := until.Sub(s.now())
+	if wait <= 0 {
+		delete(s.locks, key)
+		return 0, nil
+	}
+	return wait, nil
+}
+
+func (s *MemoryStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+	delete(s.locks, key)
+	return nil
+}
+
+// prune drops the buckets that are full again and the failures and locks
+// that expired, so keys seen once do not pile up
+func (s *MemoryStore) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < pruneInterval {
+		return
+	}
+	s.lastPrune = now
+
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) >= b.interval {
+			delete(s.buckets, key)
+		}
+	}
+	for key, f := range s.failures {
+		if !now.Before(f.expires) {
+			delete(s.failures, key)
+		}
+	}
+	for key, until := range s.locks {
+		if !now.Before(until) {
+			delete(s.locks, key)
+		}
+	}
+}
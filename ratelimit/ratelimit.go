@@ -0,0 +1,96 @@
+// Package ratelimit throttles login attempts with token buckets and locks
+// usernames out after repeated failures
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Store keeps the token buckets, failure counts and locks by key
+type Store interface {
+	// Take takes a token from the bucket of key, which holds limit tokens and
+	// is refilled at limit tokens per interval. It returns how long to wait
+	// for the next token when the bucket is empty, zero otherwise.
+	Take(ctx context.Context, key string, limit int, interval time.Duration) (time.Duration, error)
+	// Fail counts a failure of key and returns the failures counted within
+	// window of the first one
+	Fail(ctx context.Context, key string, window time.Duration) (int, error)
+	// Lock locks key for d
+	Lock(ctx context.Context, key string, d time.Duration) error
+	// Locked returns how long key stays locked, zero when it is not locked
+	Locked(ctx context.Context, key string) (time.Duration, error)
+	// Reset forgets the failures and the lock of key
+	Reset(ctx context.Context, key string) error
+}
+
+// Limits configures a LoginGuard; all of them must be positive
+type Limits struct {
+	// PerIP is how many login attempts a minute one IP address may make
+	PerIP int
+	// PerUser is how many login attempts a minute one username may get
+	PerUser int
+	// MaxFailures is how many failed logins in a row lock a username out
+	MaxFailures int
+	// Lockout is how long a username stays locked out
+	Lockout time.Duration
+}
+
+// LoginGuard decides whether a login attempt may proceed. Attempts are
+// limited per IP address and per username, and a username with MaxFailures
+// failed logins within Lockout is locked out for Lockout.
+type LoginGuard struct {
+	store  Store
+	limits Limits
+}
+
+// NewLoginGuard creates a login guard
+func NewLoginGuard(store Store, limits Limits) *LoginGuard {
+	return &LoginGuard{
+		store:  store,
+		limits: limits,
+	}
+}
+
+// Allow returns how long the login of username from ip has to wait, zero
+// when it may proceed
+func (g *LoginGuard) Allow(ctx context.Context, ip, username string) (time.Duration, error) {
+	wait, err := g.store.Locked(ctx, userKey(username))
+	if err != nil || wait > 0 {
+		return wait, err
+	}
+
+	wait, err = g.store.Take(ctx, ipKey(ip), g.limits.PerIP, time.Minute)
+	if err != nil || wait > 0 {
+		return wait, err
+	}
+
+	return g.store.Take(ctx, userKey(username), g.limits.PerUser, time.Minute)
+}
+
+// Fail records a failed login of username and locks it out once it failed
+// too often
+func (g *LoginGuard) Fail(ctx context.Context, username string) error {
+	failures, err := g.store.Fail(ctx, userKey(username), g.limits.Lockout)
+	if err != nil {
+		return err
+	}
+	if failures < g.limits.MaxFailures {
+		return nil
+	}
+
+	return g.store.Lock(ctx, userKey(username), g.limits.Lockout)
+}
+
+// Succeed forgets the failed logins of username
+func (g *LoginGuard) Succeed(ctx context.Context, username string) error {
+	return g.store.Reset(ctx, userKey(username))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func userKey(username string) string {
+	return "user:" + username
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore is a store whose clock the test moves forward
+type testStore struct {
+	Store
+	advance func(d time.Duration)
+}
+
+func newTestStores(t *testing.T) map[string]testStore {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	memory := NewMemoryStore()
+	memory.now = func() time.Time { return now }
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	redisNow := now
+	rs := NewRedisStore(client)
+	rs.now = func() time.Time { return redisNow }
+
+	return map[string]testStore{
+		"Memory": {Store: memory, advance: func(d time.Duration) { now = now.Add(d) }},
+		"Redis": {Store: rs, advance: func(d time.Duration) {
+			redisNow = redisNow.Add(d)
+			server.FastForward(d)
+		}},
+	}
+}
+
+func TestTake(t *testing.T) {
+	for name, store := range newTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			for i := 0; i < 3; i++ {
+				wait, err := store.Take(ctx, "ip:10.0.0.1", 3, time.Minute)
+				require.NoError(t, err)
+				require.Zero(t, wait)
+			}
+
+			// the bucket is empty until a token is back, a third of a minute later
+			wait, err := store.Take(ctx, "ip:10.0.0.1", 3, time.Minute)
+			require.NoError(t, err)
+			require.Equal(t, 20*time.Second, wait)
+
+			// other keys have their own bucket
+			wait, err = store.Take(ctx, "ip:10.0.0.2", 3, time.Minute)
+			require.NoError(t, err)
+			require.Zero(t, wait)
+
+			store.advance(20 * time.Second)
+			wait, err = store.Take(ctx, "ip:10.0.0.1", 3, time.Minute)
+			require.NoError(t, err)
+			require.Zero(t, wait)
+		})
+	}
+}
+
+func TestLoginGuard(t *testing.T) {
+	for name, store := range newTestStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			guard := NewLoginGuard(store, Limits{PerIP: 100, PerUser: 100, MaxFailures: 3, Lockout: time.Minute})
+
+			for i := 0; i < 3; i++ {
+				wait, err := guard.Allow(ctx, "10.0.0.1", "alice")
+				require.NoError(t, err)
+				require.Zero(t, wait)
+				require.NoError(t, guard.Fail(ctx, "alice"))
+			}
+
+			// the third failure locks alice out, from any address
+			wait, err := guard.Allow(ctx, "10.0.0.2", "alice")
+			require.NoError(t, err)
+			require.Equal(t, time.Minute, wait)
+
+			wait, err = guard.Allow(ctx, "10.0.0.1", "bob")
+			require.NoError(t, err)
+			require.Zero(t, wait)
+
+			store.advance(time.Minute)
+			wait, err = guard.Allow(ctx, "10.0.0.1", "alice")
+			require.NoError(t, err)
+			require.Zero(t, wait)
+
+			// a successful login forgets the failures
+			require.NoError(t, guard.Fail(ctx, "bob"))
+			require.NoError(t, guard.Fail(ctx, "bob"))
+			require.NoError(t, guard.Succeed(ctx, "bob"))
+			require.NoError(t, guard.Fail(ctx, "bob"))
+			wait, err = guard.Allow(ctx, "10.0.0.1", "bob")
+			require.NoError(t, err)
+			require.Zero(t, wait)
+		})
+	}
+}
+
+func TestLoginGuardLimits(t *testing.T) {
+	ctx := context.Background()
+	guard := NewLoginGuard(NewMemoryStore(), Limits{PerIP: 2, PerUser: 1, MaxFailures: 5, Lockout: time.Minute})
+
+	// one attempt a minute per username
+	wait, err := guard.Allow(ctx, "10.0.0.1", "alice")
+	require.NoError(t, err)
+	require.Zero(t, wait)
+	wait, err = guard.Allow(ctx, "10.0.0.2", "alice")
+	require.NoError(t, err)
+	require.Positive(t, wait)
+
+	// the attempts of the address were used up by both usernames
+	wait, err = guard.Allow(ctx, "10.0.0.1", "bob")
+	require.NoError(t, err)
+	require.Zero(t, wait)
+	wait, err = guard.Allow(ctx, "10.0.0.1", "carol")
+	require.NoError(t, err)
+	require.Positive(t, wait)
+}
@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix keeps the keys of the limits apart from other data in Redis
+const keyPrefix = "movieapi:ratelimit:"
+
+// takeScript refills the bucket for the time since it was last updated and
+// takes a token from it, returning the milliseconds to wait when it is empty
+var takeScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(state[1]) or limit
+local updated = tonumber(state[2]) or now
+local rate = limit / interval
+tokens = math.min(limit, tokens + math.max(0, now - updated) * rate)
+local wait = 0
+if tokens < 1 then
+	wait = math.ceil((1 - tokens) / rate)
+else
+	tokens = tokens - 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated", now)
+redis.call("PEXPIRE", KEYS[1], interval)
+return wait
+`)
+
+// failScript counts a failure; the count expires a window after the first one
+var failScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// RedisStore keeps the limits in Redis, so they are shared by every instance
+// of the API
+type RedisStore struct {
+	client redis.UniversalClient
+	now    func() time.Time
+}
+
+// NewRedisStore creates a store that keeps the limits in Redis
+func NewRedisStore(client redis.UniversalClient) *RedisStore {
+	return &RedisStore{
+		client: client,
+		now:    time.Now,
+	}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit int, interval time.Duration) (time.Duration, error) {
+	wait, err := takeScript.Run(ctx, s.client,
+		[]string{keyPrefix + "bucket:" + key},
+		limit, interval.Milliseconds(), s.now().UnixMilli(),
+	).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
+
+func (s *RedisStore) Fail(ctx context.Context, key string, window time.Duration) (int, error) {
+	count, err := failScript.Run(ctx, s.client,
+		[]string{keyPrefix + "failures:" + key},
+		window.Milliseconds(),
+	).Int()
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (s *RedisStore) Lock(ctx context.Context, key string, d time.Duration) error {
+	return s.client.Set(ctx, keyPrefix+"lock:"+key, 1, d).Err()
+}
+
+func (s *RedisStore) Locked(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := s.client.PTTL(ctx, keyPrefix+"lock:"+key).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	// a missing key has a negative TTL
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+func (s *RedisStore) Reset(ctx context.Context, key string) error {
+	return s.client.Del(ctx, keyPrefix+"failures:"+key, keyPrefix+"lock:"+key).Err()
+}
+
+// Close closes the connections to Redis
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+var (
+	ErrCinemaNotFound  = errors.New("cinema not found")
+	ErrCinemaNameTaken = errors.New("a cinema with this name already exists")
+	ErrCinemaHasHalls  = errors.New("cinema still has halls")
+)
+
+// AddCinema adds a new cinema
+func (r *MongoStore) AddCinema(ctx context.Context, cinema *models.Cinema) (*models.Cinema, error) {
+	cinema.ID = primitive.NewObjectID()
+	cinema.CreatedAt = time.Now()
+	_, err := r.db(ctx).Collection("cinemas").InsertOne(ctx, cinema)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrCinemaNameTaken
+		}
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not add new cinema")
+		return nil, err
+	}
+	return cinema, nil
+}
+
+// ListCinemas returns all cinemas sorted by name
+func (r *MongoStore) ListCinemas(ctx context.Context) ([]models.Cinema, error) {
+	cinemas := make([]models.Cinema, 0)
+	cur, err := r.db(ctx).Collection("cinemas").Find(ctx, notDeleted(bson.M{}), options.Find().SetSort(bson.M{"name": 1}))
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not get all cinemas")
+		return nil, err
+	}
+
+	if err = cur.All(ctx, &cinemas); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could marshall the cinemas results")
+		return nil, err
+	}
+
+	return cinemas, nil
+}
+
+// GetCinema returns a cinema by ID
+func (r *MongoStore) GetCinema(ctx context.Context, id string) (*models.Cinema, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrCinemaNotFound
+	}
+
+	var cinema models.Cinema
+	err = r.db(ctx).Collection("cinemas").FindOne(ctx, notDeleted(bson.M{"_id": objID})).Decode(&cinema)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCinemaNotFound
+		}
+		return nil, err
+	}
+
+	return &cinema, nil
+}
+
+// UpdateCinema updates the name, address, time zone and pricing of a cinema
+func (r *MongoStore) UpdateCinema(ctx context.Context, id string, cinema models.Cinema) (*models.Cinema, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrCinemaNotFound
+	}
+
+	var updated models.Cinema
+	err = r.db(ctx).Collection("cinemas").FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID}), bson.M{
+		"$set": bson.M{
+			"name":     cinema.Name,
+			"city":     cinema.City,
+			"address":  cinema.Address,
+			"timeZone": cinema.TimeZone,
+			"pricing":  cinema.Pricing,
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&updated)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrCinemaNotFound
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrCinemaNameTaken
+		}
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not update cinema with id [%s]", id)
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// DeleteCinema soft deletes a cinema that has no halls, deleted ones included,
+// as they could still be restored. In the same transaction the admins of the
+// cinema lose their role for it.
+func (r *MongoStore) DeleteCinema(ctx context.Context, id string, deletedBy string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrCinemaNotFound
+	}
+
+	wc := writeconcern.Majority()
+	txnOptions := options.Transaction().SetWriteConcern(wc)
+
+	session, err := r.db(ctx).Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		halls, err := r.db(sessionCtx).Collection("halls").CountDocuments(sessionCtx, bson.M{"cinemaId": objID})
+		if err != nil {
+			zerolog.Ctx(sessionCtx).Error().Err(err).Msgf("could not count the halls of cinema [%s]", id)
+			return nil, err
+		}
+		if halls > 0 {
+			return nil, ErrCinemaHasHalls
+		}
+
+		if err := r.softDelete(sessionCtx, "cinemas", id, deletedBy, ErrCinemaNotFound); err != nil {
+			return nil, err
+		}
+
+		role := util.CinemaRole(util.CinemaAdminRole, id)
+		_, err = r.db(sessionCtx).Collection("users").UpdateMany(sessionCtx, bson.M{"roles": role}, bson.M{
+			"$pull": bson.M{"roles": role},
+			"$set":  bson.M{"update_date": time.Now()},
+		})
+		if err != nil {
+			zerolog.Ctx(sessionCtx).Error().Err(err).Msgf("could not remove the admins of cinema [%s]", id)
+			return nil, err
+		}
+		return nil, nil
+	}, txnOptions)
+
+	return err
+}
+
+// SetCinemaAdmin grants or revokes the admin role of a user for one cinema
+func (r *MongoStore) SetCinemaAdmin(ctx context.Context, cinemaId string, username string, admin bool) error {
+	if _, err := r.GetCinema(ctx, cinemaId); err != nil {
+		return err
+	}
+
+	role := util.CinemaRole(util.CinemaAdminRole, cinemaId)
+	operator := "$pull"
+	if admin {
+		operator = "$addToSet"
+	}
+
+	res, err := r.db(ctx).Collection("users").UpdateOne(ctx, bson.M{"username": username}, bson.M{
+		operator: bson.M{"roles": role},
+		"$set":   bson.M{"update_date": time.Now()},
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not change the roles of user [%s]", username)
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/util"
+)
+
+func createRandomCinema(t *testing.T) *models.Cinema {
+	arg := models.Cinema{
+		Name:     util.RandomString(10),
+		City:     util.RandomString(6),
+		TimeZone: "Europe/Belgrade",
+		Pricing: models.Pricing{
+			Currency: "RSD",
+			Prices:   map[string]int64{models.SeatStandard: 50000, models.SeatVIP: 80000},
+		},
+	}
+
+	cinema, err := testStore.AddCinema(context.Background(), &arg)
+	require.NoError(t, err)
+	require.NotZero(t, cinema.ID)
+	require.NotZero(t, cinema.CreatedAt)
+
+	return cinema
+}
+
+func TestAddAndGetCinema(t *testing.T) {
+	cinema1 := createRandomCinema(t)
+
+	cinema2, err := testStore.GetCinema(context.Background(), cinema1.ID.Hex())
+	require.NoError(t, err)
+	require.Equal(t, cinema1.Name, cinema2.Name)
+	require.Equal(t, cinema1.TimeZone, cinema2.TimeZone)
+	require.Equal(t, cinema1.Pricing, cinema2.Pricing)
+
+	_, err = testStore.AddCinema(context.Background(), &models.Cinema{Name: cinema1.Name, TimeZone: "UTC"})
+	require.ErrorIs(t, err, ErrCinemaNameTaken)
+
+	_, err = testStore.GetCinema(context.Background(), "invalid")
+	require.ErrorIs(t, err, ErrCinemaNotFound)
+}
+
+func TestUpdateCinema(t *testing.T) {
+	cinema1 := createRandomCinema(t)
+
+	arg := *cinema1
+	arg.TimeZone = "America/New_York"
+	arg.Pricing = models.Pricing{Currency: "USD", Prices: map[string]int64{models.SeatStandard: 1200}}
+
+	cinema2, err := testStore.UpdateCinema(context.Background(), cinema1.ID.Hex(), arg)
+	require.NoError(t, err)
+	require.Equal(t, cinema1.ID, cinema2.ID)
+	require.Equal(t, arg.TimeZone, cinema2.TimeZone)
+	require.Equal(t, arg.Pricing, cinema2.Pricing)
+}
+
+func TestDeleteCinemaWithHalls(t *testing.T) {
+	hall := createRandomHall(t)
+
+	err := testStore.DeleteCinema(context.Background(), hall.CinemaID.Hex(), util.RandomOwner())
+	require.ErrorIs(t, err, ErrCinemaHasHalls)
+
+	cinema := createRandomCinema(t)
+	err = testStore.DeleteCinema(context.Background(), cinema.ID.Hex(), util.RandomOwner())
+	require.NoError(t, err)
+
+	_, err = testStore.GetCinema(context.Background(), cinema.ID.Hex())
+	require.ErrorIs(t, err, ErrCinemaNotFound)
+}
+
+func TestDeleteCinemaRemovesAdmins(t *testing.T) {
+	require.NoError(t, testStore.EnsureIndexes(context.Background()))
+	cinema := createRandomCinema(t)
+	user := createRandomUser(t)
+	role := util.CinemaRole(util.CinemaAdminRole, cinema.ID.Hex())
+	require.NoError(t, testStore.SetCinemaAdmin(context.Background(), cinema.ID.Hex(), user.Username, true))
+
+	require.NoError(t, testStore.DeleteCinema(context.Background(), cinema.ID.Hex(), util.RandomOwner()))
+
+	updated, err := testStore.GetUserByUsername(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.NotContains(t, updated.Roles, role)
+
+	cinemas, err := testStore.ListCinemas(context.Background())
+	require.NoError(t, err)
+	for _, c := range cinemas {
+		require.NotEqual(t, cinema.ID, c.ID)
+	}
+
+	// the name of a deleted cinema can be used again
+	_, err = testStore.AddCinema(context.Background(), &models.Cinema{Name: cinema.Name, TimeZone: cinema.TimeZone})
+	require.NoError(t, err)
+}
+
+func TestSetCinemaAdmin(t *testing.T) {
+	cinema := createRandomCinema(t)
+	user := createRandomUser(t)
+	role := util.CinemaRole(util.CinemaAdminRole, cinema.ID.Hex())
+
+	err := testStore.SetCinemaAdmin(context.Background(), cinema.ID.Hex(), user.Username, true)
+	require.NoError(t, err)
+
+	updated, err := testStore.GetUserByUsername(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Contains(t, updated.Roles, role)
+
+	err = testStore.SetCinemaAdmin(context.Background(), cinema.ID.Hex(), user.Username, false)
+	require.NoError(t, err)
+
+	updated, err = testStore.GetUserByUsername(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.NotContains(t, updated.Roles, role)
+
+	err = testStore.SetCinemaAdmin(context.Background(), cinema.ID.Hex(), util.RandomOwner(), true)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
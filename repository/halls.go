@@ -4,26 +4,39 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/tijanadmi/movieginmongoapi/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var (
-	ErrHallNotFound = errors.New("hall not found")
+	ErrHallNotFound  = errors.New("hall not found")
+	ErrHallNameTaken = errors.New("a hall with this name already exists")
+	// ErrHallNameAmbiguous is returned when halls of several cinemas have the
+	// name a hall is looked up by
+	ErrHallNameAmbiguous = errors.New("halls of several cinemas have this name, give hallId or cinemaId")
 )
 
-// AddHall adds a new hall to the MongoDB collection
+// AddHall adds a new hall of an existing cinema to the MongoDB collection
 func (r *MongoStore) InsertHall(ctx context.Context, hall *models.Hall) (*models.Hall, error) {
+	if _, err := r.GetCinema(ctx, hall.CinemaID.Hex()); err != nil {
+		return nil, err
+	}
+
 	hall.ID = primitive.NewObjectID()
 	hall.CreatedAt = time.Now()
-	result, err := r.db.Collection("halls").InsertOne(ctx, hall)
+	hall.Seats = hall.Layout()
+	result, err := r.db(ctx).Collection("halls").InsertOne(ctx, hall)
 	if err != nil {
-		log.Print(fmt.Errorf("could not add new hall: %w", err))
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrHallNameTaken
+		}
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not add new hall")
 		return nil, err
 	}
 	hall.ID = result.InsertedID.(primitive.ObjectID)
@@ -33,14 +46,14 @@ func (r *MongoStore) InsertHall(ctx context.Context, hall *models.Hall) (*models
 // ListHalls returns all halls from the MongoDB collection
 func (r *MongoStore) ListHalls(ctx context.Context) ([]models.Hall, error) {
 	halls := make([]models.Hall, 0)
-	cur, err := r.db.Collection("halls").Find(ctx, bson.M{})
+	cur, err := r.db(ctx).Collection("halls").Find(ctx, notDeleted(bson.M{}))
 	if err != nil {
-		log.Print(fmt.Errorf("could not get all halls: %w", err))
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not get all halls")
 		return nil, err
 	}
 
 	if err = cur.All(ctx, &halls); err != nil {
-		log.Print(fmt.Errorf("could marshall the halls results: %w", err))
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could marshall the halls results")
 		return nil, err
 	}
 
@@ -52,20 +65,20 @@ func (r *MongoStore) GetHall(ctx context.Context, name string) ([]models.Hall, e
 	halls := make([]models.Hall, 0)
 
 	// Provera inicijalizacije kolekcije
-	if r.db.Collection("halls") == nil {
-		log.Print(fmt.Errorf("collection is not initialized:"))
+	if r.db(ctx).Collection("halls") == nil {
+		zerolog.Ctx(ctx).Error().Msg("collection is not initialized")
 		return nil, fmt.Errorf("collection is not initialized")
 	}
 
-	cur, err := r.db.Collection("halls").Find(ctx, bson.M{"name": name})
+	cur, err := r.db(ctx).Collection("halls").Find(ctx, notDeleted(bson.M{"name": name}))
 
 	if err != nil {
-		log.Print(fmt.Errorf("could not get all halls: %w", err))
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not get all halls")
 		return nil, err
 	}
 
 	if err = cur.All(ctx, &halls); err != nil {
-		log.Print(fmt.Errorf("could marshall the halls results: %w", err))
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could marshall the halls results")
 		return nil, err
 	}
 
@@ -80,7 +93,7 @@ func (r *MongoStore) GetHallById(ctx context.Context, id string) (*models.Hall,
 	}
 
 	var hall models.Hall
-	result := r.db.Collection("halls").FindOne(ctx, bson.M{"_id": objID})
+	result := r.db(ctx).Collection("halls").FindOne(ctx, notDeleted(bson.M{"_id": objID}))
 	err = result.Decode(&hall)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -96,42 +109,113 @@ func (r *MongoStore) GetHallById(ctx context.Context, id string) (*models.Hall,
 // UpdateHall updates a hall by ID in the MongoDB collection
 func (r *MongoStore) UpdateHall(ctx context.Context, id string, hall models.Hall) (models.Hall, error) {
 	objID, _ := primitive.ObjectIDFromHex(id)
-	res, err := r.db.Collection("halls").UpdateOne(ctx, bson.M{"_id": objID}, bson.D{
+	if _, err := r.GetCinema(ctx, hall.CinemaID.Hex()); err != nil {
+		return models.Hall{}, err
+	}
+
+	hall.Seats = hall.Layout()
+	res, err := r.db(ctx).Collection("halls").UpdateOne(ctx, notDeleted(bson.M{"_id": objID}), bson.D{
 		{"$set", bson.D{
+			{"cinemaId", hall.CinemaID},
 			{"name", hall.Name},
 			{"rows", hall.Rows},
 			{"cols", hall.Cols},
+			{"seats", hall.Seats},
 		}},
 	})
 	if err != nil {
-		log.Print(fmt.Errorf("could not update hall with id [%s]: %w", id, err))
+		if mongo.IsDuplicateKeyError(err) {
+			return models.Hall{}, ErrHallNameTaken
+		}
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not update hall with id [%s]", id)
 		return models.Hall{}, err
 	}
-	log.Print(fmt.Errorf("Rezultat updatea je %d", res.MatchedCount))
 	if res.MatchedCount == 0 {
 		return models.Hall{}, ErrHallNotFound
 	}
 	hall.ID = objID
 
+	// repertoires and reservations keep the hall name for display and follow
+	// the hall when it is moved to another cinema
+	for _, collection := range []string{"repertoires", "reservations"} {
+		_, err = r.db(ctx).Collection(collection).UpdateMany(ctx,
+			bson.M{"hallId": objID, "$or": bson.A{
+				bson.M{"hall": bson.M{"$ne": hall.Name}},
+				bson.M{"cinemaId": bson.M{"$ne": hall.CinemaID}},
+			}},
+			bson.M{"$set": bson.M{"hall": hall.Name, "cinemaId": hall.CinemaID}},
+		)
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msgf("could not rename hall [%s] in %s", id, collection)
+			return models.Hall{}, err
+		}
+	}
+
 	return hall, nil
 }
 
-// DeleteHall deletes a hall by ID from the MongoDB collection
-func (r *MongoStore) DeleteHall(ctx context.Context, id string) error {
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return err
+// getHallByName returns the hall with the given name. Hall names are only
+// unique within a cinema, so without a cinema the name must not be used by
+// halls of several cinemas.
+func (r *MongoStore) getHallByName(ctx context.Context, cinemaID primitive.ObjectID, name string) (*models.Hall, error) {
+	filter := bson.M{"name": name}
+	if !cinemaID.IsZero() {
+		filter["cinemaId"] = cinemaID
 	}
 
-	res, err := r.db.Collection("halls").DeleteOne(ctx, bson.M{"_id": objID})
+	halls := make([]models.Hall, 0)
+	cur, err := r.db(ctx).Collection("halls").Find(ctx, notDeleted(filter), options.Find().SetLimit(2))
 	if err != nil {
-		log.Print(fmt.Errorf("error deleting the hall with id [%s]: %w", id, err))
-		return err
+		return nil, err
+	}
+	if err = cur.All(ctx, &halls); err != nil {
+		return nil, err
 	}
 
-	if res.DeletedCount == 0 {
-		return ErrHallNotFound
+	switch len(halls) {
+	case 0:
+		return nil, ErrHallNotFound
+	case 1:
+		return &halls[0], nil
+	default:
+		return nil, ErrHallNameAmbiguous
+	}
+}
+
+// resolveHall sets both the hall reference and the hall name of a repertoire
+// from whichever of the two is given; HallID wins when both are. A name is
+// looked up in the cinema of the repertoire when it has one. The cinema of
+// the repertoire is the cinema of the hall.
+func (r *MongoStore) resolveHall(ctx context.Context, repertoire *models.Repertoire) (*models.Hall, error) {
+	var hall *models.Hall
+	var err error
+	switch {
+	case !repertoire.HallID.IsZero():
+		hall, err = r.GetHallById(ctx, repertoire.HallID.Hex())
+	case repertoire.Hall != "":
+		hall, err = r.getHallByName(ctx, repertoire.CinemaID, repertoire.Hall)
+	default:
+		return nil, ErrHallNotFound
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	repertoire.HallID = hall.ID
+	repertoire.Hall = hall.Name
+	repertoire.CinemaID = hall.CinemaID
+	return hall, nil
+}
 
-	return nil
+// DeleteHall soft deletes a hall by ID. The repertoires scheduled in the hall
+// and their upcoming reservations are dependents, see deleteWithDependents.
+func (r *MongoStore) DeleteHall(ctx context.Context, id string, deletedBy string, cascade bool) (Dependents, error) {
+	return r.deleteWithDependents(ctx, "halls", id, deletedBy, cascade, ErrHallNotFound,
+		func(ctx context.Context) ([]models.Repertoire, []primitive.ObjectID, error) {
+			hall, err := r.GetHallById(ctx, id)
+			if err != nil {
+				return nil, nil, err
+			}
+			return r.scheduledRepertoires(ctx, bson.M{"hallId": hall.ID})
+		})
 }
@@ -12,9 +12,11 @@ import (
 )
 
 func createRandomHall(t *testing.T) *models.Hall {
+	cinema := createRandomCinema(t)
 
 	arg := models.Hall{
-		Name: util.RandomHall(),
+		CinemaID: cinema.ID,
+		Name:     util.RandomHall(),
 		Rows: []string{
 			"A",
 			"B",
@@ -81,7 +83,8 @@ func TestUpdateHall(t *testing.T) {
 	hall1 := createRandomHall(t)
 
 	arg := models.Hall{
-		Name: hall1.Name,
+		CinemaID: hall1.CinemaID,
+		Name:     hall1.Name,
 		Rows: []string{
 			"A",
 			"B",
@@ -105,7 +108,7 @@ func TestUpdateHall(t *testing.T) {
 
 func TestDeleteHall(t *testing.T) {
 	hall1 := createRandomHall(t)
-	err := testStore.DeleteHall(context.Background(), hall1.ID.Hex())
+	_, err := testStore.DeleteHall(context.Background(), hall1.ID.Hex(), util.RandomOwner(), false)
 	require.NoError(t, err)
 
 	hall2, err := testStore.GetHallById(context.Background(), hall1.ID.Hex())
@@ -114,3 +117,53 @@ func TestDeleteHall(t *testing.T) {
 	require.EqualError(t, err, ErrHallNotFound.Error())
 	require.Empty(t, hall2)
 }
+
+func TestRenameHallKeepsRepertoires(t *testing.T) {
+	repertoire := createRandomRepertoire(t)
+
+	hall, err := testStore.GetHallById(context.Background(), repertoire.HallID.Hex())
+	require.NoError(t, err)
+
+	hall.Name = util.RandomHall()
+	_, err = testStore.UpdateHall(context.Background(), hall.ID.Hex(), *hall)
+	require.NoError(t, err)
+
+	renamed, err := testStore.GetRepertoire(context.Background(), repertoire.ID.Hex())
+	require.NoError(t, err)
+	require.Equal(t, hall.ID, renamed.HallID)
+	require.Equal(t, hall.Name, renamed.Hall)
+}
+
+func TestInsertHallNameTaken(t *testing.T) {
+	require.NoError(t, testStore.EnsureIndexes(context.Background()))
+	hall1 := createRandomHall(t)
+
+	_, err := testStore.InsertHall(context.Background(), &models.Hall{CinemaID: hall1.CinemaID, Name: hall1.Name, Rows: hall1.Rows, Cols: hall1.Cols})
+	require.ErrorIs(t, err, ErrHallNameTaken)
+}
+
+func TestHallNamesPerCinema(t *testing.T) {
+	require.NoError(t, testStore.EnsureIndexes(context.Background()))
+	hall1 := createRandomHall(t)
+	cinema := createRandomCinema(t)
+
+	// another cinema may have a hall with the same name
+	hall2, err := testStore.InsertHall(context.Background(), &models.Hall{CinemaID: cinema.ID, Name: hall1.Name, Rows: hall1.Rows, Cols: hall1.Cols})
+	require.NoError(t, err)
+
+	movie := createRandomMovie(t)
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	_, err = testStore.AddRepertoire(context.Background(), &models.Repertoire{MovieID: movie.ID, Date: date, Time: "10:00", Hall: hall1.Name})
+	require.ErrorIs(t, err, ErrHallNameAmbiguous)
+
+	repertoire, err := testStore.AddRepertoire(context.Background(), &models.Repertoire{MovieID: movie.ID, Date: date, Time: "10:00", CinemaID: cinema.ID, Hall: hall1.Name})
+	require.NoError(t, err)
+	require.Equal(t, hall2.ID, repertoire.HallID)
+
+	// a deleted hall frees its name until it is restored
+	_, err = testStore.DeleteHall(context.Background(), hall1.ID.Hex(), util.RandomOwner(), true)
+	require.NoError(t, err)
+	_, err = testStore.InsertHall(context.Background(), &models.Hall{CinemaID: hall1.CinemaID, Name: hall1.Name, Rows: hall1.Rows, Cols: hall1.Cols})
+	require.NoError(t, err)
+	require.ErrorIs(t, testStore.RestoreHall(context.Background(), hall1.ID.Hex()), ErrHallNameTaken)
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// movieTextIndexName is the name of the full-text index over the movies collection
+const movieTextIndexName = "movies_text"
+
+// EnsureIndexes creates the indexes the store relies on. It is safe to call on every start.
+func (r *MongoStore) EnsureIndexes(ctx context.Context) error {
+	// Title matches rank above cast and crew, and those above the plot.
+	// Language "none" disables stemming so Serbian titles are matched as written,
+	// while the version 3 text index keeps matching diacritic-insensitive.
+	movieText := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "actors", Value: "text"},
+			{Key: "directors", Value: "text"},
+			{Key: "plot", Value: "text"},
+		},
+		Options: options.Index().
+			SetName(movieTextIndexName).
+			SetDefaultLanguage("none").
+			SetTextVersion(3).
+			SetWeights(bson.M{
+				"title":     10,
+				"actors":    5,
+				"directors": 5,
+				"plot":      1,
+			}),
+	}
+	if _, err := r.db(ctx).Collection("movies").Indexes().CreateOne(ctx, movieText); err != nil {
+		return fmt.Errorf("could not create movies text index: %w", err)
+	}
+
+	reviewPerUser := mongo.IndexModel{
+		Keys:    bson.D{{Key: "movieId", Value: 1}, {Key: "userId", Value: 1}},
+		Options: options.Index().SetName("reviews_movie_user").SetUnique(true),
+	}
+	if _, err := r.db(ctx).Collection("reviews").Indexes().CreateOne(ctx, reviewPerUser); err != nil {
+		return fmt.Errorf("could not create reviews index: %w", err)
+	}
+
+	// hall names are unique within a cinema; deleted halls free their name
+	hallName := mongo.IndexModel{
+		Keys: bson.D{{Key: "cinemaId", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().
+			SetName("halls_cinema_name").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"deleted_at": nil}),
+	}
+	if _, err := r.db(ctx).Collection("halls").Indexes().CreateOne(ctx, hallName); err != nil {
+		return fmt.Errorf("could not create halls name index: %w", err)
+	}
+
+	// cinema names are unique among the cinemas that are not deleted; a nil
+	// deleted_at matches documents without the field too
+	cinemaName := mongo.IndexModel{
+		Keys: bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().
+			SetName("cinemas_name").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"deleted_at": nil}),
+	}
+	if _, err := r.db(ctx).Collection("cinemas").Indexes().CreateOne(ctx, cinemaName); err != nil {
+		return fmt.Errorf("could not create cinemas name index: %w", err)
+	}
+
+	cinemaHalls := mongo.IndexModel{
+		Keys:    bson.D{{Key: "cinemaId", Value: 1}},
+		Options: options.Index().SetName("halls_cinema"),
+	}
+	if _, err := r.db(ctx).Collection("halls").Indexes().CreateOne(ctx, cinemaHalls); err != nil {
+		return fmt.Errorf("could not create halls cinema index: %w", err)
+	}
+
+	cinemaRepertoires := mongo.IndexModel{
+		Keys:    bson.D{{Key: "cinemaId", Value: 1}, {Key: "date", Value: 1}},
+		Options: options.Index().SetName("repertoires_cinema_date"),
+	}
+	if _, err := r.db(ctx).Collection("repertoires").Indexes().CreateOne(ctx, cinemaRepertoires); err != nil {
+		return fmt.Errorf("could not create repertoires cinema index: %w", err)
+	}
+
+	hallRepertoires := mongo.IndexModel{
+		Keys:    bson.D{{Key: "hallId", Value: 1}, {Key: "date", Value: 1}},
+		Options: options.Index().SetName("repertoires_hall_date"),
+	}
+	if _, err := r.db(ctx).Collection("repertoires").Indexes().CreateOne(ctx, hallRepertoires); err != nil {
+		return fmt.Errorf("could not create repertoires hall index: %w", err)
+	}
+
+	notificationsPerUser := mongo.IndexModel{
+		Keys:    bson.D{{Key: "username", Value: 1}, {Key: "creation_date", Value: -1}},
+		Options: options.Index().SetName("notifications_username"),
+	}
+	if _, err := r.db(ctx).Collection("notifications").Indexes().CreateOne(ctx, notificationsPerUser); err != nil {
+		return fmt.Errorf("could not create notifications index: %w", err)
+	}
+
+	// the purge job looks up soft-deleted documents by deleted_at
+	for _, collection := range []string{"movies", "halls", "repertoires"} {
+		deletedAt := mongo.IndexModel{
+			Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetName(collection + "_deleted_at"),
+		}
+		if _, err := r.db(ctx).Collection(collection).Indexes().CreateOne(ctx, deletedAt); err != nil {
+			return fmt.Errorf("could not create %s deleted_at index: %w", collection, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/util"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestEnsureIndexesOnNewDatabase(t *testing.T) {
+	// a new tenant has no indexes yet, so MongoDB checks every definition
+	ctx := WithTenant(context.Background(), "indexes-"+util.RandomString(6))
+	store := testStore.(*MongoStore)
+	defer store.db(ctx).Drop(context.Background())
+
+	require.NoError(t, store.EnsureIndexes(ctx))
+	// and accepts them again on the next start
+	require.NoError(t, store.EnsureIndexes(ctx))
+
+	for collection, name := range map[string]string{
+		"halls":   "halls_cinema_name",
+		"cinemas": "cinemas_name",
+	} {
+		cur, err := store.db(ctx).Collection(collection).Indexes().List(ctx)
+		require.NoError(t, err)
+		var indexes []bson.M
+		require.NoError(t, cur.All(ctx, &indexes))
+
+		var partialFilter any
+		for _, index := range indexes {
+			if index["name"] == name {
+				partialFilter = index["partialFilterExpression"]
+			}
+		}
+		require.NotNil(t, partialFilter, "index %s of %s", name, collection)
+	}
+
+	cinema, err := store.AddCinema(ctx, &models.Cinema{Name: util.RandomString(10), TimeZone: "UTC"})
+	require.NoError(t, err)
+	_, err = store.AddCinema(ctx, &models.Cinema{Name: cinema.Name, TimeZone: "UTC"})
+	require.ErrorIs(t, err, ErrCinemaNameTaken)
+
+	hall, err := store.InsertHall(ctx, &models.Hall{CinemaID: cinema.ID, Name: util.RandomHall(), Rows: []string{"A"}, Cols: []int{1}})
+	require.NoError(t, err)
+	_, err = store.InsertHall(ctx, &models.Hall{CinemaID: cinema.ID, Name: hall.Name, Rows: []string{"A"}, Cols: []int{1}})
+	require.ErrorIs(t, err, ErrHallNameTaken)
+
+	// deleted halls are left out of the index and free their name
+	_, err = store.DeleteHall(ctx, hall.ID.Hex(), util.RandomOwner(), false)
+	require.NoError(t, err)
+	_, err = store.InsertHall(ctx, &models.Hall{CinemaID: cinema.ID, Name: hall.Name, Rows: []string{"A"}, Cols: []int{1}})
+	require.NoError(t, err)
+}
@@ -0,0 +1,408 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tijanadmi/movieginmongoapi/metrics"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/tijanadmi/movieginmongoapi/repository")
+
+// instrumentedStore records the duration and the outcome of every operation
+// of the store it wraps, and traces it in a span that is the parent of the
+// spans of the database commands of the operation
+type instrumentedStore struct {
+	store Store
+}
+
+// NewInstrumentedStore wraps store so that its operations show up in the
+// mongo_operation_duration_seconds metric and in traces
+func NewInstrumentedStore(store Store) Store {
+	return &instrumentedStore{store: store}
+}
+
+// operation is a store operation in progress
+type operation struct {
+	name  string
+	start time.Time
+	span  trace.Span
+}
+
+func startOperation(ctx context.Context, name string) (context.Context, *operation) {
+	ctx, span := tracer.Start(ctx, "MongoStore."+name)
+	return ctx, &operation{name: name, start: time.Now(), span: span}
+}
+
+func (op *operation) end(err *error) {
+	metrics.ObserveOperation(op.name, op.start, *err)
+	if *err != nil {
+		op.span.RecordError(*err)
+		op.span.SetStatus(codes.Error, (*err).Error())
+	}
+	op.span.End()
+}
+
+func (s *instrumentedStore) Ping(ctx context.Context) (err error) {
+	ctx, op := startOperation(ctx, "Ping")
+	defer op.end(&err)
+	return s.store.Ping(ctx)
+}
+
+func (s *instrumentedStore) EnsureIndexes(ctx context.Context) (err error) {
+	ctx, op := startOperation(ctx, "EnsureIndexes")
+	defer op.end(&err)
+	return s.store.EnsureIndexes(ctx)
+}
+
+func (s *instrumentedStore) RunMigrations(ctx context.Context) (err error) {
+	ctx, op := startOperation(ctx, "RunMigrations")
+	defer op.end(&err)
+	return s.store.RunMigrations(ctx)
+}
+
+func (s *instrumentedStore) PendingMigrations(ctx context.Context) (_ []string, err error) {
+	ctx, op := startOperation(ctx, "PendingMigrations")
+	defer op.end(&err)
+	return s.store.PendingMigrations(ctx)
+}
+
+func (s *instrumentedStore) GetUserByUsername(ctx context.Context, username string) (_ *models.User, err error) {
+	ctx, op := startOperation(ctx, "GetUserByUsername")
+	defer op.end(&err)
+	return s.store.GetUserByUsername(ctx, username)
+}
+
+func (s *instrumentedStore) InsertUser(ctx context.Context, user *models.User) (_ *models.User, err error) {
+	ctx, op := startOperation(ctx, "InsertUser")
+	defer op.end(&err)
+	return s.store.InsertUser(ctx, user)
+}
+
+func (s *instrumentedStore) AddCinema(ctx context.Context, cinema *models.Cinema) (_ *models.Cinema, err error) {
+	ctx, op := startOperation(ctx, "AddCinema")
+	defer op.end(&err)
+	return s.store.AddCinema(ctx, cinema)
+}
+
+func (s *instrumentedStore) ListCinemas(ctx context.Context) (_ []models.Cinema, err error) {
+	ctx, op := startOperation(ctx, "ListCinemas")
+	defer op.end(&err)
+	return s.store.ListCinemas(ctx)
+}
+
+func (s *instrumentedStore) GetCinema(ctx context.Context, id string) (_ *models.Cinema, err error) {
+	ctx, op := startOperation(ctx, "GetCinema")
+	defer op.end(&err)
+	return s.store.GetCinema(ctx, id)
+}
+
+func (s *instrumentedStore) UpdateCinema(ctx context.Context, id string, cinema models.Cinema) (_ *models.Cinema, err error) {
+	ctx, op := startOperation(ctx, "UpdateCinema")
+	defer op.end(&err)
+	return s.store.UpdateCinema(ctx, id, cinema)
+}
+
+func (s *instrumentedStore) DeleteCinema(ctx context.Context, id string, deletedBy string) (err error) {
+	ctx, op := startOperation(ctx, "DeleteCinema")
+	defer op.end(&err)
+	return s.store.DeleteCinema(ctx, id, deletedBy)
+}
+
+func (s *instrumentedStore) SetCinemaAdmin(ctx context.Context, cinemaId string, username string, admin bool) (err error) {
+	ctx, op := startOperation(ctx, "SetCinemaAdmin")
+	defer op.end(&err)
+	return s.store.SetCinemaAdmin(ctx, cinemaId, username, admin)
+}
+
+func (s *instrumentedStore) InsertHall(ctx context.Context, hall *models.Hall) (_ *models.Hall, err error) {
+	ctx, op := startOperation(ctx, "InsertHall")
+	defer op.end(&err)
+	return s.store.InsertHall(ctx, hall)
+}
+
+func (s *instrumentedStore) ListHalls(ctx context.Context) (_ []models.Hall, err error) {
+	ctx, op := startOperation(ctx, "ListHalls")
+	defer op.end(&err)
+	return s.store.ListHalls(ctx)
+}
+
+func (s *instrumentedStore) GetHall(ctx context.Context, name string) (_ []models.Hall, err error) {
+	ctx, op := startOperation(ctx, "GetHall")
+	defer op.end(&err)
+	return s.store.GetHall(ctx, name)
+}
+
+func (s *instrumentedStore) GetHallById(ctx context.Context, id string) (_ *models.Hall, err error) {
+	ctx, op := startOperation(ctx, "GetHallById")
+	defer op.end(&err)
+	return s.store.GetHallById(ctx, id)
+}
+
+func (s *instrumentedStore) UpdateHall(ctx context.Context, id string, hall models.Hall) (_ models.Hall, err error) {
+	ctx, op := startOperation(ctx, "UpdateHall")
+	defer op.end(&err)
+	return s.store.UpdateHall(ctx, id, hall)
+}
+
+func (s *instrumentedStore) DeleteHall(ctx context.Context, id string, deletedBy string, cascade bool) (_ Dependents, err error) {
+	ctx, op := startOperation(ctx, "DeleteHall")
+	defer op.end(&err)
+	return s.store.DeleteHall(ctx, id, deletedBy, cascade)
+}
+
+func (s *instrumentedStore) RestoreHall(ctx context.Context, id string) (err error) {
+	ctx, op := startOperation(ctx, "RestoreHall")
+	defer op.end(&err)
+	return s.store.RestoreHall(ctx, id)
+}
+
+func (s *instrumentedStore) AddMovie(ctx context.Context, movie *models.Movie) (_ *models.Movie, err error) {
+	ctx, op := startOperation(ctx, "AddMovie")
+	defer op.end(&err)
+	return s.store.AddMovie(ctx, movie)
+}
+
+func (s *instrumentedStore) ListMovies(ctx context.Context) (_ []models.Movie, err error) {
+	ctx, op := startOperation(ctx, "ListMovies")
+	defer op.end(&err)
+	return s.store.ListMovies(ctx)
+}
+
+func (s *instrumentedStore) GetMovie(ctx context.Context, id string) (_ *models.Movie, err error) {
+	ctx, op := startOperation(ctx, "GetMovie")
+	defer op.end(&err)
+	return s.store.GetMovie(ctx, id)
+}
+
+func (s *instrumentedStore) UpdateMovie(ctx context.Context, id string, movie *models.Movie) (_ *models.Movie, err error) {
+	ctx, op := startOperation(ctx, "UpdateMovie")
+	defer op.end(&err)
+	return s.store.UpdateMovie(ctx, id, movie)
+}
+
+func (s *instrumentedStore) DeleteMovie(ctx context.Context, id string, deletedBy string, cascade bool) (_ Dependents, err error) {
+	ctx, op := startOperation(ctx, "DeleteMovie")
+	defer op.end(&err)
+	return s.store.DeleteMovie(ctx, id, deletedBy, cascade)
+}
+
+func (s *instrumentedStore) RestoreMovie(ctx context.Context, id string) (err error) {
+	ctx, op := startOperation(ctx, "RestoreMovie")
+	defer op.end(&err)
+	return s.store.RestoreMovie(ctx, id)
+}
+
+func (s *instrumentedStore) UpdateMoviePoster(ctx context.Context, id string, poster string, sizes map[string]string) (err error) {
+	ctx, op := startOperation(ctx, "UpdateMoviePoster")
+	defer op.end(&err)
+	return s.store.UpdateMoviePoster(ctx, id, poster, sizes)
+}
+
+func (s *instrumentedStore) SearchMovies(ctx context.Context, movieId string) (_ []models.Movie, err error) {
+	ctx, op := startOperation(ctx, "SearchMovies")
+	defer op.end(&err)
+	return s.store.SearchMovies(ctx, movieId)
+}
+
+func (s *instrumentedStore) SearchMoviesText(ctx context.Context, arg SearchMoviesParams) (_ []models.Movie, err error) {
+	ctx, op := startOperation(ctx, "SearchMoviesText")
+	defer op.end(&err)
+	return s.store.SearchMoviesText(ctx, arg)
+}
+
+func (s *instrumentedStore) ListNowShowing(ctx context.Context, from time.Time, to time.Time) (_ []models.MovieSchedule, err error) {
+	ctx, op := startOperation(ctx, "ListNowShowing")
+	defer op.end(&err)
+	return s.store.ListNowShowing(ctx, from, to)
+}
+
+func (s *instrumentedStore) ListComingSoon(ctx context.Context, after time.Time) (_ []models.MovieSchedule, err error) {
+	ctx, op := startOperation(ctx, "ListComingSoon")
+	defer op.end(&err)
+	return s.store.ListComingSoon(ctx, after)
+}
+
+func (s *instrumentedStore) ListMoviesByGenre(ctx context.Context, genre string) (_ []models.Movie, err error) {
+	ctx, op := startOperation(ctx, "ListMoviesByGenre")
+	defer op.end(&err)
+	return s.store.ListMoviesByGenre(ctx, genre)
+}
+
+func (s *instrumentedStore) ListMoviesByPerson(ctx context.Context, name string, role string) (_ []models.Movie, err error) {
+	ctx, op := startOperation(ctx, "ListMoviesByPerson")
+	defer op.end(&err)
+	return s.store.ListMoviesByPerson(ctx, name, role)
+}
+
+func (s *instrumentedStore) AddRepertoire(ctx context.Context, repertoire *models.Repertoire) (_ *models.Repertoire, err error) {
+	ctx, op := startOperation(ctx, "AddRepertoire")
+	defer op.end(&err)
+	return s.store.AddRepertoire(ctx, repertoire)
+}
+
+func (s *instrumentedStore) ListRepertoires(ctx context.Context) (_ []models.Repertoire, err error) {
+	ctx, op := startOperation(ctx, "ListRepertoires")
+	defer op.end(&err)
+	return s.store.ListRepertoires(ctx)
+}
+
+func (s *instrumentedStore) GetRepertoire(ctx context.Context, id string) (_ *models.Repertoire, err error) {
+	ctx, op := startOperation(ctx, "GetRepertoire")
+	defer op.end(&err)
+	return s.store.GetRepertoire(ctx, id)
+}
+
+func (s *instrumentedStore) GetRepertoireByMovieDateTimeHall(ctx context.Context, movieId string, dateValue time.Time, timeValue string, hallId string) (_ models.Repertoire, err error) {
+	ctx, op := startOperation(ctx, "GetRepertoireByMovieDateTimeHall")
+	defer op.end(&err)
+	return s.store.GetRepertoireByMovieDateTimeHall(ctx, movieId, dateValue, timeValue, hallId)
+}
+
+func (s *instrumentedStore) GetAllRepertoireForMovie(ctx context.Context, movieId string, startDate time.Time, endDate time.Time, cinemaId string) (_ []models.Repertoire, err error) {
+	ctx, op := startOperation(ctx, "GetAllRepertoireForMovie")
+	defer op.end(&err)
+	return s.store.GetAllRepertoireForMovie(ctx, movieId, startDate, endDate, cinemaId)
+}
+
+func (s *instrumentedStore) UpdateRepertoire(ctx context.Context, id string, repertoire models.Repertoire) (_ *models.Repertoire, err error) {
+	ctx, op := startOperation(ctx, "UpdateRepertoire")
+	defer op.end(&err)
+	return s.store.UpdateRepertoire(ctx, id, repertoire)
+}
+
+func (s *instrumentedStore) DeleteRepertoire(ctx context.Context, id string, deletedBy string, cascade bool) (_ Dependents, err error) {
+	ctx, op := startOperation(ctx, "DeleteRepertoire")
+	defer op.end(&err)
+	return s.store.DeleteRepertoire(ctx, id, deletedBy, cascade)
+}
+
+func (s *instrumentedStore) DeleteRepertoireForMovie(ctx context.Context, movieId string, deletedBy string, cascade bool) (_ Dependents, err error) {
+	ctx, op := startOperation(ctx, "DeleteRepertoireForMovie")
+	defer op.end(&err)
+	return s.store.DeleteRepertoireForMovie(ctx, movieId, deletedBy, cascade)
+}
+
+func (s *instrumentedStore) RestoreRepertoire(ctx context.Context, id string) (err error) {
+	ctx, op := startOperation(ctx, "RestoreRepertoire")
+	defer op.end(&err)
+	return s.store.RestoreRepertoire(ctx, id)
+}
+
+func (s *instrumentedStore) BlockSeats(ctx context.Context, repertoireId string, seats []string, reason string, blockedBy string) (_ *models.Repertoire, err error) {
+	ctx, op := startOperation(ctx, "BlockSeats")
+	defer op.end(&err)
+	return s.store.BlockSeats(ctx, repertoireId, seats, reason, blockedBy)
+}
+
+func (s *instrumentedStore) UnblockSeats(ctx context.Context, repertoireId string, seats []string) (_ *models.Repertoire, err error) {
+	ctx, op := startOperation(ctx, "UnblockSeats")
+	defer op.end(&err)
+	return s.store.UnblockSeats(ctx, repertoireId, seats)
+}
+
+func (s *instrumentedStore) FindBestSeats(ctx context.Context, repertoireId string, party int, username string, holdUntil time.Time) (_ models.SeatProposal, err error) {
+	ctx, op := startOperation(ctx, "FindBestSeats")
+	defer op.end(&err)
+	return s.store.FindBestSeats(ctx, repertoireId, party, username, holdUntil)
+}
+
+func (s *instrumentedStore) GetProgram(ctx context.Context, date time.Time, cinemaId string) (_ []models.ProgramHall, err error) {
+	ctx, op := startOperation(ctx, "GetProgram")
+	defer op.end(&err)
+	return s.store.GetProgram(ctx, date, cinemaId)
+}
+
+func (s *instrumentedStore) InsertReservation(ctx context.Context, reservation *models.Reservation) (_ *models.Reservation, err error) {
+	ctx, op := startOperation(ctx, "InsertReservation")
+	defer op.end(&err)
+	return s.store.InsertReservation(ctx, reservation)
+}
+
+func (s *instrumentedStore) GetReservationById(ctx context.Context, id string) (_ *models.Reservation, err error) {
+	ctx, op := startOperation(ctx, "GetReservationById")
+	defer op.end(&err)
+	return s.store.GetReservationById(ctx, id)
+}
+
+func (s *instrumentedStore) GetAllReservationsForUser(ctx context.Context, username string) (_ []models.Reservation, err error) {
+	ctx, op := startOperation(ctx, "GetAllReservationsForUser")
+	defer op.end(&err)
+	return s.store.GetAllReservationsForUser(ctx, username)
+}
+
+func (s *instrumentedStore) DeleteReservation(ctx context.Context, id string) (err error) {
+	ctx, op := startOperation(ctx, "DeleteReservation")
+	defer op.end(&err)
+	return s.store.DeleteReservation(ctx, id)
+}
+
+func (s *instrumentedStore) CheckInReservation(ctx context.Context, id string) (err error) {
+	ctx, op := startOperation(ctx, "CheckInReservation")
+	defer op.end(&err)
+	return s.store.CheckInReservation(ctx, id)
+}
+
+func (s *instrumentedStore) ListNotifications(ctx context.Context, username string) (_ []models.Notification, err error) {
+	ctx, op := startOperation(ctx, "ListNotifications")
+	defer op.end(&err)
+	return s.store.ListNotifications(ctx, username)
+}
+
+func (s *instrumentedStore) AddReservation(ctx context.Context, req AddReservationParams) (_ *models.Reservation, err error) {
+	ctx, op := startOperation(ctx, "AddReservation")
+	defer op.end(&err)
+	return s.store.AddReservation(ctx, req)
+}
+
+func (s *instrumentedStore) CancelReservation(ctx context.Context, resId string) (err error) {
+	ctx, op := startOperation(ctx, "CancelReservation")
+	defer op.end(&err)
+	return s.store.CancelReservation(ctx, resId)
+}
+
+func (s *instrumentedStore) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	ctx, op := startOperation(ctx, "WithTransaction")
+	defer op.end(&err)
+	return s.store.WithTransaction(ctx, fn)
+}
+
+func (s *instrumentedStore) AddReview(ctx context.Context, review *models.Review) (_ *models.Review, err error) {
+	ctx, op := startOperation(ctx, "AddReview")
+	defer op.end(&err)
+	return s.store.AddReview(ctx, review)
+}
+
+func (s *instrumentedStore) ListReviewsForMovie(ctx context.Context, movieId string) (_ []models.Review, err error) {
+	ctx, op := startOperation(ctx, "ListReviewsForMovie")
+	defer op.end(&err)
+	return s.store.ListReviewsForMovie(ctx, movieId)
+}
+
+func (s *instrumentedStore) ListReviews(ctx context.Context, status string) (_ []models.Review, err error) {
+	ctx, op := startOperation(ctx, "ListReviews")
+	defer op.end(&err)
+	return s.store.ListReviews(ctx, status)
+}
+
+func (s *instrumentedStore) SetReviewStatus(ctx context.Context, id string, status string, moderator string) (_ *models.Review, err error) {
+	ctx, op := startOperation(ctx, "SetReviewStatus")
+	defer op.end(&err)
+	return s.store.SetReviewStatus(ctx, id, status, moderator)
+}
+
+func (s *instrumentedStore) DeleteReview(ctx context.Context, id string) (err error) {
+	ctx, op := startOperation(ctx, "DeleteReview")
+	defer op.end(&err)
+	return s.store.DeleteReview(ctx, id)
+}
+
+func (s *instrumentedStore) PurgeDeleted(ctx context.Context, before time.Time) (_ PurgeResult, err error) {
+	ctx, op := startOperation(ctx, "PurgeDeleted")
+	defer op.end(&err)
+	return s.store.PurgeDeleted(ctx, before)
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tijanadmi/movieginmongoapi/metrics"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// ErrHasDependents is returned when a record cannot be deleted without cascade
+// because repertoires or reservations depend on it
+var ErrHasDependents = errors.New("record has dependent repertoires or reservations")
+
+// Dependents lists the repertoires and upcoming reservations that depend on a
+// movie, hall or repertoire
+type Dependents struct {
+	Repertoires  []models.Repertoire  `json:"repertoires,omitempty"`
+	Reservations []primitive.ObjectID `json:"reservations,omitempty"`
+}
+
+// Empty reports whether nothing depends on the record
+func (d Dependents) Empty() bool {
+	return len(d.Repertoires) == 0 && len(d.Reservations) == 0
+}
+
+// dependentsLoader returns the repertoires scheduled for the record being
+// deleted and the screenings whose reservations depend on it. It returns the
+// not found error of the record when there is nothing to delete.
+type dependentsLoader func(ctx context.Context) ([]models.Repertoire, []primitive.ObjectID, error)
+
+// deleteWithDependents soft deletes a document after checking its dependents in
+// a transaction. Without cascade the dependents are returned with
+// ErrHasDependents; with cascade the dependent repertoires are deleted too, the
+// upcoming reservations are cancelled and their owners notified.
+func (r *MongoStore) deleteWithDependents(ctx context.Context, collection string, id string, deletedBy string, cascade bool, notFound error, load dependentsLoader) (Dependents, error) {
+	return r.removeWithDependents(ctx, deletedBy, cascade, load, func(ctx context.Context) error {
+		return r.softDelete(ctx, collection, id, deletedBy, notFound)
+	})
+}
+
+// removeWithDependents is deleteWithDependents for any removal, e.g. of
+// several documents at once
+func (r *MongoStore) removeWithDependents(ctx context.Context, deletedBy string, cascade bool, load dependentsLoader, remove func(ctx context.Context) error) (Dependents, error) {
+	wc := writeconcern.Majority()
+	txnOptions := options.Transaction().SetWriteConcern(wc)
+
+	session, err := r.db(ctx).Client().StartSession()
+	if err != nil {
+		return Dependents{}, err
+	}
+	defer session.EndSession(ctx)
+
+	result, err := session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		repertoires, screenings, err := load(sessionCtx)
+		if err != nil {
+			return Dependents{}, err
+		}
+
+		reservations, err := r.upcomingReservations(sessionCtx, screenings)
+		if err != nil {
+			return Dependents{}, err
+		}
+
+		dependents := Dependents{Repertoires: repertoires}
+		for _, reservation := range reservations {
+			dependents.Reservations = append(dependents.Reservations, reservation.ID)
+		}
+
+		if !dependents.Empty() {
+			if !cascade {
+				return dependents, ErrHasDependents
+			}
+			if err := r.cancelDependents(sessionCtx, dependents, reservations, deletedBy); err != nil {
+				return dependents, err
+			}
+		}
+
+		return dependents, remove(sessionCtx)
+	}, txnOptions)
+
+	dependents, _ := result.(Dependents)
+	if err == nil {
+		metrics.ReservationsCancelled.Add(float64(len(dependents.Reservations)))
+	}
+	return dependents, err
+}
+
+// scheduledRepertoires returns the repertoires matching filter that are not
+// deleted and are screened from today on; past repertoires are kept as history
+func (r *MongoStore) scheduledRepertoires(ctx context.Context, filter bson.M) ([]models.Repertoire, []primitive.ObjectID, error) {
+	repertoires := make([]models.Repertoire, 0)
+	filter["date"] = bson.M{"$gte": time.Now().UTC().Truncate(24 * time.Hour)}
+	cur, err := r.db(ctx).Collection("repertoires").Find(ctx, notDeleted(filter))
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not get dependent repertoires")
+		return nil, nil, err
+	}
+
+	if err = cur.All(ctx, &repertoires); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could marshall the repertoires results")
+		return nil, nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(repertoires))
+	for _, repertoire := range repertoires {
+		ids = append(ids, repertoire.ID)
+	}
+
+	return repertoires, ids, nil
+}
+
+// upcomingReservations returns the reservations for the given screenings from
+// today on; reservations of past screenings are kept as history
+func (r *MongoStore) upcomingReservations(ctx context.Context, screenings []primitive.ObjectID) ([]models.Reservation, error) {
+	reservations := make([]models.Reservation, 0)
+	if len(screenings) == 0 {
+		return reservations, nil
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	cur, err := r.db(ctx).Collection("reservations").Find(ctx, bson.M{
+		"repertoiresId": bson.M{"$in": screenings},
+		"date":          bson.M{"$gte": today},
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not get dependent reservations")
+		return nil, err
+	}
+
+	if err = cur.All(ctx, &reservations); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could marshall the reservations results")
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// cancelDependents soft deletes the dependent repertoires, cancels the
+// reservations and notifies their owners
+func (r *MongoStore) cancelDependents(ctx context.Context, dependents Dependents, reservations []models.Reservation, deletedBy string) error {
+	if len(dependents.Repertoires) > 0 {
+		ids := make([]primitive.ObjectID, 0, len(dependents.Repertoires))
+		for _, repertoire := range dependents.Repertoires {
+			ids = append(ids, repertoire.ID)
+		}
+
+		_, err := r.db(ctx).Collection("repertoires").UpdateMany(ctx, notDeleted(bson.M{"_id": bson.M{"$in": ids}}), bson.M{
+			"$set": bson.M{"deleted_at": time.Now().UTC(), "deleted_by": deletedBy},
+		})
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("could not delete dependent repertoires")
+			return err
+		}
+	}
+
+	if len(dependents.Reservations) > 0 {
+		_, err := r.db(ctx).Collection("reservations").DeleteMany(ctx, bson.M{"_id": bson.M{"$in": dependents.Reservations}})
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("could not cancel dependent reservations")
+			return err
+		}
+	}
+
+	return r.notifyCancelled(ctx, reservations)
+}
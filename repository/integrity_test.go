@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDeleteMovieWithDependents(t *testing.T) {
+	reservation := createRandomReservation(t)
+	movieID := reservation.MovieID.Hex()
+	admin := util.RandomOwner()
+
+	dependents, err := testStore.DeleteMovie(context.Background(), movieID, admin, false)
+	require.ErrorIs(t, err, ErrHasDependents)
+	require.Len(t, dependents.Repertoires, 1)
+	require.Equal(t, reservation.RepertoiresID, dependents.Repertoires[0].ID)
+	require.Equal(t, reservation.ID, dependents.Reservations[0])
+
+	// nothing was deleted
+	_, err = testStore.GetMovie(context.Background(), movieID)
+	require.NoError(t, err)
+	_, err = testStore.GetReservationById(context.Background(), reservation.ID.Hex())
+	require.NoError(t, err)
+
+	dependents, err = testStore.DeleteMovie(context.Background(), movieID, admin, true)
+	require.NoError(t, err)
+	require.Len(t, dependents.Reservations, 1)
+
+	_, err = testStore.GetMovie(context.Background(), movieID)
+	require.EqualError(t, err, ErrMovieNotFound.Error())
+	_, err = testStore.GetRepertoire(context.Background(), reservation.RepertoiresID.Hex())
+	require.EqualError(t, err, ErrRepertoireNotFound.Error())
+	_, err = testStore.GetReservationById(context.Background(), reservation.ID.Hex())
+	require.Error(t, err)
+
+	notifications, err := testStore.ListNotifications(context.Background(), reservation.Username)
+	require.NoError(t, err)
+	require.NotEmpty(t, notifications)
+	require.Equal(t, models.NotificationScreeningCancelled, notifications[0].Type)
+	require.Equal(t, reservation.ID, notifications[0].ReservationID)
+}
+
+func TestDeleteHallWithRepertoires(t *testing.T) {
+	repertoire := createRandomRepertoire(t)
+	halls, err := testStore.GetHall(context.Background(), repertoire.Hall)
+	require.NoError(t, err)
+	require.Len(t, halls, 1)
+
+	dependents, err := testStore.DeleteHall(context.Background(), halls[0].ID.Hex(), util.RandomOwner(), false)
+	require.ErrorIs(t, err, ErrHasDependents)
+	require.Len(t, dependents.Repertoires, 1)
+	require.Empty(t, dependents.Reservations)
+}
+
+func TestDeleteMovieWithPastRepertoires(t *testing.T) {
+	movie := createRandomMovie(t)
+	hall := createRandomHall(t)
+
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	date, err := util.ParseDate(yesterday)
+	require.NoError(t, err)
+	repertoire, err := testStore.AddRepertoire(context.Background(), &models.Repertoire{
+		MovieID: movie.ID,
+		DateSt:  yesterday,
+		Date:    date,
+		Time:    "10:00",
+		Hall:    hall.Name,
+	})
+	require.NoError(t, err)
+
+	// a past screening is history, not a dependent
+	dependents, err := testStore.DeleteMovie(context.Background(), movie.ID.Hex(), util.RandomOwner(), false)
+	require.NoError(t, err)
+	require.True(t, dependents.Empty())
+
+	_, err = testStore.GetRepertoire(context.Background(), repertoire.ID.Hex())
+	require.NoError(t, err)
+}
+
+func TestDeleteRepertoireForMovieWithReservations(t *testing.T) {
+	reservation := createRandomReservation(t)
+	movieID := reservation.MovieID.Hex()
+	admin := util.RandomOwner()
+
+	dependents, err := testStore.DeleteRepertoireForMovie(context.Background(), movieID, admin, false)
+	require.ErrorIs(t, err, ErrHasDependents)
+	require.Equal(t, []primitive.ObjectID{reservation.ID}, dependents.Reservations)
+
+	_, err = testStore.GetRepertoire(context.Background(), reservation.RepertoiresID.Hex())
+	require.NoError(t, err)
+
+	dependents, err = testStore.DeleteRepertoireForMovie(context.Background(), movieID, admin, true)
+	require.NoError(t, err)
+	require.Len(t, dependents.Reservations, 1)
+
+	_, err = testStore.GetRepertoire(context.Background(), reservation.RepertoiresID.Hex())
+	require.EqualError(t, err, ErrRepertoireNotFound.Error())
+	_, err = testStore.GetReservationById(context.Background(), reservation.ID.Hex())
+	require.Error(t, err)
+
+	notifications, err := testStore.ListNotifications(context.Background(), reservation.Username)
+	require.NoError(t, err)
+	require.NotEmpty(t, notifications)
+	require.Equal(t, reservation.ID, notifications[0].ReservationID)
+}
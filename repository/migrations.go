@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// migration is a one-off data change applied once per database
+type migration struct {
+	ID string
+	Up func(ctx context.Context, db *mongo.Database) error
+}
+
+// migrations are applied in order; append new ones at the end and never reorder them
+var migrations = []migration{
+	{ID: "0001_movie_people_lists", Up: migrateMoviePeopleLists},
+	{ID: "0002_hall_ids", Up: migrateHallIDs},
+	{ID: "0003_hall_seat_maps", Up: migrateHallSeatMaps},
+	{ID: "0004_cinemas", Up: migrateCinemas},
+}
+
+// appliedMigration is the record kept in the migrations collection
+type appliedMigration struct {
+	ID        string    `bson:"_id"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// RunMigrations applies the migrations that have not been applied yet
+func (r *MongoStore) RunMigrations(ctx context.Context) error {
+	applied, err := r.appliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.ID] {
+			continue
+		}
+		if err := m.Up(ctx, r.db(ctx)); err != nil {
+			return fmt.Errorf("migration %s failed: %w", m.ID, err)
+		}
+		_, err := r.db(ctx).Collection("migrations").InsertOne(ctx, appliedMigration{ID: m.ID, AppliedAt: time.Now()})
+		if err != nil {
+			return fmt.Errorf("could not record migration %s: %w", m.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// PendingMigrations returns the IDs of the migrations that have not been applied yet
+func (r *MongoStore) PendingMigrations(ctx context.Context) ([]string, error) {
+	applied, err := r.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]string, 0)
+	for _, m := range migrations {
+		if !applied[m.ID] {
+			pending = append(pending, m.ID)
+		}
+	}
+	return pending, nil
+}
+
+func (r *MongoStore) appliedMigrations(ctx context.Context) (map[string]bool, error) {
+	cur, err := r.db(ctx).Collection("migrations").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("could not read applied migrations: %w", err)
+	}
+
+	var records []appliedMigration
+	if err = cur.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("could not read applied migrations: %w", err)
+	}
+
+	applied := make(map[string]bool, len(records))
+	for _, record := range records {
+		applied[record.ID] = true
+	}
+	return applied, nil
+}
+
+// migrateMoviePeopleLists converts the comma-separated genre, directors and
+// actors strings of movies into arrays of trimmed names.
+func migrateMoviePeopleLists(ctx context.Context, db *mongo.Database) error {
+	for _, field := range []string{"genre", "directors", "actors"} {
+		split := bson.M{"$filter": bson.M{
+			"input": bson.M{"$map": bson.M{
+				"input": bson.M{"$split": bson.A{"$" + field, ","}},
+				"as":    "item",
+				"in":    bson.M{"$trim": bson.M{"input": "$$item"}},
+			}},
+			"as":   "item",
+			"cond": bson.M{"$ne": bson.A{"$$item", ""}},
+		}}
+
+		_, err := db.Collection("movies").UpdateMany(ctx,
+			bson.M{field: bson.M{"$type": "string"}},
+			mongo.Pipeline{bson.D{{Key: "$set", Value: bson.M{field: split}}}},
+		)
+		if err != nil {
+			return fmt.Errorf("could not convert movie %s: %w", field, err)
+		}
+	}
+
+	return nil
+}
+
+// migrateHallIDs makes hall names unique and references halls by ID from
+// repertoires and reservations. Of halls sharing a name the oldest keeps it and
+// gets the existing references; the others are renamed to "name (2)" etc.
+func migrateHallIDs(ctx context.Context, db *mongo.Database) error {
+	cur, err := db.Collection("halls").Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return fmt.Errorf("could not read halls: %w", err)
+	}
+
+	var halls []models.Hall
+	if err = cur.All(ctx, &halls); err != nil {
+		return fmt.Errorf("could not read halls: %w", err)
+	}
+
+	taken := make(map[string]bool, len(halls))
+	for _, hall := range halls {
+		taken[hall.Name] = true
+	}
+
+	owners := make(map[string]bool, len(halls))
+	for _, hall := range halls {
+		if owners[hall.Name] {
+			name := hall.Name
+			for n := 2; taken[name]; n++ {
+				name = fmt.Sprintf("%s (%d)", hall.Name, n)
+			}
+			taken[name] = true
+
+			_, err := db.Collection("halls").UpdateOne(ctx, bson.M{"_id": hall.ID}, bson.M{"$set": bson.M{"name": name}})
+			if err != nil {
+				return fmt.Errorf("could not rename hall %s: %w", hall.ID.Hex(), err)
+			}
+			continue
+		}
+		owners[hall.Name] = true
+
+		for _, collection := range []string{"repertoires", "reservations"} {
+			_, err := db.Collection(collection).UpdateMany(ctx,
+				bson.M{"hall": hall.Name, "hallId": bson.M{"$exists": false}},
+				bson.M{"$set": bson.M{"hallId": hall.ID}},
+			)
+			if err != nil {
+				return fmt.Errorf("could not set hall ids in %s: %w", collection, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// migrateHallSeatMaps generates the seat map of the halls created before seat
+// maps existed from their rows and cols.
+func migrateHallSeatMaps(ctx context.Context, db *mongo.Database) error {
+	cur, err := db.Collection("halls").Find(ctx, bson.M{"seats": bson.M{"$exists": false}})
+	if err != nil {
+		return fmt.Errorf("could not read halls: %w", err)
+	}
+
+	var halls []models.Hall
+	if err = cur.All(ctx, &halls); err != nil {
+		return fmt.Errorf("could not read halls: %w", err)
+	}
+
+	for _, hall := range halls {
+		_, err := db.Collection("halls").UpdateOne(ctx,
+			bson.M{"_id": hall.ID},
+			bson.M{"$set": bson.M{"seats": models.BuildLayout(hall.Rows, hall.Cols)}},
+		)
+		if err != nil {
+			return fmt.Errorf("could not set the seat map of hall %s: %w", hall.ID.Hex(), err)
+		}
+	}
+
+	return nil
+}
+
+// defaultCinemaName is the cinema created by the 0004_cinemas migration for
+// the halls that existed before cinemas did
+const defaultCinemaName = "Cinema"
+
+// migrateCinemas assigns the halls without a cinema, and their repertoires
+// and reservations, to the oldest cinema, creating one when there is none.
+func migrateCinemas(ctx context.Context, db *mongo.Database) error {
+	cur, err := db.Collection("halls").Find(ctx, bson.M{"cinemaId": bson.M{"$exists": false}})
+	if err != nil {
+		return fmt.Errorf("could not read halls: %w", err)
+	}
+
+	var halls []models.Hall
+	if err = cur.All(ctx, &halls); err != nil {
+		return fmt.Errorf("could not read halls: %w", err)
+	}
+	if len(halls) == 0 {
+		return nil
+	}
+
+	var cinema models.Cinema
+	err = db.Collection("cinemas").FindOne(ctx, notDeleted(bson.M{}), options.FindOne().SetSort(bson.M{"_id": 1})).Decode(&cinema)
+	if err == mongo.ErrNoDocuments {
+		cinema = models.Cinema{
+			ID:        primitive.NewObjectID(),
+			Name:      defaultCinemaName,
+			TimeZone:  "UTC",
+			CreatedAt: time.Now(),
+		}
+		_, err = db.Collection("cinemas").InsertOne(ctx, cinema)
+	}
+	if err != nil {
+		return fmt.Errorf("could not get the default cinema: %w", err)
+	}
+
+	for _, hall := range halls {
+		_, err := db.Collection("halls").UpdateOne(ctx, bson.M{"_id": hall.ID}, bson.M{"$set": bson.M{"cinemaId": cinema.ID}})
+		if err != nil {
+			return fmt.Errorf("could not set the cinema of hall %s: %w", hall.ID.Hex(), err)
+		}
+
+		for _, collection := range []string{"repertoires", "reservations"} {
+			_, err := db.Collection(collection).UpdateMany(ctx,
+				bson.M{"hallId": hall.ID},
+				bson.M{"$set": bson.M{"cinemaId": cinema.ID}},
+			)
+			if err != nil {
+				return fmt.Errorf("could not set cinema ids in %s: %w", collection, err)
+			}
+		}
+	}
+
+	return nil
+}
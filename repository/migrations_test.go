@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tijanadmi/movieginmongoapi/util"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMigrateMoviePeopleLists(t *testing.T) {
+	store := testStore.(*MongoStore)
+
+	id := primitive.NewObjectID()
+	_, err := store.db(context.Background()).Collection("movies").InsertOne(context.Background(), bson.M{
+		"_id":       id,
+		"title":     util.RandomString(50),
+		"genre":     "drama, komedija",
+		"directors": "Kameron",
+		"actors":    "Leonardo Di Kaprio, , Kejt Vinslet",
+	})
+	require.NoError(t, err)
+
+	err = migrateMoviePeopleLists(context.Background(), store.db(context.Background()))
+	require.NoError(t, err)
+
+	var raw bson.M
+	err = store.db(context.Background()).Collection("movies").FindOne(context.Background(), bson.M{"_id": id}).Decode(&raw)
+	require.NoError(t, err)
+	require.Equal(t, bson.A{"drama", "komedija"}, raw["genre"])
+	require.Equal(t, bson.A{"Kameron"}, raw["directors"])
+	require.Equal(t, bson.A{"Leonardo Di Kaprio", "Kejt Vinslet"}, raw["actors"])
+}
+
+func TestRunMigrations(t *testing.T) {
+	require.NoError(t, testStore.RunMigrations(context.Background()))
+
+	// a second run finds nothing left to apply
+	require.NoError(t, testStore.RunMigrations(context.Background()))
+}
+
+func TestMigrateHallIDs(t *testing.T) {
+	store := testStore.(*MongoStore)
+	hall := createRandomHall(t)
+
+	repertoireID := primitive.NewObjectID()
+	_, err := store.db(context.Background()).Collection("repertoires").InsertOne(context.Background(), bson.M{
+		"_id":  repertoireID,
+		"time": "20:00",
+		"hall": hall.Name,
+	})
+	require.NoError(t, err)
+
+	err = migrateHallIDs(context.Background(), store.db(context.Background()))
+	require.NoError(t, err)
+
+	repertoire, err := testStore.GetRepertoire(context.Background(), repertoireID.Hex())
+	require.NoError(t, err)
+	require.Equal(t, hall.ID, repertoire.HallID)
+	require.Equal(t, hall.Name, repertoire.Hall)
+}
@@ -1,18 +1,31 @@
 package repository
 
 import (
+	"context"
+
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 type MongoStore struct {
 	client *mongo.Client
-	db     *mongo.Database
+	dbName string
 }
 
 func NewStore(client *mongo.Client, dbName string) Store {
-	database := client.Database(dbName)
 	return &MongoStore{
 		client: client,
-		db:     database,
+		dbName: dbName,
 	}
 }
+
+// db returns the database of the tenant of ctx. Every query goes through it,
+// so a request can only ever see the data of its own tenant.
+func (r *MongoStore) db(ctx context.Context) *mongo.Database {
+	return r.client.Database(TenantDatabase(r.dbName, TenantFromContext(ctx)))
+}
+
+// Ping checks that the primary of the database can be reached
+func (r *MongoStore) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx, readpref.Primary())
+}
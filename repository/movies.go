@@ -4,9 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"regexp"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/tijanadmi/movieginmongoapi/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -23,9 +24,9 @@ var (
 func (r *MongoStore) AddMovie(ctx context.Context, movie *models.Movie) (*models.Movie, error) {
 	movie.ID = primitive.NewObjectID()
 	movie.CreatedAt = time.Now()
-	result, err := r.db.Collection("movies").InsertOne(ctx, movie)
+	result, err := r.db(ctx).Collection("movies").InsertOne(ctx, movie)
 	if err != nil {
-		log.Print(fmt.Errorf("could not add new movie: %w", err))
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not add new movie")
 		return nil, err
 	}
 	movie.ID = result.InsertedID.(primitive.ObjectID)
@@ -35,19 +36,7 @@ func (r *MongoStore) AddMovie(ctx context.Context, movie *models.Movie) (*models
 
 // ListMovies returns all movies from the MongoDB collection
 func (r *MongoStore) ListMovies(ctx context.Context) ([]models.Movie, error) {
-	movies := make([]models.Movie, 0)
-	cur, err := r.db.Collection("movies").Find(ctx, bson.M{})
-	if err != nil {
-		log.Print(fmt.Errorf("could not get all movies: %w", err))
-		return nil, err
-	}
-
-	if err = cur.All(ctx, &movies); err != nil {
-		log.Print(fmt.Errorf("could not marshall the movies results: %w", err))
-		return nil, err
-	}
-
-	return movies, nil
+	return r.findMovies(ctx, bson.M{})
 }
 
 // GetMovie returns a movie by ID from the MongoDB collection
@@ -59,7 +48,7 @@ func (r *MongoStore) GetMovie(ctx context.Context, id string) (*models.Movie, er
 	}
 
 	var movie models.Movie
-	result := r.db.Collection("movies").FindOne(ctx, bson.M{"_id": objID})
+	result := r.db(ctx).Collection("movies").FindOne(ctx, notDeleted(bson.M{"_id": objID}))
 	err = result.Decode(&movie)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -77,7 +66,7 @@ func (r *MongoStore) UpdateMovie(ctx context.Context, id string, movie *models.M
 	if err != nil {
 		return nil, err
 	}
-	res, err := r.db.Collection("movies").UpdateOne(ctx, bson.M{"_id": objID}, bson.D{
+	res, err := r.db(ctx).Collection("movies").UpdateOne(ctx, notDeleted(bson.M{"_id": objID}), bson.D{
 		{"$set", bson.D{
 			{"title", movie.Title},
 			{"duration", movie.Duration},
@@ -86,12 +75,14 @@ func (r *MongoStore) UpdateMovie(ctx context.Context, id string, movie *models.M
 			{"actors", movie.Actors},
 			{"screening", movie.Screening},
 			{"plot", movie.Plot},
+			{"translations", movie.Translations},
 			{"poster", movie.Poster},
 			{"screenings", movie.Screenings},
+			{"ageRating", movie.AgeRating},
 		}},
 	})
 	if err != nil {
-		log.Print(fmt.Errorf("could not update movie with id [%s]: %w", id, err))
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not update movie with id [%s]", id)
 		return &models.Movie{}, err
 	}
 
@@ -103,33 +94,50 @@ func (r *MongoStore) UpdateMovie(ctx context.Context, id string, movie *models.M
 	return movie, nil
 }
 
-// DeleteMovie deletes a movie by ID from the MongoDB collection
-func (r *MongoStore) DeleteMovie(ctx context.Context, id string) error {
+// UpdateMoviePoster sets the poster URL and the URLs of its resized variants
+func (r *MongoStore) UpdateMoviePoster(ctx context.Context, id string, poster string, sizes map[string]string) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
-	res, err := r.db.Collection("movies").DeleteOne(ctx, bson.M{"_id": objID})
+	res, err := r.db(ctx).Collection("movies").UpdateOne(ctx, notDeleted(bson.M{"_id": objID}), bson.M{
+		"$set": bson.M{
+			"poster":       poster,
+			"poster_sizes": sizes,
+		},
+	})
 	if err != nil {
-		log.Print(fmt.Errorf("error deleting the movie with id [%s]: %w", id, err))
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not update poster of movie with id [%s]", id)
 		return err
 	}
 
-	if res.DeletedCount == 0 {
+	if res.MatchedCount == 0 {
 		return ErrMovieNotFound
 	}
 
 	return nil
 }
 
+// DeleteMovie soft deletes a movie by ID. Its repertoires and their upcoming
+// reservations are dependents, see deleteWithDependents.
+func (r *MongoStore) DeleteMovie(ctx context.Context, id string, deletedBy string, cascade bool) (Dependents, error) {
+	return r.deleteWithDependents(ctx, "movies", id, deletedBy, cascade, ErrMovieNotFound,
+		func(ctx context.Context) ([]models.Repertoire, []primitive.ObjectID, error) {
+			movie, err := r.GetMovie(ctx, id)
+			if err != nil {
+				return nil, nil, err
+			}
+			return r.scheduledRepertoires(ctx, bson.M{"movieId": movie.ID})
+		})
+}
+
 // GetHall returns a hall by ID from the MongoDB collection
 func (r *MongoStore) SearchMovies(ctx context.Context, movieId string) ([]models.Movie, error) {
 	movies := make([]models.Movie, 0)
-	fmt.Println(movieId)
 	// Provera inicijalizacije kolekcije
-	if r.db.Collection("movies") == nil {
-		log.Print(fmt.Errorf("collection is not initialized:"))
+	if r.db(ctx).Collection("movies") == nil {
+		zerolog.Ctx(ctx).Error().Msg("collection is not initialized")
 		return nil, fmt.Errorf("collection is not initialized")
 	}
 
@@ -139,13 +147,13 @@ func (r *MongoStore) SearchMovies(ctx context.Context, movieId string) ([]models
 	if movieId != "0" {
 		objectId, err := primitive.ObjectIDFromHex(movieId)
 		if err != nil {
-			log.Print(fmt.Errorf("invalid movie ID: %w", err))
+			zerolog.Ctx(ctx).Error().Err(err).Msg("invalid movie ID")
 			return nil, err
 		}
-		matchStage = bson.D{{"$match", bson.D{{"_id", objectId}}}}
+		matchStage = bson.D{{"$match", notDeleted(bson.M{"_id": objectId})}}
 		//matchStage = bson.D{{"$match", bson.D{{"_id", movieId}}}}
 	} else {
-		matchStage = bson.D{{"$match", bson.D{}}}
+		matchStage = bson.D{{"$match", notDeleted(bson.M{})}}
 	}
 
 	pipeline := mongo.Pipeline{
@@ -153,11 +161,16 @@ func (r *MongoStore) SearchMovies(ctx context.Context, movieId string) ([]models
 		{
 			{"$lookup", bson.D{
 				{"from", "repertoires"},
-				{"localField", "_id"},
-				{"foreignField", "movieId"},
+				{"let", bson.M{"movieId": "$_id"}},
+				{"pipeline", []bson.M{
+					{"$match": notDeleted(bson.M{"$expr": bson.M{"$eq": bson.A{"$movieId", "$$movieId"}}})},
+				}},
 				{"as", "screenings"},
 			}},
 		},
+	}
+	pipeline = append(pipeline, ratingStages()...)
+	pipeline = append(pipeline, mongo.Pipeline{
 		{
 			{"$project", bson.D{
 				{"_id", 1},
@@ -168,28 +181,228 @@ func (r *MongoStore) SearchMovies(ctx context.Context, movieId string) ([]models
 				{"actors", 1},
 				{"screening", 1},
 				{"plot", 1},
+				{"translations", 1},
 				{"poster", 1},
+				{"poster_sizes", 1},
 				{"screenings.date", 1},
 				{"screenings.time", 1},
+				{"screenings.hallId", 1},
 				{"screenings.hall", 1},
+				{"avgRating", 1},
+				{"numOfRatings", 1},
+				{"ageRating", 1},
 			}},
 		},
-	}
+	}...)
 
 	// Izvršavanje agregacije
-	cursor, err := r.db.Collection("movies").Aggregate(ctx, pipeline)
+	cursor, err := r.db(ctx).Collection("movies").Aggregate(ctx, pipeline)
 	if err != nil {
-		log.Print(fmt.Errorf("could not aggregate movies: %w", err))
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not aggregate movies")
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
 	// Parsiranje rezultata
 	if err := cursor.All(ctx, &movies); err != nil {
-		log.Print(fmt.Errorf("could not unmarshal the movies results: %w", err))
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not unmarshal the movies results")
 		return nil, err
 	}
 
 	return movies, nil
 
 }
+
+// Person roles accepted by ListMoviesByPerson
+const (
+	PersonRoleActor    = "actor"
+	PersonRoleDirector = "director"
+)
+
+var ErrInvalidPersonRole = errors.New("role must be actor or director")
+
+// exactNameMatch matches a list element equal to name, ignoring case
+func exactNameMatch(name string) bson.M {
+	return bson.M{"$regex": "^" + regexp.QuoteMeta(name) + "$", "$options": "i"}
+}
+
+// ListMoviesByGenre returns all movies of the given genre
+func (r *MongoStore) ListMoviesByGenre(ctx context.Context, genre string) ([]models.Movie, error) {
+	return r.findMovies(ctx, bson.M{"genre": exactNameMatch(genre)})
+}
+
+// ListMoviesByPerson returns all movies the person acted in or directed.
+// An empty role matches both actors and directors.
+func (r *MongoStore) ListMoviesByPerson(ctx context.Context, name string, role string) ([]models.Movie, error) {
+	var filter bson.M
+	switch role {
+	case "":
+		filter = bson.M{"$or": bson.A{
+			bson.M{"actors": exactNameMatch(name)},
+			bson.M{"directors": exactNameMatch(name)},
+		}}
+	case PersonRoleActor:
+		filter = bson.M{"actors": exactNameMatch(name)}
+	case PersonRoleDirector:
+		filter = bson.M{"directors": exactNameMatch(name)}
+	default:
+		return nil, ErrInvalidPersonRole
+	}
+
+	return r.findMovies(ctx, filter)
+}
+
+// findMovies returns the movies matching filter by title, with their ratings
+func (r *MongoStore) findMovies(ctx context.Context, filter bson.M) ([]models.Movie, error) {
+	movies := make([]models.Movie, 0)
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: notDeleted(filter)}},
+		{{Key: "$sort", Value: bson.D{{Key: "title", Value: 1}}}},
+	}
+	pipeline = append(pipeline, ratingStages()...)
+
+	cur, err := r.db(ctx).Collection("movies").Aggregate(ctx, pipeline)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not get movies")
+		return nil, err
+	}
+
+	if err = cur.All(ctx, &movies); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not marshall the movies results")
+		return nil, err
+	}
+
+	return movies, nil
+}
+
+// SearchMoviesParams contains the filters of the full-text movie search.
+// CurrentlyShown keeps the screenings from today on, where today is the date
+// at the cinema of each screening.
+type SearchMoviesParams struct {
+	Query          string
+	Genre          string
+	CurrentlyShown bool
+	From           time.Time
+	To             time.Time
+}
+
+// cinemaToday is an expression for the current date at the cinema of a
+// repertoire, as the UTC midnight its date is stored with. Repertoires of
+// cinemas that are not listed use the date in UTC.
+func (r *MongoStore) cinemaToday(ctx context.Context, now time.Time) (interface{}, error) {
+	cinemas, err := r.ListCinemas(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	utcToday := now.UTC().Truncate(24 * time.Hour)
+	if len(cinemas) == 0 {
+		return utcToday, nil
+	}
+	branches := make(bson.A, 0, len(cinemas))
+	for _, cinema := range cinemas {
+		branches = append(branches, bson.M{
+			"case": bson.M{"$eq": bson.A{"$cinemaId", cinema.ID}},
+			"then": cinema.Today(now),
+		})
+	}
+	return bson.M{"$switch": bson.M{"branches": branches, "default": utcToday}}, nil
+}
+
+// SearchMoviesText returns movies matching the full-text query, ordered by relevance
+func (r *MongoStore) SearchMoviesText(ctx context.Context, arg SearchMoviesParams) ([]models.Movie, error) {
+	movies := make([]models.Movie, 0)
+
+	match := notDeleted(bson.M{})
+	if arg.Query != "" {
+		match["$text"] = bson.M{
+			"$search":             arg.Query,
+			"$caseSensitive":      false,
+			"$diacriticSensitive": false,
+		}
+	}
+	if arg.Genre != "" {
+		match["genre"] = exactNameMatch(arg.Genre)
+	}
+
+	// Only screenings inside the requested window are joined, so the
+	// date filters also narrow down the listed screenings.
+	screeningsFilter := bson.A{bson.M{"$eq": bson.A{"$movieId", "$$movieId"}}}
+	if arg.CurrentlyShown {
+		today, err := r.cinemaToday(ctx, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		screeningsFilter = append(screeningsFilter, bson.M{"$gte": bson.A{"$date", today}})
+	}
+	if !arg.From.IsZero() {
+		screeningsFilter = append(screeningsFilter, bson.M{"$gte": bson.A{"$date", arg.From}})
+	}
+	if !arg.To.IsZero() {
+		screeningsFilter = append(screeningsFilter, bson.M{"$lte": bson.A{"$date", arg.To}})
+	}
+	dateFiltered := arg.CurrentlyShown || !arg.From.IsZero() || !arg.To.IsZero()
+
+	pipeline := bson.A{
+		bson.M{"$match": match},
+		bson.M{"$lookup": bson.M{
+			"from":     "repertoires",
+			"let":      bson.M{"movieId": "$_id"},
+			"pipeline": []bson.M{{"$match": notDeleted(bson.M{"$expr": bson.M{"$and": screeningsFilter}})}},
+			"as":       "screenings",
+		}},
+	}
+	if dateFiltered {
+		pipeline = append(pipeline, bson.M{"$match": bson.M{"screenings.0": bson.M{"$exists": true}}})
+	}
+	for _, stage := range ratingStages() {
+		pipeline = append(pipeline, stage)
+	}
+
+	project := bson.M{
+		"_id":               1,
+		"title":             1,
+		"duration":          1,
+		"genre":             1,
+		"directors":         1,
+		"actors":            1,
+		"screening":         1,
+		"plot":              1,
+		"translations":      1,
+		"poster":            1,
+		"poster_sizes":      1,
+		"screenings.date":   1,
+		"screenings.time":   1,
+		"screenings.hallId": 1,
+		"screenings.hall":   1,
+		"avgRating":         1,
+		"numOfRatings":      1,
+		"ageRating":         1,
+	}
+	if arg.Query != "" {
+		project["score"] = bson.M{"$meta": "textScore"}
+		pipeline = append(pipeline,
+			bson.M{"$project": project},
+			bson.M{"$sort": bson.D{{Key: "score", Value: -1}, {Key: "title", Value: 1}}},
+		)
+	} else {
+		pipeline = append(pipeline,
+			bson.M{"$project": project},
+			bson.M{"$sort": bson.M{"title": 1}},
+		)
+	}
+
+	cursor, err := r.db(ctx).Collection("movies").Aggregate(ctx, pipeline)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not search movies [%s]", arg.Query)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &movies); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not unmarshal the movies results")
+		return nil, err
+	}
+
+	return movies, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,9 +17,9 @@ func createRandomMovie(t *testing.T) *models.Movie {
 	arg := models.Movie{
 		Title:     util.RandomString(50),
 		Duration:  int32(util.RandomInt(100, 250)),
-		Genre:     util.RandomString(200),
-		Directors: util.RandomString(200),
-		Actors:    util.RandomString(200),
+		Genre:     models.StringList{util.RandomString(10)},
+		Directors: models.StringList{util.RandomString(20)},
+		Actors:    models.StringList{util.RandomString(20), util.RandomString(20)},
 		Screening: time.Now(),
 		Plot:      util.RandomString(200),
 		Poster:    util.RandomString(200),
@@ -114,7 +115,7 @@ func TestUpdateMovie(t *testing.T) {
 
 func TestDeleteMovie(t *testing.T) {
 	movie1 := createRandomMovie(t)
-	err := testStore.DeleteMovie(context.Background(), movie1.ID.Hex())
+	_, err := testStore.DeleteMovie(context.Background(), movie1.ID.Hex(), util.RandomOwner(), false)
 	require.NoError(t, err)
 
 	movie2, err := testStore.GetMovie(context.Background(), movie1.ID.Hex())
@@ -123,3 +124,87 @@ func TestDeleteMovie(t *testing.T) {
 	require.EqualError(t, err, ErrMovieNotFound.Error())
 	require.Empty(t, movie2)
 }
+
+func TestSearchMoviesText(t *testing.T) {
+	require.NoError(t, testStore.EnsureIndexes(context.Background()))
+
+	movie := createRandomMovie(t)
+	createRandomRepertoireForMovie(t, movie.ID)
+
+	movies, err := testStore.SearchMoviesText(context.Background(), SearchMoviesParams{
+		Query:          movie.Title,
+		CurrentlyShown: true,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, movies)
+
+	require.Equal(t, movie.ID, movies[0].ID)
+	require.NotZero(t, movies[0].Score)
+	require.NotEmpty(t, movies[0].Screenings)
+}
+
+func TestSearchMoviesTextShowingInCinemaTimeZone(t *testing.T) {
+	movie := createRandomMovie(t)
+
+	// the dates of these cinemas differ by a day or two at any moment
+	addScreening := func(timeZone string, day func(cinema *models.Cinema) time.Time) *models.Repertoire {
+		cinema, err := testStore.AddCinema(context.Background(), &models.Cinema{Name: util.RandomString(10), TimeZone: timeZone})
+		require.NoError(t, err)
+		hall, err := testStore.InsertHall(context.Background(), &models.Hall{CinemaID: cinema.ID, Name: util.RandomHall(), Rows: []string{"A"}, Cols: []int{1, 2}})
+		require.NoError(t, err)
+		date := day(cinema)
+		repertoire, err := testStore.AddRepertoire(context.Background(), &models.Repertoire{
+			MovieID: movie.ID,
+			DateSt:  date.Format("2006-01-02"),
+			Date:    date,
+			Time:    "20:00",
+			HallID:  hall.ID,
+		})
+		require.NoError(t, err)
+		return repertoire
+	}
+	today := addScreening("Pacific/Pago_Pago", func(cinema *models.Cinema) time.Time {
+		return cinema.Today(time.Now())
+	})
+	addScreening("Pacific/Kiritimati", func(cinema *models.Cinema) time.Time {
+		return cinema.Today(time.Now()).AddDate(0, 0, -1)
+	})
+
+	movies, err := testStore.SearchMoviesText(context.Background(), SearchMoviesParams{
+		Query:          movie.Title,
+		CurrentlyShown: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, movies, 1)
+	require.Len(t, movies[0].Screenings, 1)
+	require.Equal(t, today.HallID, movies[0].Screenings[0].HallID)
+}
+
+func TestListMoviesByGenre(t *testing.T) {
+	movie := createRandomMovie(t)
+
+	movies, err := testStore.ListMoviesByGenre(context.Background(), strings.ToUpper(movie.Genre[0]))
+	require.NoError(t, err)
+	require.Len(t, movies, 1)
+	require.Equal(t, movie.ID, movies[0].ID)
+}
+
+func TestListMoviesByPerson(t *testing.T) {
+	movie := createRandomMovie(t)
+
+	movies, err := testStore.ListMoviesByPerson(context.Background(), movie.Actors[1], PersonRoleActor)
+	require.NoError(t, err)
+	require.Len(t, movies, 1)
+	require.Equal(t, movie.ID, movies[0].ID)
+
+	movies, err = testStore.ListMoviesByPerson(context.Background(), movie.Directors[0], "")
+	require.NoError(t, err)
+	require.Len(t, movies, 1)
+
+	movies, err = testStore.ListMoviesByPerson(context.Background(), movie.Directors[0], PersonRoleActor)
+	require.NoError(t, err)
+	require.Empty(t, movies)
+
+	_, err = testStore.ListMoviesByPerson(context.Background(), movie.Directors[0], "producer")
+	require.ErrorIs(t, err, ErrInvalidPersonRole)
+}
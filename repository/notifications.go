@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ListNotifications returns the notifications of a user, newest first
+func (r *MongoStore) ListNotifications(ctx context.Context, username string) ([]models.Notification, error) {
+	notifications := make([]models.Notification, 0)
+	cur, err := r.db(ctx).Collection("notifications").Find(ctx, bson.M{"username": username},
+		options.Find().SetSort(bson.M{"creation_date": -1}))
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not get notifications for [%s]", username)
+		return nil, err
+	}
+
+	if err = cur.All(ctx, &notifications); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not marshall the notifications results")
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// notifyCancelled tells the owners of the reservations that their screening was cancelled
+func (r *MongoStore) notifyCancelled(ctx context.Context, reservations []models.Reservation) error {
+	if len(reservations) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(reservations))
+	for _, reservation := range reservations {
+		docs = append(docs, models.Notification{
+			Username: reservation.Username,
+			Type:     models.NotificationScreeningCancelled,
+			Message: fmt.Sprintf("The screening of %s on %s at %s in %s has been cancelled",
+				reservation.MovieTitle, reservation.Date.Format("2006-01-02"), reservation.Time, reservation.Hall),
+			ReservationID: reservation.ID,
+			CreatedAt:     now,
+		})
+	}
+
+	if _, err := r.db(ctx).Collection("notifications").InsertMany(ctx, docs); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not add notifications")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetProgram returns all screenings of a day grouped by hall and sorted by start time,
+// only of the given cinema unless cinemaId is empty.
+// Blocked seats are not available but do not count towards the occupancy.
+func (r *MongoStore) GetProgram(ctx context.Context, date time.Time, cinemaId string) ([]models.ProgramHall, error) {
+	program := make([]models.ProgramHall, 0)
+
+	match := bson.M{"date": date}
+	if cinemaId != "" {
+		cinemaID, err := primitive.ObjectIDFromHex(cinemaId)
+		if err != nil {
+			return nil, ErrCinemaNotFound
+		}
+		match["cinemaId"] = cinemaID
+	}
+
+	pipeline := []bson.M{
+		{"$match": notDeleted(match)},
+		{"$lookup": bson.M{
+			"from": "movies",
+			"let":  bson.M{"movieId": "$movieId"},
+			"pipeline": []bson.M{
+				{"$match": notDeleted(bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$movieId"}}})},
+			},
+			"as": "movie",
+		}},
+		// screenings of deleted movies are not part of the program
+		{"$unwind": "$movie"},
+		{"$sort": bson.D{{Key: "hall", Value: 1}, {Key: "time", Value: 1}}},
+		{"$group": bson.M{
+			"_id":  "$hallId",
+			"hall": bson.M{"$first": "$hall"},
+			"screenings": bson.M{"$push": bson.M{
+				"repertoireId":    "$_id",
+				"movieId":         "$movieId",
+				"movieTitle":      "$movie.title",
+				"duration":        "$movie.duration",
+				"time":            "$time",
+				"numOfTickets":    "$numOfTickets",
+				"numOfResTickets": "$numOfResTickets",
+				"occupancy": bson.M{"$cond": bson.A{
+					bson.M{"$gt": bson.A{"$numOfTickets", 0}},
+					bson.M{"$divide": bson.A{"$numOfResTickets", "$numOfTickets"}},
+					0,
+				}},
+			}},
+		}},
+		{"$sort": bson.M{"hall": 1}},
+		{"$project": bson.M{"_id": 0, "hallId": "$_id", "hall": 1, "screenings": 1}},
+	}
+
+	cursor, err := r.db(ctx).Collection("repertoires").Aggregate(ctx, pipeline)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not get program for %s", date)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &program); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not unmarshal the program results")
+		return nil, err
+	}
+
+	return program, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tijanadmi/movieginmongoapi/models"
+)
+
+func TestGetProgram(t *testing.T) {
+	repertoire := createRandomRepertoire(t)
+
+	program, err := testStore.GetProgram(context.Background(), repertoire.Date, repertoire.CinemaID.Hex())
+	require.NoError(t, err)
+	require.NotEmpty(t, program)
+
+	// the program of another cinema doesn't show the screening
+	other := createRandomCinema(t)
+	otherProgram, err := testStore.GetProgram(context.Background(), repertoire.Date, other.ID.Hex())
+	require.NoError(t, err)
+	require.Empty(t, otherProgram)
+
+	var hall *models.ProgramHall
+	for i := range program {
+		if program[i].Hall == repertoire.Hall {
+			hall = &program[i]
+		}
+	}
+	require.NotNil(t, hall)
+
+	var screening *models.ProgramScreening
+	for i := range hall.Screenings {
+		if hall.Screenings[i].RepertoireID == repertoire.ID {
+			screening = &hall.Screenings[i]
+		}
+		if i > 0 {
+			require.LessOrEqual(t, hall.Screenings[i-1].Time, hall.Screenings[i].Time)
+		}
+	}
+	require.NotNil(t, screening)
+	require.Equal(t, repertoire.MovieID, screening.MovieID)
+	require.NotEmpty(t, screening.MovieTitle)
+	require.Equal(t, repertoire.NumOfTickets, screening.NumOfTickets)
+	require.Equal(t, float64(repertoire.NumOfResTickets)/float64(repertoire.NumOfTickets), screening.Occupancy)
+}
@@ -3,10 +3,9 @@ package repository
 import (
 	"context"
 	"errors"
-	"fmt"
-	"log"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/tijanadmi/movieginmongoapi/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,20 +14,37 @@ import (
 
 var (
 	ErrRepertoireNotFound = errors.New("repertoire not found")
+	ErrTooManyTickets     = errors.New("numOfTickets exceeds the sellable seats of the hall")
 )
 
 // AddRepertoire adds a new repertoire to the MongoDB collection
 func (r *MongoStore) AddRepertoire(ctx context.Context, repertoire *models.Repertoire) (*models.Repertoire, error) {
+	hall, err := r.resolveHall(ctx, repertoire)
+	if err != nil {
+		return nil, err
+	}
+
+	// tickets are sold for the sellable seats of the hall by default
+	sellable := len(hall.SellableSeats())
+	if repertoire.NumOfTickets == 0 {
+		repertoire.NumOfTickets = sellable
+	}
+	if repertoire.NumOfTickets > sellable {
+		return nil, ErrTooManyTickets
+	}
+
+	// seats are blocked later with BlockSeats, once the screening exists
+	repertoire.BlockedSeats = nil
+
 	repertoire.ID = primitive.NewObjectID()
 	repertoire.CreatedAt = time.Now()
 	// Provera da li je numOfResTickets postavljen, ako nije postavi na 0
 	if repertoire.NumOfResTickets == 0 {
 		repertoire.NumOfResTickets = 0
 	}
-	fmt.Println("Repository NumOfResTickets", repertoire.NumOfResTickets)
-	result, err := r.db.Collection("repertoires").InsertOne(ctx, repertoire)
+	result, err := r.db(ctx).Collection("repertoires").InsertOne(ctx, repertoire)
 	if err != nil {
-		log.Print(fmt.Errorf("could not add new repertoire: %w", err))
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not add new repertoire")
 		return nil, err
 	}
 
@@ -39,14 +55,14 @@ func (r *MongoStore) AddRepertoire(ctx context.Context, repertoire *models.Reper
 // ListRepertoires returns all repertoires from the MongoDB collection
 func (r *MongoStore) ListRepertoires(ctx context.Context) ([]models.Repertoire, error) {
 	repertoires := make([]models.Repertoire, 0)
-	cur, err := r.db.Collection("repertoires").Find(ctx, bson.M{})
+	cur, err := r.db(ctx).Collection("repertoires").Find(ctx, notDeleted(bson.M{}))
 	if err != nil {
-		log.Print(fmt.Errorf("could not get all repertoires: %w", err))
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not get all repertoires")
 		return nil, err
 	}
 
 	if err = cur.All(ctx, &repertoires); err != nil {
-		log.Print(fmt.Errorf("could marshall the repertoires results: %w", err))
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could marshall the repertoires results")
 		return nil, err
 	}
 
@@ -62,7 +78,7 @@ func (r *MongoStore) GetRepertoire(ctx context.Context, id string) (*models.Repe
 	}
 
 	var repertoire models.Repertoire
-	result := r.db.Collection("repertoires").FindOne(ctx, bson.M{"_id": objID})
+	result := r.db(ctx).Collection("repertoires").FindOne(ctx, notDeleted(bson.M{"_id": objID}))
 	err = result.Decode(&repertoire)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -75,33 +91,35 @@ func (r *MongoStore) GetRepertoire(ctx context.Context, id string) (*models.Repe
 }
 
 // GetRepertoire returns a repertoire based on its ID
-func (r *MongoStore) GetRepertoireByMovieDateTimeHall(ctx context.Context, movieId string, dateValue time.Time, timeValue string, hallValue string) (models.Repertoire, error) {
+func (r *MongoStore) GetRepertoireByMovieDateTimeHall(ctx context.Context, movieId string, dateValue time.Time, timeValue string, hallId string) (models.Repertoire, error) {
 	var repertoire models.Repertoire
 	movieID, _ := primitive.ObjectIDFromHex(movieId)
+	hallID, _ := primitive.ObjectIDFromHex(hallId)
 	filter := bson.M{
 		"movieId": movieID,
 		"date":    dateValue,
 		"time":    timeValue,
-		"hall":    hallValue,
+		"hallId":  hallID,
 	}
-	res := r.db.Collection("repertoires").FindOne(ctx, filter)
+	res := r.db(ctx).Collection("repertoires").FindOne(ctx, notDeleted(filter))
 	if res.Err() != nil {
 		if errors.Is(res.Err(), mongo.ErrNoDocuments) {
 			return repertoire, nil
 		}
-		log.Print(fmt.Errorf("error when finding the repertoire [%s]: %q", movieID, res.Err()))
+		zerolog.Ctx(ctx).Error().Err(res.Err()).Msgf("error when finding the repertoire [%s]", movieID)
 		return repertoire, res.Err()
 	}
 
 	if err := res.Decode(&repertoire); err != nil {
-		log.Print(fmt.Errorf("error decoding [%s]: %q", movieID, err))
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("error decoding [%s]", movieID)
 		return repertoire, err
 	}
 	return repertoire, nil
 }
 
-// GetRepertoire returns a repertoires based on its movieId
-func (r *MongoStore) GetAllRepertoireForMovie(ctx context.Context, movieId string, startDate time.Time, endDate time.Time) ([]models.Repertoire, error) {
+// GetRepertoire returns a repertoires based on its movieId, only of the
+// given cinema unless cinemaId is empty
+func (r *MongoStore) GetAllRepertoireForMovie(ctx context.Context, movieId string, startDate time.Time, endDate time.Time, cinemaId string) ([]models.Repertoire, error) {
 	repertoires := make([]models.Repertoire, 0)
 
 	movieID, _ := primitive.ObjectIDFromHex(movieId)
@@ -112,14 +130,21 @@ func (r *MongoStore) GetAllRepertoireForMovie(ctx context.Context, movieId strin
 			"$lte": endDate,
 		},
 	}
-	cur, err := r.db.Collection("repertoires").Find(ctx, filter)
+	if cinemaId != "" {
+		cinemaID, err := primitive.ObjectIDFromHex(cinemaId)
+		if err != nil {
+			return nil, ErrCinemaNotFound
+		}
+		filter["cinemaId"] = cinemaID
+	}
+	cur, err := r.db(ctx).Collection("repertoires").Find(ctx, notDeleted(filter))
 	if err != nil {
-		log.Print(fmt.Errorf("could not get repertoires for period  %s - %s [%s]: %w", startDate, endDate, movieId, err))
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not get repertoires for period  %s - %s [%s]", startDate, endDate, movieId)
 		return nil, err
 	}
 
 	if err = cur.All(ctx, &repertoires); err != nil {
-		log.Print(fmt.Errorf("could marshall the repertoires results: %w", err))
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could marshall the repertoires results")
 		return nil, err
 	}
 
@@ -132,11 +157,16 @@ func (r *MongoStore) UpdateRepertoire(ctx context.Context, id string, repertoire
 	if err != nil {
 		return &models.Repertoire{}, err
 	}
-	res, err := r.db.Collection("repertoires").UpdateOne(ctx, bson.M{"_id": objID}, bson.D{
+	if _, err := r.resolveHall(ctx, &repertoire); err != nil {
+		return &models.Repertoire{}, err
+	}
+	res, err := r.db(ctx).Collection("repertoires").UpdateOne(ctx, notDeleted(bson.M{"_id": objID}), bson.D{
 		{"$set", bson.D{
 			{"movieId", repertoire.MovieID},
 			{"date", repertoire.Date},
 			{"time", repertoire.Time},
+			{"cinemaId", repertoire.CinemaID},
+			{"hallId", repertoire.HallID},
 			{"hall", repertoire.Hall},
 			{"numOfTickets", repertoire.NumOfTickets},
 			{"numOfResTickets", repertoire.NumOfResTickets},
@@ -144,7 +174,7 @@ func (r *MongoStore) UpdateRepertoire(ctx context.Context, id string, repertoire
 		}},
 	})
 	if err != nil {
-		log.Print(fmt.Errorf("could not update repertoire with id [%s]: %w", id, err))
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not update repertoire with id [%s]", id)
 		return &models.Repertoire{}, err
 	}
 
@@ -156,42 +186,45 @@ func (r *MongoStore) UpdateRepertoire(ctx context.Context, id string, repertoire
 	return &repertoire, nil
 }
 
-// DeleteRepertoire deletes a repertoire based on its ID
-func (r *MongoStore) DeleteRepertoire(ctx context.Context, id string) error {
-	objID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
-
-	res, err := r.db.Collection("repertoires").DeleteOne(ctx, bson.M{"_id": objID})
-	if err != nil {
-		log.Print(fmt.Errorf("error deleting the repertoire with id [%s]: %w", id, err))
-		return err
-	}
-
-	if res.DeletedCount == 0 {
-		return ErrRepertoireNotFound
-	}
-
-	return nil
+// DeleteRepertoire soft deletes a repertoire based on its ID. Its upcoming
+// reservations are dependents, see deleteWithDependents.
+func (r *MongoStore) DeleteRepertoire(ctx context.Context, id string, deletedBy string, cascade bool) (Dependents, error) {
+	return r.deleteWithDependents(ctx, "repertoires", id, deletedBy, cascade, ErrRepertoireNotFound,
+		func(ctx context.Context) ([]models.Repertoire, []primitive.ObjectID, error) {
+			repertoire, err := r.GetRepertoire(ctx, id)
+			if err != nil {
+				return nil, nil, err
+			}
+			return nil, []primitive.ObjectID{repertoire.ID}, nil
+		})
 }
 
-// DeleteRepertoire deletes a repertoire based on its ID
-func (r *MongoStore) DeleteRepertoireForMovie(ctx context.Context, movieId string) error {
+// DeleteRepertoireForMovie soft deletes all repertoires of a movie. The
+// upcoming reservations of its repertoires are dependents, see
+// deleteWithDependents.
+func (r *MongoStore) DeleteRepertoireForMovie(ctx context.Context, movieId string, deletedBy string, cascade bool) (Dependents, error) {
 	movieID, err := primitive.ObjectIDFromHex(movieId)
 	if err != nil {
-		return err
+		return Dependents{}, err
 	}
 
-	res, err := r.db.Collection("repertoires").DeleteMany(ctx, bson.M{"movieId": movieID})
-	if err != nil {
-		log.Print(fmt.Errorf("error deleting the repertoire with id [%s]: %w", movieId, err))
-		return err
-	}
-
-	if res.DeletedCount == 0 {
-		return ErrRepertoireNotFound
-	}
-
-	return nil
+	return r.removeWithDependents(ctx, deletedBy, cascade,
+		func(ctx context.Context) ([]models.Repertoire, []primitive.ObjectID, error) {
+			_, screenings, err := r.scheduledRepertoires(ctx, bson.M{"movieId": movieID})
+			return nil, screenings, err
+		},
+		func(ctx context.Context) error {
+			res, err := r.db(ctx).Collection("repertoires").UpdateMany(ctx, notDeleted(bson.M{"movieId": movieID}), bson.M{
+				"$set": bson.M{"deleted_at": time.Now().UTC(), "deleted_by": deletedBy},
+			})
+			if err != nil {
+				zerolog.Ctx(ctx).Error().Err(err).Msgf("error deleting the repertoire with id [%s]", movieId)
+				return err
+			}
+
+			if res.MatchedCount == 0 {
+				return ErrRepertoireNotFound
+			}
+			return nil
+		})
 }
@@ -26,7 +26,6 @@ func createRandomRepertoire(t *testing.T) *models.Repertoire {
 		Date:            dateValue,
 		Time:            "10:00",
 		Hall:            hall.Name,
-		NumOfTickets:    50,
 		NumOfResTickets: 0,
 	}
 
@@ -40,7 +39,8 @@ func createRandomRepertoire(t *testing.T) *models.Repertoire {
 	require.Equal(t, arg.Date, repertoire.Date)
 	require.Equal(t, arg.Time, repertoire.Time)
 	require.Equal(t, arg.Hall, repertoire.Hall)
-	require.Equal(t, arg.NumOfTickets, repertoire.NumOfTickets)
+	require.Equal(t, len(hall.SellableSeats()), repertoire.NumOfTickets)
+	require.Equal(t, hall.ID, repertoire.HallID)
 	require.Equal(t, arg.NumOfResTickets, repertoire.NumOfResTickets)
 
 	require.NotZero(t, repertoire.ID)
@@ -63,7 +63,6 @@ func createRandomRepertoireForMovie(t *testing.T, movieId primitive.ObjectID) *m
 		Date:            dateValue,
 		Time:            "10:00",
 		Hall:            hall.Name,
-		NumOfTickets:    50,
 		NumOfResTickets: 0,
 	}
 
@@ -77,7 +76,8 @@ func createRandomRepertoireForMovie(t *testing.T, movieId primitive.ObjectID) *m
 	require.Equal(t, arg.Date, repertoire.Date)
 	require.Equal(t, arg.Time, repertoire.Time)
 	require.Equal(t, arg.Hall, repertoire.Hall)
-	require.Equal(t, arg.NumOfTickets, repertoire.NumOfTickets)
+	require.Equal(t, len(hall.SellableSeats()), repertoire.NumOfTickets)
+	require.Equal(t, hall.ID, repertoire.HallID)
 	require.Equal(t, arg.NumOfResTickets, repertoire.NumOfResTickets)
 
 	require.NotZero(t, repertoire.ID)
@@ -143,17 +143,17 @@ func TestGetRepertoireByMovieDateTimeHall(t *testing.T) {
 func TestGetAllRepertoireForMovie(t *testing.T) {
 	movie := createRandomMovie(t)
 	repertoire1 := createRandomRepertoireForMovie(t, movie.ID)
-	repertoires, err := testStore.GetAllRepertoireForMovie(context.Background(), movie.ID.Hex(), repertoire1.Date, repertoire1.Date)
+	repertoires, err := testStore.GetAllRepertoireForMovie(context.Background(), movie.ID.Hex(), repertoire1.Date, repertoire1.Date, "")
 	require.NoError(t, err)
 
 	repertoire11 := createRandomRepertoireForMovie(t, movie.ID)
 	var repertoires2 []models.Repertoire
 	if repertoire1.Date.Before(repertoire11.Date) {
-		repertoires2, err = testStore.GetAllRepertoireForMovie(context.Background(), movie.ID.Hex(), repertoire1.Date, repertoire11.Date)
+		repertoires2, err = testStore.GetAllRepertoireForMovie(context.Background(), movie.ID.Hex(), repertoire1.Date, repertoire11.Date, "")
 	} else {
-		repertoires2, err = testStore.GetAllRepertoireForMovie(context.Background(), movie.ID.Hex(), repertoire11.Date, repertoire1.Date)
+		repertoires2, err = testStore.GetAllRepertoireForMovie(context.Background(), movie.ID.Hex(), repertoire11.Date, repertoire1.Date, "")
 	}
-	repertoires2, err = testStore.GetAllRepertoireForMovie(context.Background(), movie.ID.Hex(), repertoire1.Date, repertoire1.Date)
+	repertoires2, err = testStore.GetAllRepertoireForMovie(context.Background(), movie.ID.Hex(), repertoire1.Date, repertoire1.Date, "")
 	require.NoError(t, err)
 	require.NotEmpty(t, repertoires2)
 
@@ -195,7 +195,7 @@ func TestUpdateRepertoire(t *testing.T) {
 
 func TestDeleteRepertoire(t *testing.T) {
 	repertoire1 := createRandomRepertoire(t)
-	err := testStore.DeleteRepertoire(context.Background(), repertoire1.ID.Hex())
+	_, err := testStore.DeleteRepertoire(context.Background(), repertoire1.ID.Hex(), util.RandomOwner(), false)
 	require.NoError(t, err)
 
 	repertoire2, err := testStore.GetRepertoire(context.Background(), repertoire1.ID.Hex())
@@ -207,7 +207,7 @@ func TestDeleteRepertoire(t *testing.T) {
 
 func TestDeleteRepertoireForMovie(t *testing.T) {
 	repertoire1 := createRandomRepertoire(t)
-	err := testStore.DeleteRepertoireForMovie(context.Background(), repertoire1.MovieID.Hex())
+	_, err := testStore.DeleteRepertoireForMovie(context.Background(), repertoire1.MovieID.Hex(), util.RandomOwner(), false)
 	require.NoError(t, err)
 
 	repertoire2, err := testStore.GetRepertoire(context.Background(), repertoire1.ID.Hex())
@@ -216,3 +216,19 @@ func TestDeleteRepertoireForMovie(t *testing.T) {
 	require.EqualError(t, err, ErrRepertoireNotFound.Error())
 	require.Empty(t, repertoire2)
 }
+
+func TestAddRepertoireTooManyTickets(t *testing.T) {
+	movie := createRandomMovie(t)
+	hall := createRandomHall(t)
+
+	arg := models.Repertoire{
+		MovieID:      movie.ID,
+		Date:         time.Now().UTC().Truncate(24 * time.Hour),
+		Time:         "10:00",
+		HallID:       hall.ID,
+		NumOfTickets: len(hall.SellableSeats()) + 1,
+	}
+
+	_, err := testStore.AddRepertoire(context.Background(), &arg)
+	require.ErrorIs(t, err, ErrTooManyTickets)
+}
@@ -8,37 +8,74 @@ import (
 )
 
 type Store interface {
+	Ping(ctx context.Context) error
+	EnsureIndexes(ctx context.Context) error
+	RunMigrations(ctx context.Context) error
+	PendingMigrations(ctx context.Context) ([]string, error)
+
 	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
 	InsertUser(ctx context.Context, user *models.User) (*models.User, error)
 
+	AddCinema(ctx context.Context, cinema *models.Cinema) (*models.Cinema, error)
+	ListCinemas(ctx context.Context) ([]models.Cinema, error)
+	GetCinema(ctx context.Context, id string) (*models.Cinema, error)
+	UpdateCinema(ctx context.Context, id string, cinema models.Cinema) (*models.Cinema, error)
+	DeleteCinema(ctx context.Context, id string, deletedBy string) error
+	SetCinemaAdmin(ctx context.Context, cinemaId string, username string, admin bool) error
+
 	InsertHall(ctx context.Context, hall *models.Hall) (*models.Hall, error)
 	ListHalls(ctx context.Context) ([]models.Hall, error)
 	GetHall(ctx context.Context, name string) ([]models.Hall, error)
 	GetHallById(ctx context.Context, id string) (*models.Hall, error)
 	UpdateHall(ctx context.Context, id string, hall models.Hall) (models.Hall, error)
-	DeleteHall(ctx context.Context, id string) error
+	DeleteHall(ctx context.Context, id string, deletedBy string, cascade bool) (Dependents, error)
+	RestoreHall(ctx context.Context, id string) error
 
 	AddMovie(ctx context.Context, movie *models.Movie) (*models.Movie, error)
 	ListMovies(ctx context.Context) ([]models.Movie, error)
 	GetMovie(ctx context.Context, id string) (*models.Movie, error)
 	UpdateMovie(ctx context.Context, id string, movie *models.Movie) (*models.Movie, error)
-	DeleteMovie(ctx context.Context, id string) error
+	DeleteMovie(ctx context.Context, id string, deletedBy string, cascade bool) (Dependents, error)
+	RestoreMovie(ctx context.Context, id string) error
+	UpdateMoviePoster(ctx context.Context, id string, poster string, sizes map[string]string) error
 	SearchMovies(ctx context.Context, movieId string) ([]models.Movie, error)
+	SearchMoviesText(ctx context.Context, arg SearchMoviesParams) ([]models.Movie, error)
+	ListNowShowing(ctx context.Context, from time.Time, to time.Time) ([]models.MovieSchedule, error)
+	ListComingSoon(ctx context.Context, after time.Time) ([]models.MovieSchedule, error)
+	ListMoviesByGenre(ctx context.Context, genre string) ([]models.Movie, error)
+	ListMoviesByPerson(ctx context.Context, name string, role string) ([]models.Movie, error)
 
 	AddRepertoire(ctx context.Context, repertoire *models.Repertoire) (*models.Repertoire, error)
 	ListRepertoires(ctx context.Context) ([]models.Repertoire, error)
 	GetRepertoire(ctx context.Context, id string) (*models.Repertoire, error)
-	GetRepertoireByMovieDateTimeHall(ctx context.Context, movieId string, dateValue time.Time, timeValue string, hallValue string) (models.Repertoire, error)
-	GetAllRepertoireForMovie(ctx context.Context, movieId string, startDate time.Time, endDate time.Time) ([]models.Repertoire, error)
+	GetRepertoireByMovieDateTimeHall(ctx context.Context, movieId string, dateValue time.Time, timeValue string, hallId string) (models.Repertoire, error)
+	GetAllRepertoireForMovie(ctx context.Context, movieId string, startDate time.Time, endDate time.Time, cinemaId string) ([]models.Repertoire, error)
 	UpdateRepertoire(ctx context.Context, id string, repertoire models.Repertoire) (*models.Repertoire, error)
-	DeleteRepertoire(ctx context.Context, id string) error
-	DeleteRepertoireForMovie(ctx context.Context, movieId string) error
+	DeleteRepertoire(ctx context.Context, id string, deletedBy string, cascade bool) (Dependents, error)
+	DeleteRepertoireForMovie(ctx context.Context, movieId string, deletedBy string, cascade bool) (Dependents, error)
+	RestoreRepertoire(ctx context.Context, id string) error
+	BlockSeats(ctx context.Context, repertoireId string, seats []string, reason string, blockedBy string) (*models.Repertoire, error)
+	UnblockSeats(ctx context.Context, repertoireId string, seats []string) (*models.Repertoire, error)
+	FindBestSeats(ctx context.Context, repertoireId string, party int, username string, holdUntil time.Time) (models.SeatProposal, error)
+	GetProgram(ctx context.Context, date time.Time, cinemaId string) ([]models.ProgramHall, error)
 
 	InsertReservation(ctx context.Context, reservation *models.Reservation) (*models.Reservation, error)
 	GetReservationById(ctx context.Context, id string) (*models.Reservation, error)
 	GetAllReservationsForUser(ctx context.Context, username string) ([]models.Reservation, error)
 	DeleteReservation(ctx context.Context, id string) error
+	CheckInReservation(ctx context.Context, id string) error
+
+	ListNotifications(ctx context.Context, username string) ([]models.Notification, error)
 
 	AddReservation(ctx context.Context, req AddReservationParams) (*models.Reservation, error)
 	CancelReservation(ctx context.Context, resId string) error
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+
+	AddReview(ctx context.Context, review *models.Review) (*models.Review, error)
+	ListReviewsForMovie(ctx context.Context, movieId string) ([]models.Review, error)
+	ListReviews(ctx context.Context, status string) ([]models.Review, error)
+	SetReviewStatus(ctx context.Context, id string, status string, moderator string) (*models.Review, error)
+	DeleteReview(ctx context.Context, id string) error
+
+	PurgeDeleted(ctx context.Context, before time.Time) (PurgeResult, error)
 }
@@ -3,10 +3,9 @@ package repository
 import (
 	"context"
 	"errors"
-	"fmt"
-	"log"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/tijanadmi/movieginmongoapi/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -21,9 +20,9 @@ var (
 func (r *MongoStore) InsertReservation(ctx context.Context, reservation *models.Reservation) (*models.Reservation, error) {
 	reservation.ID = primitive.NewObjectID()
 	reservation.CreationDate = time.Now()
-	result, err := r.db.Collection("reservations").InsertOne(ctx, reservation)
+	result, err := r.db(ctx).Collection("reservations").InsertOne(ctx, reservation)
 	if err != nil {
-		log.Print(fmt.Errorf("could not add new reservation: %w", err))
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not add new reservation")
 		return nil, err
 	}
 	reservation.ID = result.InsertedID.(primitive.ObjectID)
@@ -39,7 +38,7 @@ func (r *MongoStore) GetReservationById(ctx context.Context, id string) (*models
 	}
 
 	var reservation models.Reservation
-	result := r.db.Collection("reservations").FindOne(ctx, bson.M{"_id": objID})
+	result := r.db(ctx).Collection("reservations").FindOne(ctx, bson.M{"_id": objID})
 	err = result.Decode(&reservation)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
@@ -55,14 +54,14 @@ func (r *MongoStore) GetReservationById(ctx context.Context, id string) (*models
 func (r *MongoStore) GetAllReservationsForUser(ctx context.Context, username string) ([]models.Reservation, error) {
 	reservations := make([]models.Reservation, 0)
 
-	cur, err := r.db.Collection("reservations").Find(ctx, bson.M{"username": username})
+	cur, err := r.db(ctx).Collection("reservations").Find(ctx, bson.M{"username": username})
 	if err != nil {
-		log.Print(fmt.Errorf("could not get all reservations [%s]: %w", username, err))
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not get all reservations [%s]", username)
 		return nil, err
 	}
 
 	if err = cur.All(ctx, &reservations); err != nil {
-		log.Print(fmt.Errorf("could marshall the repertoires results: %w", err))
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could marshall the repertoires results")
 		return nil, err
 	}
 
@@ -75,9 +74,9 @@ func (r *MongoStore) DeleteReservation(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
-	res, err := r.db.Collection("reservations").DeleteOne(ctx, bson.M{"_id": objID})
+	res, err := r.db(ctx).Collection("reservations").DeleteOne(ctx, bson.M{"_id": objID})
 	if err != nil {
-		log.Print(fmt.Errorf("error deleting the repertoire with id [%s]: %w", id, err))
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("error deleting the repertoire with id [%s]", id)
 		return err
 	}
 	if res.DeletedCount == 0 {
@@ -86,3 +85,23 @@ func (r *MongoStore) DeleteReservation(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// CheckInReservation marks a reservation as checked in at the cinema
+func (r *MongoStore) CheckInReservation(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.db(ctx).Collection("reservations").UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": bson.M{"checkedIn": true}})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not check in reservation with id [%s]", id)
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrReservationNotFound
+	}
+
+	return nil
+}
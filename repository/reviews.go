@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrReviewNotFound   = errors.New("review not found")
+	ErrReviewExists     = errors.New("movie has already been reviewed by the user")
+	ErrReviewNotAllowed = errors.New("only users who have seen the movie can review it")
+)
+
+// AddReview adds a review of a movie. The user must have a reservation for the
+// movie that was checked in or whose screening has already passed.
+func (r *MongoStore) AddReview(ctx context.Context, review *models.Review) (*models.Review, error) {
+	seen, err := r.db(ctx).Collection("reservations").CountDocuments(ctx, bson.M{
+		"userId":  review.UserID,
+		"movieId": review.MovieID,
+		"$or": bson.A{
+			bson.M{"checkedIn": true},
+			bson.M{"date": bson.M{"$lt": time.Now().UTC().Truncate(24 * time.Hour)}},
+		},
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not check reservations for review")
+		return nil, err
+	}
+	if seen == 0 {
+		return nil, ErrReviewNotAllowed
+	}
+
+	review.ID = primitive.NewObjectID()
+	review.CreatedAt = time.Now()
+	review.Status = models.ReviewStatusPublished
+	_, err = r.db(ctx).Collection("reviews").InsertOne(ctx, review)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrReviewExists
+		}
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not add new review")
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// ListReviewsForMovie returns the published reviews of a movie, newest first
+func (r *MongoStore) ListReviewsForMovie(ctx context.Context, movieId string) ([]models.Review, error) {
+	movieID, err := primitive.ObjectIDFromHex(movieId)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.findReviews(ctx, bson.M{"movieId": movieID, "status": models.ReviewStatusPublished})
+}
+
+// ListReviews returns all reviews with the given status (all reviews when empty), newest first
+func (r *MongoStore) ListReviews(ctx context.Context, status string) ([]models.Review, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+
+	return r.findReviews(ctx, filter)
+}
+
+// SetReviewStatus publishes or hides a review, recording the moderator
+func (r *MongoStore) SetReviewStatus(ctx context.Context, id string, status string, moderator string) (*models.Review, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var review models.Review
+	err = r.db(ctx).Collection("reviews").FindOneAndUpdate(ctx, bson.M{"_id": objID}, bson.M{
+		"$set": bson.M{
+			"status":      status,
+			"moderatedBy": moderator,
+			"moderatedAt": time.Now(),
+		},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&review)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrReviewNotFound
+		}
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not moderate review with id [%s]", id)
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+// DeleteReview deletes a review based on its ID
+func (r *MongoStore) DeleteReview(ctx context.Context, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.db(ctx).Collection("reviews").DeleteOne(ctx, bson.M{"_id": objID})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("error deleting the review with id [%s]", id)
+		return err
+	}
+
+	if res.DeletedCount == 0 {
+		return ErrReviewNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoStore) findReviews(ctx context.Context, filter bson.M) ([]models.Review, error) {
+	reviews := make([]models.Review, 0)
+	cur, err := r.db(ctx).Collection("reviews").Find(ctx, filter, options.Find().SetSort(bson.M{"creation_date": -1}))
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not get reviews")
+		return nil, err
+	}
+
+	if err = cur.All(ctx, &reviews); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not marshall the reviews results")
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// ratingStages adds the average of the published ratings (avgRating) and
+// their count (numOfRatings) to each movie of an aggregation.
+func ratingStages() []bson.D {
+	return []bson.D{
+		{{Key: "$lookup", Value: bson.M{
+			"from": "reviews",
+			"let":  bson.M{"movieId": "$_id"},
+			"pipeline": []bson.M{
+				{"$match": bson.M{"$expr": bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$movieId", "$$movieId"}},
+					bson.M{"$eq": bson.A{"$status", models.ReviewStatusPublished}},
+				}}}},
+				{"$group": bson.M{
+					"_id":   nil,
+					"avg":   bson.M{"$avg": "$rating"},
+					"count": bson.M{"$sum": 1},
+				}},
+			},
+			"as": "ratings",
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"avgRating":    bson.M{"$ifNull": bson.A{bson.M{"$first": "$ratings.avg"}, 0}},
+			"numOfRatings": bson.M{"$ifNull": bson.A{bson.M{"$first": "$ratings.count"}, 0}},
+		}}},
+		{{Key: "$unset", Value: "ratings"}},
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/util"
+)
+
+func TestAddReview(t *testing.T) {
+	require.NoError(t, testStore.EnsureIndexes(context.Background()))
+
+	user := createRandomUser(t)
+	movie := createRandomMovie(t)
+
+	arg := &models.Review{
+		MovieID:  movie.ID,
+		UserID:   user.ID,
+		Username: user.Username,
+		Rating:   4,
+		Comment:  util.RandomString(30),
+	}
+
+	// no reservation for the movie yet
+	_, err := testStore.AddReview(context.Background(), arg)
+	require.ErrorIs(t, err, ErrReviewNotAllowed)
+
+	_, err = testStore.InsertReservation(context.Background(), &models.Reservation{
+		Username:   user.Username,
+		UserID:     user.ID,
+		MovieID:    movie.ID,
+		MovieTitle: movie.Title,
+		Date:       time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1),
+		Time:       "20:00",
+	})
+	require.NoError(t, err)
+
+	review, err := testStore.AddReview(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, review.ID)
+	require.Equal(t, models.ReviewStatusPublished, review.Status)
+
+	_, err = testStore.AddReview(context.Background(), &models.Review{MovieID: movie.ID, UserID: user.ID, Rating: 1})
+	require.ErrorIs(t, err, ErrReviewExists)
+
+	movies, err := testStore.SearchMovies(context.Background(), movie.ID.Hex())
+	require.NoError(t, err)
+	require.Len(t, movies, 1)
+	require.Equal(t, 4.0, movies[0].AvgRating)
+	require.Equal(t, 1, movies[0].NumOfRatings)
+
+	// listings carry the ratings as well
+	movies, err = testStore.ListMoviesByGenre(context.Background(), movie.Genre[0])
+	require.NoError(t, err)
+	require.Len(t, movies, 1)
+	require.Equal(t, 4.0, movies[0].AvgRating)
+	require.Equal(t, 1, movies[0].NumOfRatings)
+
+	// hidden reviews are not counted
+	_, err = testStore.SetReviewStatus(context.Background(), review.ID.Hex(), models.ReviewStatusHidden, util.RandomOwner())
+	require.NoError(t, err)
+
+	movies, err = testStore.SearchMovies(context.Background(), movie.ID.Hex())
+	require.NoError(t, err)
+	require.Zero(t, movies[0].NumOfRatings)
+
+	reviews, err := testStore.ListReviewsForMovie(context.Background(), movie.ID.Hex())
+	require.NoError(t, err)
+	require.Empty(t, reviews)
+
+	require.NoError(t, testStore.DeleteReview(context.Background(), review.ID.Hex()))
+	require.ErrorIs(t, testStore.DeleteReview(context.Background(), review.ID.Hex()), ErrReviewNotFound)
+}
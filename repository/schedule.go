@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// scheduleLookupStage joins the repertoires of a movie between from and to
+// (to is ignored when zero), grouped by date and then by hall.
+func scheduleLookupStage(from time.Time, to time.Time) bson.M {
+	dateFilter := bson.A{
+		bson.M{"$eq": bson.A{"$movieId", "$$movieId"}},
+		bson.M{"$gte": bson.A{"$date", from}},
+	}
+	if !to.IsZero() {
+		dateFilter = append(dateFilter, bson.M{"$lte": bson.A{"$date", to}})
+	}
+
+	return bson.M{"$lookup": bson.M{
+		"from": "repertoires",
+		"let":  bson.M{"movieId": "$_id"},
+		"pipeline": []bson.M{
+			{"$match": notDeleted(bson.M{"$expr": bson.M{"$and": dateFilter}})},
+			{"$sort": bson.D{{Key: "date", Value: 1}, {Key: "time", Value: 1}}},
+			{"$group": bson.M{
+				"_id":   bson.M{"date": "$date", "hallId": "$hallId"},
+				"hall":  bson.M{"$first": "$hall"},
+				"times": bson.M{"$push": "$time"},
+			}},
+			{"$sort": bson.D{{Key: "_id.date", Value: 1}, {Key: "hall", Value: 1}}},
+			{"$group": bson.M{
+				"_id":   "$_id.date",
+				"halls": bson.M{"$push": bson.M{"hallId": "$_id.hallId", "hall": "$hall", "times": "$times"}},
+			}},
+			{"$sort": bson.M{"_id": 1}},
+			{"$project": bson.M{"_id": 0, "date": "$_id", "halls": 1}},
+		},
+		"as": "schedule",
+	}}
+}
+
+// ListNowShowing returns the movies with screenings between from and to, with their showtimes
+func (r *MongoStore) ListNowShowing(ctx context.Context, from time.Time, to time.Time) ([]models.MovieSchedule, error) {
+	pipeline := []bson.M{
+		{"$match": notDeleted(bson.M{})},
+		scheduleLookupStage(from, to),
+		{"$match": bson.M{"schedule.0": bson.M{"$exists": true}}},
+		{"$project": bson.M{"screenings": 0}},
+		{"$sort": bson.M{"title": 1}},
+	}
+
+	return r.aggregateSchedules(ctx, pipeline)
+}
+
+// ListComingSoon returns the movies whose premiere is after the given date,
+// together with any showtimes already scheduled from that date on.
+func (r *MongoStore) ListComingSoon(ctx context.Context, after time.Time) ([]models.MovieSchedule, error) {
+	pipeline := []bson.M{
+		{"$match": notDeleted(bson.M{"screening": bson.M{"$gt": after}})},
+		scheduleLookupStage(after, time.Time{}),
+		{"$project": bson.M{"screenings": 0}},
+		{"$sort": bson.D{{Key: "screening", Value: 1}, {Key: "title", Value: 1}}},
+	}
+
+	return r.aggregateSchedules(ctx, pipeline)
+}
+
+func (r *MongoStore) aggregateSchedules(ctx context.Context, pipeline []bson.M) ([]models.MovieSchedule, error) {
+	movies := make([]models.MovieSchedule, 0)
+
+	cursor, err := r.db(ctx).Collection("movies").Aggregate(ctx, pipeline)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not aggregate movie schedules")
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &movies); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not unmarshal the movie schedules results")
+		return nil, err
+	}
+
+	return movies, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/util"
+)
+
+func TestListNowShowing(t *testing.T) {
+	movie := createRandomMovie(t)
+	repertoire := createRandomRepertoireForMovie(t, movie.ID)
+
+	movies, err := testStore.ListNowShowing(context.Background(), repertoire.Date, repertoire.Date)
+	require.NoError(t, err)
+	require.NotEmpty(t, movies)
+
+	var found *models.MovieSchedule
+	for i := range movies {
+		if movies[i].ID == movie.ID {
+			found = &movies[i]
+		}
+	}
+	require.NotNil(t, found)
+	require.Len(t, found.Schedule, 1)
+	require.WithinDuration(t, repertoire.Date, found.Schedule[0].Date, time.Second)
+	require.Equal(t, repertoire.Hall, found.Schedule[0].Halls[0].Hall)
+	require.Equal(t, []string{repertoire.Time}, found.Schedule[0].Halls[0].Times)
+
+	// screenings from the past are not listed
+	movies, err = testStore.ListNowShowing(context.Background(), repertoire.Date.AddDate(0, 0, 1), repertoire.Date.AddDate(0, 0, 7))
+	require.NoError(t, err)
+	for _, m := range movies {
+		require.NotEqual(t, movie.ID, m.ID)
+	}
+}
+
+func TestListComingSoon(t *testing.T) {
+	arg := models.Movie{
+		Title:     util.RandomString(50),
+		Duration:  int32(util.RandomInt(100, 250)),
+		Screening: time.Now().AddDate(0, 1, 0),
+	}
+	movie, err := testStore.AddMovie(context.Background(), &arg)
+	require.NoError(t, err)
+
+	movies, err := testStore.ListComingSoon(context.Background(), time.Now())
+	require.NoError(t, err)
+
+	found := false
+	for _, m := range movies {
+		require.True(t, m.Screening.After(time.Now()))
+		if m.ID == movie.ID {
+			found = true
+		}
+	}
+	require.True(t, found)
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+var (
+	ErrUnknownSeat     = errors.New("seat is not sellable in this hall")
+	ErrSeatReserved    = errors.New("seat is already reserved")
+	ErrSeatBlocked     = errors.New("seat is blocked")
+	ErrSeatHeld        = errors.New("seat is held for another user")
+	ErrNoAdjacentSeats = errors.New("no adjacent seats available for the party")
+)
+
+// BlockSeats takes seats of a repertoire out of sale with the given reason.
+// Only sellable seats of the hall that are not reserved can be blocked; the
+// reason of an already blocked seat is replaced.
+func (r *MongoStore) BlockSeats(ctx context.Context, repertoireId string, seats []string, reason string, blockedBy string) (*models.Repertoire, error) {
+	wc := writeconcern.Majority()
+	txnOptions := options.Transaction().SetWriteConcern(wc)
+
+	session, err := r.db(ctx).Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	result, err := session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		repertoire, err := r.GetRepertoire(sessionCtx, repertoireId)
+		if err != nil {
+			return nil, err
+		}
+		hall, err := r.GetHallById(sessionCtx, repertoire.HallID.Hex())
+		if err != nil {
+			return nil, err
+		}
+
+		sellable := make(map[string]bool)
+		for _, label := range hall.SellableSeats() {
+			sellable[label] = true
+		}
+		reserved := make(map[string]bool, len(repertoire.ReservSeats))
+		for _, label := range repertoire.ReservSeats {
+			reserved[label] = true
+		}
+
+		requested := make(map[string]bool, len(seats))
+		for _, seat := range seats {
+			if !sellable[seat] {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownSeat, seat)
+			}
+			if reserved[seat] {
+				return nil, fmt.Errorf("%w: %s", ErrSeatReserved, seat)
+			}
+			requested[seat] = true
+		}
+
+		blocked := make([]models.BlockedSeat, 0, len(repertoire.BlockedSeats)+len(requested))
+		for _, b := range repertoire.BlockedSeats {
+			if !requested[b.Seat] {
+				blocked = append(blocked, b)
+			}
+		}
+		now := time.Now().UTC()
+		for seat := range requested {
+			blocked = append(blocked, models.BlockedSeat{Seat: seat, Reason: reason, BlockedBy: blockedBy, BlockedAt: now})
+		}
+		sort.Slice(blocked, func(i, j int) bool { return blocked[i].Seat < blocked[j].Seat })
+
+		_, err = r.db(ctx).Collection("repertoires").UpdateOne(sessionCtx, notDeleted(bson.M{"_id": repertoire.ID}), bson.M{
+			"$set": bson.M{"blockedSeats": blocked},
+		})
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msgf("could not block seats of repertoire with id [%s]", repertoireId)
+			return nil, err
+		}
+
+		repertoire.BlockedSeats = blocked
+		return repertoire, nil
+	}, txnOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*models.Repertoire), nil
+}
+
+// UnblockSeats puts blocked seats of a repertoire back on sale. Seats that
+// are not blocked are ignored.
+func (r *MongoStore) UnblockSeats(ctx context.Context, repertoireId string, seats []string) (*models.Repertoire, error) {
+	objID, err := primitive.ObjectIDFromHex(repertoireId)
+	if err != nil {
+		return nil, err
+	}
+
+	var repertoire models.Repertoire
+	err = r.db(ctx).Collection("repertoires").FindOneAndUpdate(ctx, notDeleted(bson.M{"_id": objID}), bson.M{
+		"$pull": bson.M{"blockedSeats": bson.M{"seat": bson.M{"$in": seats}}},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&repertoire)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrRepertoireNotFound
+		}
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not unblock seats of repertoire with id [%s]", repertoireId)
+		return nil, err
+	}
+
+	return &repertoire, nil
+}
+
+// FindBestSeats proposes party adjacent seats of a repertoire for username,
+// see models.BestAvailableSeats. When holdUntil is not zero the seats are
+// held for the user until then, replacing the user's earlier holds.
+func (r *MongoStore) FindBestSeats(ctx context.Context, repertoireId string, party int, username string, holdUntil time.Time) (models.SeatProposal, error) {
+	wc := writeconcern.Majority()
+	txnOptions := options.Transaction().SetWriteConcern(wc)
+
+	session, err := r.db(ctx).Client().StartSession()
+	if err != nil {
+		return models.SeatProposal{}, err
+	}
+	defer session.EndSession(ctx)
+
+	result, err := session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		repertoire, err := r.GetRepertoire(sessionCtx, repertoireId)
+		if err != nil {
+			return nil, err
+		}
+		hall, err := r.GetHallById(sessionCtx, repertoire.HallID.Hex())
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		unavailable := repertoire.UnavailableSeats(username, now)
+		if repertoire.AvailableTickets()-len(repertoire.HeldByOthers(username, now)) < party {
+			return nil, ErrNotEnoughTickets
+		}
+
+		seats := models.BestAvailableSeats(hall.Layout(), unavailable, party)
+		if seats == nil {
+			return nil, ErrNoAdjacentSeats
+		}
+
+		proposal := models.SeatProposal{RepertoireID: repertoire.ID, Seats: seats}
+		if holdUntil.IsZero() {
+			return proposal, nil
+		}
+
+		holds := make([]models.SeatHold, 0, len(repertoire.HeldSeats)+len(seats))
+		for _, hold := range repertoire.HeldSeats {
+			if hold.Username != username && hold.ExpiresAt.After(now) {
+				holds = append(holds, hold)
+			}
+		}
+		for _, seat := range seats {
+			holds = append(holds, models.SeatHold{Seat: seat, Username: username, ExpiresAt: holdUntil})
+		}
+
+		_, err = r.db(ctx).Collection("repertoires").UpdateOne(sessionCtx, notDeleted(bson.M{"_id": repertoire.ID}), bson.M{
+			"$set": bson.M{"heldSeats": holds},
+		})
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msgf("could not hold seats of repertoire with id [%s]", repertoireId)
+			return nil, err
+		}
+
+		proposal.HeldUntil = &holdUntil
+		return proposal, nil
+	}, txnOptions)
+	if err != nil {
+		return models.SeatProposal{}, err
+	}
+
+	return result.(models.SeatProposal), nil
+}
+
+// releaseHolds drops the seats held for username on a repertoire
+func (r *MongoStore) releaseHolds(ctx context.Context, repertoireID primitive.ObjectID, username string) error {
+	_, err := r.db(ctx).Collection("repertoires").UpdateOne(ctx, bson.M{"_id": repertoireID}, bson.M{
+		"$pull": bson.M{"heldSeats": bson.M{"username": username}},
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("could not release the holds of %s on repertoire with id [%s]", username, repertoireID.Hex())
+	}
+	return err
+}
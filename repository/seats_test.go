@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockAndUnblockSeats(t *testing.T) {
+	user := createRandomUser(t)
+	repertoire := createRandomRepertoire(t)
+	admin := createRandomUser(t)
+
+	blocked, err := testStore.BlockSeats(context.Background(), repertoire.ID.Hex(), []string{"C3", "A1"}, "broken", admin.Username)
+	require.NoError(t, err)
+	require.Len(t, blocked.BlockedSeats, 2)
+	require.Equal(t, "A1", blocked.BlockedSeats[0].Seat)
+	require.Equal(t, "broken", blocked.BlockedSeats[0].Reason)
+	require.Equal(t, admin.Username, blocked.BlockedSeats[0].BlockedBy)
+	require.Equal(t, repertoire.NumOfTickets-2, blocked.AvailableTickets())
+
+	// blocking again replaces the reason
+	blocked, err = testStore.BlockSeats(context.Background(), repertoire.ID.Hex(), []string{"A1"}, "distancing", admin.Username)
+	require.NoError(t, err)
+	require.Len(t, blocked.BlockedSeats, 2)
+	require.Equal(t, "distancing", blocked.BlockedSeats[0].Reason)
+
+	_, err = testStore.BlockSeats(context.Background(), repertoire.ID.Hex(), []string{"Z9"}, "broken", admin.Username)
+	require.ErrorIs(t, err, ErrUnknownSeat)
+
+	arg := AddReservationParams{
+		Username:    user.Username,
+		MovieID:     repertoire.MovieID.Hex(),
+		Date:        repertoire.Date,
+		Time:        repertoire.Time,
+		HallID:      repertoire.HallID.Hex(),
+		ReservSeats: []string{"A1", "A2"},
+	}
+	_, err = testStore.AddReservation(context.Background(), arg)
+	require.ErrorIs(t, err, ErrSeatBlocked)
+
+	arg.ReservSeats = []string{"A2"}
+	_, err = testStore.AddReservation(context.Background(), arg)
+	require.NoError(t, err)
+
+	_, err = testStore.BlockSeats(context.Background(), repertoire.ID.Hex(), []string{"A2"}, "broken", admin.Username)
+	require.ErrorIs(t, err, ErrSeatReserved)
+
+	unblocked, err := testStore.UnblockSeats(context.Background(), repertoire.ID.Hex(), []string{"A1", "B1"})
+	require.NoError(t, err)
+	require.Len(t, unblocked.BlockedSeats, 1)
+	require.Equal(t, "C3", unblocked.BlockedSeats[0].Seat)
+}
+
+func TestFindBestSeatsWithHold(t *testing.T) {
+	repertoire := createRandomRepertoire(t)
+	first := createRandomUser(t)
+	second := createRandomUser(t)
+
+	// the hall of createRandomHall has 5 rows of 5 seats, so C2-C4 would
+	// leave C1 and C5 single
+	proposal, err := testStore.FindBestSeats(context.Background(), repertoire.ID.Hex(), 3, first.Username, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"C1", "C2", "C3"}, proposal.Seats)
+	require.Nil(t, proposal.HeldUntil)
+
+	holdUntil := time.Now().Add(time.Minute).UTC().Truncate(time.Millisecond)
+	proposal, err = testStore.FindBestSeats(context.Background(), repertoire.ID.Hex(), 3, first.Username, holdUntil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"C1", "C2", "C3"}, proposal.Seats)
+	require.Equal(t, holdUntil, *proposal.HeldUntil)
+
+	// the held seats are skipped for everyone else
+	proposal, err = testStore.FindBestSeats(context.Background(), repertoire.ID.Hex(), 3, second.Username, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"B1", "B2", "B3"}, proposal.Seats)
+
+	arg := AddReservationParams{
+		Username:    second.Username,
+		MovieID:     repertoire.MovieID.Hex(),
+		Date:        repertoire.Date,
+		Time:        repertoire.Time,
+		HallID:      repertoire.HallID.Hex(),
+		ReservSeats: []string{"C3"},
+	}
+	_, err = testStore.AddReservation(context.Background(), arg)
+	require.ErrorIs(t, err, ErrSeatHeld)
+
+	// booking the held seats uses up the hold
+	arg.Username = first.Username
+	arg.ReservSeats = []string{"C1", "C2", "C3"}
+	_, err = testStore.AddReservation(context.Background(), arg)
+	require.NoError(t, err)
+
+	updated, err := testStore.GetRepertoire(context.Background(), repertoire.ID.Hex())
+	require.NoError(t, err)
+	require.Empty(t, updated.HeldSeats)
+
+	_, err = testStore.FindBestSeats(context.Background(), repertoire.ID.Hex(), 6, second.Username, time.Time{})
+	require.ErrorIs(t, err, ErrNoAdjacentSeats)
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Movies, halls, repertoires and cinemas are soft deleted: deleting one only
+// sets deleted_at and deleted_by, and every normal query skips such documents.
+// Movies, halls and repertoires can be restored until PurgeDeleted removes
+// them for good.
+
+// notDeleted adds the condition excluding soft-deleted documents to filter.
+// A nil deleted_at matches documents without the field too.
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = nil
+	return filter
+}
+
+// softDelete marks a document as deleted; notFound is returned when there is
+// no such document or it is already deleted.
+func (r *MongoStore) softDelete(ctx context.Context, collection string, id string, deletedBy string, notFound error) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.db(ctx).Collection(collection).UpdateOne(ctx, notDeleted(bson.M{"_id": objID}), bson.M{
+		"$set": bson.M{"deleted_at": time.Now().UTC(), "deleted_by": deletedBy},
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("error deleting the document with id [%s] from %s", id, collection)
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return notFound
+	}
+
+	return nil
+}
+
+// restore clears the deletion mark of a soft-deleted document
+func (r *MongoStore) restore(ctx context.Context, collection string, id string, notFound error) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.db(ctx).Collection(collection).UpdateOne(ctx,
+		bson.M{"_id": objID, "deleted_at": bson.M{"$ne": nil}},
+		bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": ""}},
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("error restoring the document with id [%s] in %s", id, collection)
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return notFound
+	}
+
+	return nil
+}
+
+// RestoreMovie restores a soft-deleted movie
+func (r *MongoStore) RestoreMovie(ctx context.Context, id string) error {
+	return r.restore(ctx, "movies", id, ErrMovieNotFound)
+}
+
+// RestoreHall restores a soft-deleted hall. It fails with ErrHallNameTaken
+// when another hall of the cinema got its name meanwhile.
+func (r *MongoStore) RestoreHall(ctx context.Context, id string) error {
+	err := r.restore(ctx, "halls", id, ErrHallNotFound)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrHallNameTaken
+	}
+	return err
+}
+
+// RestoreRepertoire restores a soft-deleted repertoire
+func (r *MongoStore) RestoreRepertoire(ctx context.Context, id string) error {
+	return r.restore(ctx, "repertoires", id, ErrRepertoireNotFound)
+}
+
+// PurgeResult holds the number of documents removed by PurgeDeleted
+type PurgeResult struct {
+	Movies      int64 `json:"movies"`
+	Halls       int64 `json:"halls"`
+	Repertoires int64 `json:"repertoires"`
+}
+
+// reference is a field of a collection holding the ID of another document
+type reference struct {
+	collection string
+	field      string
+}
+
+// PurgeDeleted permanently removes the movies, halls and repertoires that were
+// soft deleted before the given time. Records that reservations, reviews or
+// the remaining repertoires still reference are kept, so the history of past
+// screenings stays complete.
+func (r *MongoStore) PurgeDeleted(ctx context.Context, before time.Time) (PurgeResult, error) {
+	var result PurgeResult
+	filter := bson.M{"deleted_at": bson.M{"$lt": before}}
+
+	counts := []struct {
+		collection string
+		count      *int64
+		references []reference
+	}{
+		{"repertoires", &result.Repertoires, []reference{{"reservations", "repertoiresId"}}},
+		{"movies", &result.Movies, []reference{{"reservations", "movieId"}, {"reviews", "movieId"}, {"repertoires", "movieId"}}},
+		{"halls", &result.Halls, []reference{{"reservations", "hallId"}, {"repertoires", "hallId"}}},
+	}
+	for _, c := range counts {
+		ids, err := r.db(ctx).Collection(c.collection).Distinct(ctx, "_id", filter)
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msgf("could not get deleted %s", c.collection)
+			return result, err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		referenced := bson.A{}
+		for _, ref := range c.references {
+			refIDs, err := r.db(ctx).Collection(ref.collection).Distinct(ctx, ref.field, bson.M{ref.field: bson.M{"$in": ids}})
+			if err != nil {
+				zerolog.Ctx(ctx).Error().Err(err).Msgf("could not get %s referencing deleted %s", ref.collection, c.collection)
+				return result, err
+			}
+			referenced = append(referenced, refIDs...)
+		}
+
+		res, err := r.db(ctx).Collection(c.collection).DeleteMany(ctx, bson.M{
+			"_id":        bson.M{"$in": ids, "$nin": referenced},
+			"deleted_at": bson.M{"$lt": before},
+		})
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msgf("could not purge deleted %s", c.collection)
+			return result, err
+		}
+		*c.count = res.DeletedCount
+	}
+
+	return result, nil
+}
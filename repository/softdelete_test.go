@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/util"
+)
+
+func TestSoftDeleteAndRestoreMovie(t *testing.T) {
+	movie := createRandomMovie(t)
+	admin := util.RandomOwner()
+
+	_, err := testStore.DeleteMovie(context.Background(), movie.ID.Hex(), admin, false)
+	require.NoError(t, err)
+
+	_, err = testStore.GetMovie(context.Background(), movie.ID.Hex())
+	require.EqualError(t, err, ErrMovieNotFound.Error())
+
+	movies, err := testStore.ListMovies(context.Background())
+	require.NoError(t, err)
+	for _, m := range movies {
+		require.NotEqual(t, movie.ID, m.ID)
+	}
+
+	// deleting twice finds nothing to delete
+	_, err = testStore.DeleteMovie(context.Background(), movie.ID.Hex(), admin, false)
+	require.EqualError(t, err, ErrMovieNotFound.Error())
+
+	require.NoError(t, testStore.RestoreMovie(context.Background(), movie.ID.Hex()))
+
+	restored, err := testStore.GetMovie(context.Background(), movie.ID.Hex())
+	require.NoError(t, err)
+	require.Nil(t, restored.DeletedAt)
+	require.Empty(t, restored.DeletedBy)
+
+	// a movie that is not deleted cannot be restored
+	err = testStore.RestoreMovie(context.Background(), movie.ID.Hex())
+	require.EqualError(t, err, ErrMovieNotFound.Error())
+}
+
+func TestPurgeDeleted(t *testing.T) {
+	hall := createRandomHall(t)
+	_, err := testStore.DeleteHall(context.Background(), hall.ID.Hex(), util.RandomOwner(), false)
+	require.NoError(t, err)
+
+	// still within the retention window
+	_, err = testStore.PurgeDeleted(context.Background(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, testStore.RestoreHall(context.Background(), hall.ID.Hex()))
+
+	_, err = testStore.DeleteHall(context.Background(), hall.ID.Hex(), util.RandomOwner(), false)
+	require.NoError(t, err)
+	result, err := testStore.PurgeDeleted(context.Background(), time.Now().Add(time.Second))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, result.Halls, int64(1))
+
+	err = testStore.RestoreHall(context.Background(), hall.ID.Hex())
+	require.EqualError(t, err, ErrHallNotFound.Error())
+}
+
+func TestPurgeDeletedKeepsReferenced(t *testing.T) {
+	user := createRandomUser(t)
+	movie := createRandomMovie(t)
+	repertoire := createRandomRepertoireForMovie(t, movie.ID)
+	admin := util.RandomOwner()
+
+	// a reservation of a past screening is kept as history
+	_, err := testStore.InsertReservation(context.Background(), &models.Reservation{
+		Username:      user.Username,
+		UserID:        user.ID,
+		MovieID:       movie.ID,
+		RepertoiresID: repertoire.ID,
+		MovieTitle:    movie.Title,
+		Date:          time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1),
+		Time:          repertoire.Time,
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.DeleteRepertoire(context.Background(), repertoire.ID.Hex(), admin, false)
+	require.NoError(t, err)
+	_, err = testStore.DeleteMovie(context.Background(), movie.ID.Hex(), admin, false)
+	require.NoError(t, err)
+
+	_, err = testStore.PurgeDeleted(context.Background(), time.Now().Add(time.Second))
+	require.NoError(t, err)
+
+	// the reservation still references both, so they can be restored
+	require.NoError(t, testStore.RestoreRepertoire(context.Background(), repertoire.ID.Hex()))
+	require.NoError(t, testStore.RestoreMovie(context.Background(), movie.ID.Hex()))
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+)
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx whose store queries go to the database of
+// tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant of ctx, empty for single tenant
+// deployments
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// TenantDatabase returns the name of the database of a tenant: dbName itself
+// without a tenant, dbName_tenant otherwise
+func TenantDatabase(dbName string, tenant string) string {
+	if tenant == "" {
+		return dbName
+	}
+	return dbName + "_" + tenant
+}
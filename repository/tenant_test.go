@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/util"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	acme := WithTenant(context.Background(), "acme-"+util.RandomString(6))
+	globex := WithTenant(context.Background(), "globex-"+util.RandomString(6))
+
+	cinema, err := testStore.AddCinema(acme, &models.Cinema{Name: util.RandomString(10), TimeZone: "UTC"})
+	require.NoError(t, err)
+	hall, err := testStore.InsertHall(acme, &models.Hall{CinemaID: cinema.ID, Name: util.RandomHall(), Rows: []string{"A"}, Cols: []int{1, 2}})
+	require.NoError(t, err)
+
+	_, err = testStore.GetCinema(acme, cinema.ID.Hex())
+	require.NoError(t, err)
+
+	// neither another tenant nor the default database sees the records
+	for _, ctx := range []context.Context{globex, context.Background()} {
+		_, err = testStore.GetCinema(ctx, cinema.ID.Hex())
+		require.ErrorIs(t, err, ErrCinemaNotFound)
+
+		_, err = testStore.GetHallById(ctx, hall.ID.Hex())
+		require.Error(t, err)
+
+		halls, err := testStore.ListHalls(ctx)
+		require.NoError(t, err)
+		for _, h := range halls {
+			require.NotEqual(t, hall.ID, h.ID)
+		}
+	}
+
+	// nor can it add halls to the cinema of another tenant
+	_, err = testStore.InsertHall(globex, &models.Hall{CinemaID: cinema.ID, Name: util.RandomHall(), Rows: []string{"A"}, Cols: []int{1}})
+	require.ErrorIs(t, err, ErrCinemaNotFound)
+}
+
+func TestTenantDatabase(t *testing.T) {
+	require.Equal(t, "movies", TenantDatabase("movies", ""))
+	require.Equal(t, "movies_acme", TenantDatabase("movies", "acme"))
+}
@@ -3,24 +3,54 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
 	"time"
 
+	"github.com/tijanadmi/movieginmongoapi/metrics"
 	"github.com/tijanadmi/movieginmongoapi/models"
 	"github.com/tijanadmi/movieginmongoapi/util"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
+var (
+	ErrAgeRestricted    = errors.New("user is too young for this movie")
+	ErrNotEnoughTickets = errors.New("not enough tickets available")
+)
+
 // TransferTxParams contains the input parameters of the transfer transaction
 type AddReservationParams struct {
 	Username    string    `json:"username" binding:"required"`
 	MovieID     string    `json:"movieId" binding:"required"`
 	Date        time.Time `json:"date" binding:"required"`
 	Time        string    `json:"time" binding:"required"`
-	Hall        string    `json:"hall" binding:"required"`
+	HallID      string    `json:"hallId" binding:"required_without=Hall"`
+	Hall        string    `json:"hall" binding:"required_without=HallID"`
 	ReservSeats []string  `json:"reservSeats" binding:"required"`
+	// AgeOverride lets an admin book an age restricted movie for a viewer who
+	// is too young or whose age is unknown; the ticket then requires an ID check.
+	AgeOverride bool `json:"ageOverride"`
+}
+
+// checkAgeRestriction reports whether the user may see the movie on the given
+// date and whether the ticket requires an ID check at the entrance.
+func checkAgeRestriction(movie *models.Movie, user *models.User, date time.Time, override bool) (bool, error) {
+	if movie.AgeRating == 0 {
+		return false, nil
+	}
+	if user.DateOfBirth.IsZero() {
+		return true, nil
+	}
+	if util.Age(user.DateOfBirth, date) >= movie.AgeRating {
+		return false, nil
+	}
+	if override {
+		return true, nil
+	}
+	return false, ErrAgeRestricted
 }
 
 func (r *MongoStore) AddReservation(ctx context.Context, req AddReservationParams) (*models.Reservation, error) {
@@ -29,14 +59,18 @@ func (r *MongoStore) AddReservation(ctx context.Context, req AddReservationParam
 	txnOptions := options.Transaction().SetWriteConcern(wc)
 
 	// Starts a session on the client
-	session, err := r.db.Client().StartSession()
+	session, err := r.db(ctx).Client().StartSession()
 	if err != nil {
 		panic(err)
 	}
 	// Defers ending the session after the transaction is committed or ended
 	defer session.EndSession(ctx)
 
+	// WithTransaction runs the callback again after transient errors
+	attempts := 0
 	result, err := session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		attempts++
+
 		// Prvo čitanje filma
 		movie, err := r.GetMovie(sessionCtx, req.MovieID)
 		if err != nil {
@@ -49,18 +83,69 @@ func (r *MongoStore) AddReservation(ctx context.Context, req AddReservationParam
 			return nil, err
 		}
 
+		// The hall is given by ID or, for older clients, by its name
+		slot := models.Repertoire{Hall: req.Hall}
+		if req.HallID != "" {
+			if slot.HallID, err = primitive.ObjectIDFromHex(req.HallID); err != nil {
+				return nil, ErrHallNotFound
+			}
+		}
+		hall, err := r.resolveHall(sessionCtx, &slot)
+		if err != nil {
+			return nil, err
+		}
+		cinema, err := r.GetCinema(sessionCtx, hall.CinemaID.Hex())
+		if err != nil {
+			return nil, err
+		}
+
 		// Čitanje repertoara
 		var repertoire models.Repertoire
-		repertoire, err = r.GetRepertoireByMovieDateTimeHall(sessionCtx, req.MovieID, req.Date, req.Time, req.Hall)
+		repertoire, err = r.GetRepertoireByMovieDateTimeHall(sessionCtx, req.MovieID, req.Date, req.Time, slot.HallID.Hex())
+		if err != nil {
+			return nil, err
+		}
+
+		requiresIDCheck, err := checkAgeRestriction(movie, user, repertoire.Date, req.AgeOverride)
 		if err != nil {
 			return nil, err
 		}
 
 		numOfSeats := len(req.ReservSeats)
 
+		// Only sellable seats that are not blocked, reserved or held for someone
+		// else can be booked; they are priced by the category of the seat
+		now := time.Now()
+		heldByOthers := repertoire.HeldByOthers(user.Username, now)
+		reserved := make(map[string]bool, len(repertoire.ReservSeats))
+		for _, seat := range repertoire.ReservSeats {
+			reserved[seat] = true
+		}
+		categories := make(map[string]string)
+		for _, seat := range hall.Layout() {
+			if !seat.Disabled {
+				categories[seat.Label] = seat.Category
+			}
+		}
+		var price int64
+		for _, seat := range req.ReservSeats {
+			category, ok := categories[seat]
+			switch {
+			case !ok:
+				return nil, fmt.Errorf("%w: %s", ErrUnknownSeat, seat)
+			case repertoire.IsBlocked(seat):
+				return nil, fmt.Errorf("%w: %s", ErrSeatBlocked, seat)
+			case reserved[seat]:
+				return nil, fmt.Errorf("%w: %s", ErrSeatReserved, seat)
+			case heldByOthers[seat]:
+				return nil, fmt.Errorf("%w: %s", ErrSeatHeld, seat)
+			}
+			price += cinema.Price(category)
+		}
+
 		// Provera dostupnih mesta
-		if repertoire.NumOfTickets < repertoire.NumOfResTickets+numOfSeats {
-			return nil, errors.New("not enough tickets available")
+		if repertoire.AvailableTickets()-len(heldByOthers) < numOfSeats {
+			return nil, ErrNotEnoughTickets
 		}
 
 		// Ažuriranje repertoara
@@ -75,6 +160,11 @@ func (r *MongoStore) AddReservation(ctx context.Context, req AddReservationParam
 			return nil, err
 		}
 
+		// The user's holds are used up by the reservation
+		if err = r.releaseHolds(sessionCtx, repertoire.ID, user.Username); err != nil {
+			return nil, err
+		}
+
 		// Kreiranje rezervacije
 		sort.Strings(req.ReservSeats)
 		var reservation *models.Reservation
@@ -86,9 +176,15 @@ func (r *MongoStore) AddReservation(ctx context.Context, req AddReservationParam
 			RepertoiresID: repertoire.ID,
 			Date:          repertoire.Date,
 			Time:          repertoire.Time,
+			CinemaID:      repertoire.CinemaID,
+			HallID:        repertoire.HallID,
 			Hall:          repertoire.Hall,
 			CreationDate:  time.Now(),
 			ReservSeats:   req.ReservSeats,
+
+			RequiresIDCheck: requiresIDCheck,
+			Price:           price,
+			Currency:        cinema.Pricing.Currency,
 		}
 
 		// Unos rezervacije u okviru transakcije
@@ -99,6 +195,9 @@ func (r *MongoStore) AddReservation(ctx context.Context, req AddReservationParam
 
 		return reservation, nil
 	}, txnOptions)
+	if attempts > 1 {
+		metrics.TransactionRetries.WithLabelValues("AddReservation").Add(float64(attempts - 1))
+	}
 	if err != nil {
 		return nil, err
 
@@ -111,6 +210,8 @@ func (r *MongoStore) AddReservation(ctx context.Context, req AddReservationParam
 		return nil, errors.New("unexpected result type")
 	}
 
+	metrics.ReservationsCreated.Inc()
+	metrics.SeatsSold.Add(float64(len(reservation.ReservSeats)))
 	return reservation, nil
 }
 
@@ -120,7 +221,7 @@ func (r *MongoStore) CancelReservation(ctx context.Context, resId string) error
 	txnOptions := options.Transaction().SetWriteConcern(wc)
 
 	// Starts a session on the client
-	session, err := r.db.Client().StartSession()
+	session, err := r.db(ctx).Client().StartSession()
 	if err != nil {
 		panic(err)
 	}
@@ -167,6 +268,30 @@ func (r *MongoStore) CancelReservation(ctx context.Context, resId string) error
 	if result == nil {
 		errors.New("result is empty")
 	}
+	metrics.ReservationsCancelled.Inc()
 	return nil
 
 }
+
+// WithTransaction runs fn in a transaction; the writes fn does with the context
+// it is given are committed together, or not at all when fn returns an error
+func (r *MongoStore) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	wc := writeconcern.Majority()
+	txnOptions := options.Transaction().SetWriteConcern(wc)
+
+	session, err := r.db(ctx).Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	attempts := 0
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		attempts++
+		return nil, fn(sessionCtx)
+	}, txnOptions)
+	if attempts > 1 {
+		metrics.TransactionRetries.WithLabelValues("WithTransaction").Add(float64(attempts - 1))
+	}
+	return err
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/tijanadmi/movieginmongoapi/models"
+	"github.com/tijanadmi/movieginmongoapi/util"
 )
 
 func CreateRandomAddReservation(t *testing.T) *models.Reservation {
@@ -54,3 +56,83 @@ func TestCancelReservation(t *testing.T) {
 	require.EqualError(t, err, ErrReservationNotFound.Error())
 	require.Empty(t, reservation1)
 }
+
+func TestWithTransaction(t *testing.T) {
+	var added *models.Movie
+	err := testStore.WithTransaction(context.Background(), func(ctx context.Context) error {
+		movie, err := testStore.AddMovie(ctx, &models.Movie{Title: util.RandomString(50), Duration: 90})
+		if err != nil {
+			return err
+		}
+		added = movie
+		return ErrNotEnoughTickets
+	})
+	require.ErrorIs(t, err, ErrNotEnoughTickets)
+
+	// the movie is rolled back with the transaction
+	_, err = testStore.GetMovie(context.Background(), added.ID.Hex())
+	require.ErrorIs(t, err, ErrMovieNotFound)
+
+	err = testStore.WithTransaction(context.Background(), func(ctx context.Context) error {
+		movie, err := testStore.AddMovie(ctx, &models.Movie{Title: util.RandomString(50), Duration: 90})
+		added = movie
+		return err
+	})
+	require.NoError(t, err)
+
+	_, err = testStore.GetMovie(context.Background(), added.ID.Hex())
+	require.NoError(t, err)
+}
+
+func TestCheckAgeRestriction(t *testing.T) {
+	screening := time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC)
+	restricted := &models.Movie{AgeRating: 16}
+
+	testCases := []struct {
+		name            string
+		movie           *models.Movie
+		dateOfBirth     time.Time
+		override        bool
+		requiresIDCheck bool
+		err             error
+	}{
+		{
+			name:  "NotRated",
+			movie: &models.Movie{},
+		},
+		{
+			name:        "OldEnough",
+			movie:       restricted,
+			dateOfBirth: time.Date(2008, time.June, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:            "UnknownAge",
+			movie:           restricted,
+			requiresIDCheck: true,
+		},
+		{
+			name:        "TooYoung",
+			movie:       restricted,
+			dateOfBirth: time.Date(2008, time.June, 16, 0, 0, 0, 0, time.UTC),
+			err:         ErrAgeRestricted,
+		},
+		{
+			name:            "TooYoungOverride",
+			movie:           restricted,
+			dateOfBirth:     time.Date(2008, time.June, 16, 0, 0, 0, 0, time.UTC),
+			override:        true,
+			requiresIDCheck: true,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			user := &models.User{DateOfBirth: tc.dateOfBirth}
+			requiresIDCheck, err := checkAgeRestriction(tc.movie, user, screening, tc.override)
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, tc.requiresIDCheck, requiresIDCheck)
+		})
+	}
+}
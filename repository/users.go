@@ -3,10 +3,9 @@ package repository
 import (
 	"context"
 	"errors"
-	"fmt"
-	"log"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/tijanadmi/movieginmongoapi/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -21,17 +20,17 @@ import (
 func (r *MongoStore) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
 	var dbUser models.User
 
-	res := r.db.Collection("users").FindOne(ctx, bson.M{"username": username})
+	res := r.db(ctx).Collection("users").FindOne(ctx, bson.M{"username": username})
 	if res.Err() != nil {
 		if errors.Is(res.Err(), mongo.ErrNoDocuments) {
 			return &dbUser, res.Err()
 		}
-		log.Print(fmt.Errorf("error when finding the dbUser [%s]: %q", username, res.Err()))
+		zerolog.Ctx(ctx).Error().Err(res.Err()).Msgf("error when finding the dbUser [%s]", username)
 		return &dbUser, res.Err()
 	}
 
 	if err := res.Decode(&dbUser); err != nil {
-		log.Print(fmt.Errorf("error decoding [%s]: %q", username, err))
+		zerolog.Ctx(ctx).Error().Err(err).Msgf("error decoding [%s]", username)
 		return &dbUser, err
 	}
 
@@ -47,10 +46,10 @@ func (r *MongoStore) InsertUser(ctx context.Context, user *models.User) (*models
 	user.DateOfCreation = time.Now()
 	user.DateOfLastUpdate = time.Now()
 
-	result, err := r.db.Collection("users").InsertOne(ctx, user)
+	result, err := r.db(ctx).Collection("users").InsertOne(ctx, user)
 
 	if err != nil {
-		log.Print(fmt.Errorf("could not add new user: %w", err))
+		zerolog.Ctx(ctx).Error().Err(err).Msg("could not add new user")
 		return nil, err
 	}
 	user.ID = result.InsertedID.(primitive.ObjectID)
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GridFSStorage keeps objects in a MongoDB GridFS bucket
+type GridFSStorage struct {
+	bucket *gridfs.Bucket
+}
+
+// gridFSFile is the part of a GridFS files document the storage reads
+type gridFSFile struct {
+	ID         interface{} `bson:"_id"`
+	Length     int64       `bson:"length"`
+	UploadDate time.Time   `bson:"uploadDate"`
+	Metadata   struct {
+		ContentType string `bson:"contentType"`
+	} `bson:"metadata"`
+}
+
+// NewGridFSStorage creates a storage backed by the named GridFS bucket of the database
+func NewGridFSStorage(db *mongo.Database, bucketName string) (*GridFSStorage, error) {
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName(bucketName))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create gridfs bucket: %w", err)
+	}
+	return &GridFSStorage{bucket: bucket}, nil
+}
+
+// Save uploads the object, replacing an existing object with the same name
+func (s *GridFSStorage) Save(ctx context.Context, name string, contentType string, data []byte) error {
+	existing, err := s.find(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	uploadOpts := options.GridFSUpload().SetMetadata(bson.M{"contentType": contentType})
+	if _, err = s.bucket.UploadFromStream(name, bytes.NewReader(data), uploadOpts); err != nil {
+		return fmt.Errorf("cannot upload %s: %w", name, err)
+	}
+
+	for _, file := range existing {
+		if err = s.bucket.DeleteContext(ctx, file.ID); err != nil {
+			return fmt.Errorf("cannot replace %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Open downloads the object; posters are small, so the content is read into memory
+func (s *GridFSStorage) Open(ctx context.Context, name string) (io.ReadSeekCloser, Object, error) {
+	files, err := s.find(ctx, name)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	if len(files) == 0 {
+		return nil, Object{}, ErrObjectNotFound
+	}
+	file := files[len(files)-1]
+
+	var buf bytes.Buffer
+	if _, err = s.bucket.DownloadToStream(file.ID, &buf); err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return nil, Object{}, ErrObjectNotFound
+		}
+		return nil, Object{}, err
+	}
+
+	return nopCloser{bytes.NewReader(buf.Bytes())}, Object{
+		Name:        name,
+		ContentType: file.Metadata.ContentType,
+		Size:        file.Length,
+		ModTime:     file.UploadDate,
+	}, nil
+}
+
+// Delete removes every revision of the object
+func (s *GridFSStorage) Delete(ctx context.Context, name string) error {
+	files, err := s.find(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return ErrObjectNotFound
+	}
+
+	for _, file := range files {
+		if err = s.bucket.DeleteContext(ctx, file.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// find returns the revisions of the named object, oldest first
+func (s *GridFSStorage) find(ctx context.Context, name string) ([]gridFSFile, error) {
+	cur, err := s.bucket.FindContext(ctx, bson.M{"filename": name}, options.GridFSFind().SetSort(bson.M{"uploadDate": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []gridFSFile
+	if err = cur.All(ctx, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage keeps objects as files in a directory on the local filesystem
+type LocalStorage struct {
+	dir string
+}
+
+// NewLocalStorage creates a local storage rooted at dir, creating the directory if needed
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create storage directory: %w", err)
+	}
+	return &LocalStorage{dir: dir}, nil
+}
+
+// Save writes the object atomically, replacing an existing object with the same name
+func (s *LocalStorage) Save(ctx context.Context, name string, contentType string, data []byte) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("cannot create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("cannot write file: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("cannot write file: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Open returns the content of the object; the content type is derived from the file extension
+func (s *LocalStorage) Open(ctx context.Context, name string) (io.ReadSeekCloser, Object, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, Object{}, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, Object{}, ErrObjectNotFound
+		}
+		return nil, Object{}, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, Object{}, err
+	}
+
+	return file, Object{
+		Name:        name,
+		ContentType: mime.TypeByExtension(filepath.Ext(name)),
+		Size:        info.Size(),
+		ModTime:     info.ModTime(),
+	}, nil
+}
+
+// Delete removes the object
+func (s *LocalStorage) Delete(ctx context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ErrObjectNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// path maps an object name to a file inside the storage directory, rejecting names
+// that could escape it
+func (s *LocalStorage) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || name[0] == '.' {
+		return "", ErrObjectNotFound
+	}
+	return filepath.Join(s.dir, name), nil
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, store.Save(ctx, "poster.png", "image/png", []byte("first")))
+	require.NoError(t, store.Save(ctx, "poster.png", "image/png", []byte("second")))
+
+	content, object, err := store.Open(ctx, "poster.png")
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+	require.NoError(t, content.Close())
+
+	require.Equal(t, []byte("second"), data)
+	require.Equal(t, "image/png", object.ContentType)
+	require.Equal(t, int64(len("second")), object.Size)
+	require.NotZero(t, object.ModTime)
+
+	require.NoError(t, store.Delete(ctx, "poster.png"))
+	_, _, err = store.Open(ctx, "poster.png")
+	require.ErrorIs(t, err, ErrObjectNotFound)
+	require.ErrorIs(t, store.Delete(ctx, "poster.png"), ErrObjectNotFound)
+}
+
+func TestLocalStorageRejectsPaths(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	for _, name := range []string{"", "../poster.png", "dir/poster.png", ".hidden"} {
+		_, _, err = store.Open(context.Background(), name)
+		require.ErrorIs(t, err, ErrObjectNotFound, name)
+	}
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// PartitionedStorage keeps the objects of each partition, e.g. of each
+// tenant, in a storage of its own, so one partition cannot read or replace
+// the objects of another. The storage of a partition is created on first use.
+type PartitionedStorage struct {
+	partition func(ctx context.Context) string
+	create    func(partition string) (Storage, error)
+
+	mu       sync.Mutex
+	storages map[string]Storage
+}
+
+// NewPartitionedStorage creates a storage that uses partition to find the
+// partition of a request and create to make the storage of a partition
+func NewPartitionedStorage(partition func(ctx context.Context) string, create func(partition string) (Storage, error)) *PartitionedStorage {
+	return &PartitionedStorage{
+		partition: partition,
+		create:    create,
+		storages:  make(map[string]Storage),
+	}
+}
+
+// Save saves the object in the storage of the partition of ctx
+func (s *PartitionedStorage) Save(ctx context.Context, name string, contentType string, data []byte) error {
+	store, err := s.storage(ctx)
+	if err != nil {
+		return err
+	}
+	return store.Save(ctx, name, contentType, data)
+}
+
+// Open opens the object from the storage of the partition of ctx
+func (s *PartitionedStorage) Open(ctx context.Context, name string) (io.ReadSeekCloser, Object, error) {
+	store, err := s.storage(ctx)
+	if err != nil {
+		return nil, Object{}, err
+	}
+	return store.Open(ctx, name)
+}
+
+// Delete removes the object from the storage of the partition of ctx
+func (s *PartitionedStorage) Delete(ctx context.Context, name string) error {
+	store, err := s.storage(ctx)
+	if err != nil {
+		return err
+	}
+	return store.Delete(ctx, name)
+}
+
+func (s *PartitionedStorage) storage(ctx context.Context) (Storage, error) {
+	partition := s.partition(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if store, ok := s.storages[partition]; ok {
+		return store, nil
+	}
+	store, err := s.create(partition)
+	if err != nil {
+		return nil, err
+	}
+	s.storages[partition] = store
+	return store, nil
+}
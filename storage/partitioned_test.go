@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type partitionKey struct{}
+
+func TestPartitionedStorage(t *testing.T) {
+	dir := t.TempDir()
+	store := NewPartitionedStorage(
+		func(ctx context.Context) string {
+			partition, _ := ctx.Value(partitionKey{}).(string)
+			return partition
+		},
+		func(partition string) (Storage, error) {
+			return NewLocalStorage(filepath.Join(dir, partition))
+		},
+	)
+
+	acme := context.WithValue(context.Background(), partitionKey{}, "acme")
+	globex := context.WithValue(context.Background(), partitionKey{}, "globex")
+
+	require.NoError(t, store.Save(acme, "poster.png", "image/png", []byte("acme")))
+	require.NoError(t, store.Save(globex, "poster.png", "image/png", []byte("globex")))
+
+	content, _, err := store.Open(acme, "poster.png")
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+	require.NoError(t, content.Close())
+	require.Equal(t, []byte("acme"), data)
+
+	// objects of other partitions are not visible
+	_, _, err = store.Open(context.Background(), "poster.png")
+	require.ErrorIs(t, err, ErrObjectNotFound)
+
+	require.NoError(t, store.Delete(globex, "poster.png"))
+	_, _, err = store.Open(globex, "poster.png")
+	require.ErrorIs(t, err, ErrObjectNotFound)
+	_, _, err = store.Open(acme, "poster.png")
+	require.NoError(t, err)
+}
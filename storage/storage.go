@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// Object describes a stored file
+type Object struct {
+	Name        string
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
+
+// Storage stores binary objects (movie posters) by name
+type Storage interface {
+	Save(ctx context.Context, name string, contentType string, data []byte) error
+	Open(ctx context.Context, name string) (io.ReadSeekCloser, Object, error)
+	Delete(ctx context.Context, name string) error
+}
@@ -28,9 +28,9 @@ func NewPasetoMaker(symmetricKey string) (Maker, error) {
 	return maker, nil
 }
 
-// CreateToken creates a new token for a specific username and duration
-func (maker *PasetoMaker) CreateToken(username string, role string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, role, duration)
+// CreateToken creates a new token for a specific username, tenant and duration
+func (maker *PasetoMaker) CreateToken(username string, role string, tenant string, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tenant, duration)
 	if err != nil {
 		return "", payload, err
 	}
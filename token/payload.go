@@ -18,12 +18,13 @@ type Payload struct {
 	ID        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
 	Role      string    `json:"role"`
+	Tenant    string    `json:"tenant,omitempty"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
-// NewPayload creates a new token payload with a specific username and duration
-func NewPayload(username string, role string, duration time.Duration) (*Payload, error) {
+// NewPayload creates a new token payload with a specific username, tenant and duration
+func NewPayload(username string, role string, tenant string, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -33,6 +34,7 @@ func NewPayload(username string, role string, duration time.Duration) (*Payload,
 		ID:        tokenID,
 		Username:  username,
 		Role:      role,
+		Tenant:    tenant,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tijanadmi/movieginmongoapi/util"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSetupWithoutEndpoint(t *testing.T) {
+	shutdown, err := Setup(context.Background(), util.Config{})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	// spans go nowhere
+	_, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	require.False(t, ok)
+}
+
+func TestSetupWithEndpoint(t *testing.T) {
+	shutdown, err := Setup(context.Background(), util.Config{OTLPEndpoint: "localhost:4317", OTLPInsecure: true})
+	require.NoError(t, err)
+
+	_, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	require.True(t, ok)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	shutdown(ctx)
+}
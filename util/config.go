@@ -1,8 +1,16 @@
 package util
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/aead/chacha20poly1305"
 	"github.com/spf13/viper"
 )
 
@@ -10,37 +18,256 @@ import (
 // The values are read by viper from a config file or environment variable.
 type Config struct {
 	Environment          string        `mapstructure:"ENVIRONMENT"`
-	DBDriver             string        `mapstructure:"DB_DRIVER"`
-	DBSource             string        `mapstructure:"DB_SOURCE"`
 	HTTPServerAddress    string        `mapstructure:"HTTP_SERVER_ADDRESS"`
-	MigrationURL         string        `mapstructure:"MIGRATION_URL"`
+	HTTPReadTimeout      time.Duration `mapstructure:"HTTP_READ_TIMEOUT"`
+	HTTPWriteTimeout     time.Duration `mapstructure:"HTTP_WRITE_TIMEOUT"`
+	HTTPIdleTimeout      time.Duration `mapstructure:"HTTP_IDLE_TIMEOUT"`
+	ShutdownTimeout      time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
 	RedisAddress         string        `mapstructure:"REDIS_ADDRESS"`
-	GRPCServerAddress    string        `mapstructure:"GRPC_SERVER_ADDRESS"`
 	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
-	EmailSenderName      string        `mapstructure:"EMAIL_SENDER_NAME"`
-	EmailSenderAddress   string        `mapstructure:"EMAIL_SENDER_ADDRESS"`
-	EmailSenderPassword  string        `mapstructure:"EMAIL_SENDER_PASSWORD"`
 	MongoURL             string        `mapstructure:"MONGO_URL"`
 	Username             string        `mapstructure:"USERNAME"`
 	Password             string        `mapstructure:"PASSWORD"`
 	Database             string        `mapstructure:"DATABASE"`
+	PosterStorage        string        `mapstructure:"POSTER_STORAGE"`
+	PosterDir            string        `mapstructure:"POSTER_DIR"`
+	PosterMaxSize        int64         `mapstructure:"POSTER_MAX_SIZE"`
+	SoftDeleteRetention  time.Duration `mapstructure:"SOFT_DELETE_RETENTION"`
+	PurgeInterval        time.Duration `mapstructure:"PURGE_INTERVAL"`
+	SeatHoldDuration     time.Duration `mapstructure:"SEAT_HOLD_DURATION"`
+	Tenants              []string      `mapstructure:"TENANTS"`
+	TenantHeader         string        `mapstructure:"TENANT_HEADER"`
+	OTLPEndpoint         string        `mapstructure:"OTLP_ENDPOINT"`
+	OTLPInsecure         bool          `mapstructure:"OTLP_INSECURE"`
+	LoginAttemptsPerIP   int           `mapstructure:"LOGIN_ATTEMPTS_PER_IP"`
+	LoginAttemptsPerUser int           `mapstructure:"LOGIN_ATTEMPTS_PER_USER"`
+	LoginMaxFailures     int           `mapstructure:"LOGIN_MAX_FAILURES"`
+	LoginLockout         time.Duration `mapstructure:"LOGIN_LOCKOUT"`
+	CORSAllowedOrigins   []string      `mapstructure:"CORS_ALLOWED_ORIGINS"`
+	CORSAllowedMethods   []string      `mapstructure:"CORS_ALLOWED_METHODS"`
+	CORSAllowedHeaders   []string      `mapstructure:"CORS_ALLOWED_HEADERS"`
+	TLSCertFile          string        `mapstructure:"TLS_CERT_FILE"`
+	TLSKeyFile           string        `mapstructure:"TLS_KEY_FILE"`
+	HSTSMaxAge           time.Duration `mapstructure:"HSTS_MAX_AGE"`
+	TrustedProxies       []string      `mapstructure:"TRUSTED_PROXIES"`
 }
 
-// LoadConfig reads configuration from file or environment variables.
+// configDefaults are used for the addresses and durations that are set
+// neither in the config file nor in the environment
+var configDefaults = map[string]any{
+	"ENVIRONMENT":             "production",
+	"HTTP_SERVER_ADDRESS":     "0.0.0.0:8080",
+	"HTTP_READ_TIMEOUT":       15 * time.Second,
+	"HTTP_WRITE_TIMEOUT":      60 * time.Second,
+	"HTTP_IDLE_TIMEOUT":       120 * time.Second,
+	"SHUTDOWN_TIMEOUT":        30 * time.Second,
+	"ACCESS_TOKEN_DURATION":   15 * time.Minute,
+	"REFRESH_TOKEN_DURATION":  24 * time.Hour,
+	"MONGO_URL":               "mongodb://localhost:27017",
+	"POSTER_STORAGE":          "local",
+	"POSTER_DIR":              "posters",
+	"POSTER_MAX_SIZE":         5 << 20,
+	"SOFT_DELETE_RETENTION":   30 * 24 * time.Hour,
+	"PURGE_INTERVAL":          24 * time.Hour,
+	"SEAT_HOLD_DURATION":      10 * time.Minute,
+	"TENANT_HEADER":           "X-Tenant-ID",
+	"LOGIN_ATTEMPTS_PER_IP":   20,
+	"LOGIN_ATTEMPTS_PER_USER": 5,
+	"LOGIN_MAX_FAILURES":      5,
+	"LOGIN_LOCKOUT":           15 * time.Minute,
+	"HSTS_MAX_AGE":            365 * 24 * time.Hour,
+}
+
+// tenantName is a tenant usable in a database name
+var tenantName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// secretKeys can also be read from the file named by the key with a _FILE
+// suffix, e.g. a mounted Docker or Kubernetes secret
+var secretKeys = []string{"TOKEN_SYMMETRIC_KEY", "PASSWORD", "MONGO_URL"}
+
+// LoadConfig reads configuration from file or environment variables and
+// validates it. The config file is optional when the environment has
+// everything.
 func LoadConfig(path string) (config Config, err error) {
-	viper.AddConfigPath(path)
-	viper.SetConfigName("app")
-	viper.SetConfigType("env")
+	v := viper.New()
+	v.AddConfigPath(path)
+	v.SetConfigName("app")
+	v.SetConfigType("env")
+
+	for key, value := range configDefaults {
+		v.SetDefault(key, value)
+	}
+	// unmarshal only sees the environment variables of keys viper knows
+	for _, key := range configKeys() {
+		if err = v.BindEnv(key); err != nil {
+			return
+		}
+		if err = v.BindEnv(key + "_FILE"); err != nil {
+			return
+		}
+	}
+
+	err = v.ReadInConfig()
+	if err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) {
+			return
+		}
+	}
 
-	viper.AutomaticEnv()
+	for _, key := range secretKeys {
+		if err = readSecretFile(v, key); err != nil {
+			return
+		}
+	}
 
-	err = viper.ReadInConfig()
+	err = v.Unmarshal(&config)
 	if err != nil {
 		return
 	}
 
-	err = viper.Unmarshal(&config)
+	err = config.Validate()
 	return
 }
+
+// readSecretFile sets key to the content of the file named by key_FILE
+func readSecretFile(v *viper.Viper, key string) error {
+	file := v.GetString(key + "_FILE")
+	if file == "" {
+		return nil
+	}
+	// the file overrides the config file, but not the environment
+	if os.Getenv(key) != "" {
+		return fmt.Errorf("%s and %s_FILE cannot both be set", key, key)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("cannot read %s_FILE: %w", key, err)
+	}
+	// editors and echo end files with a newline that is not part of the secret
+	v.Set(key, strings.TrimRight(string(data), "\r\n"))
+	return nil
+}
+
+// configKeys returns the keys of all Config fields
+func configKeys() []string {
+	t := reflect.TypeOf(Config{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		keys = append(keys, t.Field(i).Tag.Get("mapstructure"))
+	}
+	return keys
+}
+
+// Validate checks the whole config and reports every invalid value at once
+func (config Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if len(config.TokenSymmetricKey) != chacha20poly1305.KeySize {
+		invalid("TOKEN_SYMMETRIC_KEY must be exactly %d characters, got %d", chacha20poly1305.KeySize, len(config.TokenSymmetricKey))
+	}
+	if config.AccessTokenDuration <= 0 {
+		invalid("ACCESS_TOKEN_DURATION must be positive")
+	}
+	if config.RefreshTokenDuration < config.AccessTokenDuration {
+		invalid("REFRESH_TOKEN_DURATION must not be shorter than ACCESS_TOKEN_DURATION")
+	}
+
+	if _, _, err := net.SplitHostPort(config.HTTPServerAddress); err != nil {
+		invalid("HTTP_SERVER_ADDRESS must be host:port: %w", err)
+	}
+	if config.RedisAddress != "" {
+		if _, _, err := net.SplitHostPort(config.RedisAddress); err != nil {
+			invalid("REDIS_ADDRESS must be host:port: %w", err)
+		}
+	}
+
+	if !strings.HasPrefix(config.MongoURL, "mongodb://") && !strings.HasPrefix(config.MongoURL, "mongodb+srv://") {
+		invalid("MONGO_URL must be a mongodb:// or mongodb+srv:// URL")
+	}
+	if config.Database == "" {
+		invalid("DATABASE is required")
+	}
+
+	switch config.PosterStorage {
+	case "local":
+		if config.PosterDir == "" {
+			invalid("POSTER_DIR is required for local poster storage")
+		}
+	case "gridfs":
+	default:
+		invalid("POSTER_STORAGE must be local or gridfs, got %q", config.PosterStorage)
+	}
+	if config.PosterMaxSize <= 0 {
+		invalid("POSTER_MAX_SIZE must be positive")
+	}
+	if len(config.Tenants) > 0 && config.TenantHeader == "" {
+		invalid("TENANT_HEADER is required with TENANTS")
+	}
+
+	for _, tenant := range config.Tenants {
+		if !tenantName.MatchString(tenant) {
+			invalid("TENANTS must have up to 32 lowercase letters, digits and dashes per tenant, got %q", tenant)
+		}
+	}
+
+	// zero HTTP timeouts mean no timeout
+	for _, d := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"HTTP_READ_TIMEOUT", config.HTTPReadTimeout},
+		{"HTTP_WRITE_TIMEOUT", config.HTTPWriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", config.HTTPIdleTimeout},
+		{"HSTS_MAX_AGE", config.HSTSMaxAge},
+	} {
+		if d.value < 0 {
+			invalid("%s must not be negative", d.key)
+		}
+	}
+	for _, d := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"SHUTDOWN_TIMEOUT", config.ShutdownTimeout},
+		{"SOFT_DELETE_RETENTION", config.SoftDeleteRetention},
+		{"PURGE_INTERVAL", config.PurgeInterval},
+		{"SEAT_HOLD_DURATION", config.SeatHoldDuration},
+		{"LOGIN_LOCKOUT", config.LoginLockout},
+	} {
+		if d.value <= 0 {
+			invalid("%s must be positive", d.key)
+		}
+	}
+	for _, n := range []struct {
+		key   string
+		value int
+	}{
+		{"LOGIN_ATTEMPTS_PER_IP", config.LoginAttemptsPerIP},
+		{"LOGIN_ATTEMPTS_PER_USER", config.LoginAttemptsPerUser},
+		{"LOGIN_MAX_FAILURES", config.LoginMaxFailures},
+	} {
+		if n.value <= 0 {
+			invalid("%s must be positive", n.key)
+		}
+	}
+
+	if (config.TLSCertFile == "") != (config.TLSKeyFile == "") {
+		invalid("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	for _, proxy := range config.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				invalid("TRUSTED_PROXIES must list IP addresses or CIDR ranges, got %q", proxy)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
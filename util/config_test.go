@@ -0,0 +1,147 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSymmetricKey = "12345678901234567890123456789012"
+
+func writeConfigFile(t *testing.T, content string) string {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.env"), []byte(content), 0o600))
+	return dir
+}
+
+func TestLoadConfigDefaults(t *testing.T) {
+	dir := writeConfigFile(t, "TOKEN_SYMMETRIC_KEY="+testSymmetricKey+"\nDATABASE=userDB\n")
+
+	config, err := LoadConfig(dir)
+	require.NoError(t, err)
+	require.Equal(t, "0.0.0.0:8080", config.HTTPServerAddress)
+	require.Equal(t, 15*time.Minute, config.AccessTokenDuration)
+	require.Equal(t, 24*time.Hour, config.RefreshTokenDuration)
+	require.Equal(t, "mongodb://localhost:27017", config.MongoURL)
+	require.Equal(t, 30*time.Second, config.ShutdownTimeout)
+	require.Equal(t, "X-Tenant-ID", config.TenantHeader)
+}
+
+func TestLoadConfigFromEnvironment(t *testing.T) {
+	// no config file at all
+	dir := t.TempDir()
+	t.Setenv("TOKEN_SYMMETRIC_KEY", testSymmetricKey)
+	t.Setenv("DATABASE", "userDB")
+	t.Setenv("TENANTS", "acme,globex")
+	t.Setenv("LOGIN_MAX_FAILURES", "3")
+
+	config, err := LoadConfig(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"acme", "globex"}, config.Tenants)
+	require.Equal(t, 3, config.LoginMaxFailures)
+}
+
+func TestLoadConfigSecretFiles(t *testing.T) {
+	dir := writeConfigFile(t, "DATABASE=userDB\nPASSWORD=from-config-file\n")
+
+	keyFile := filepath.Join(t.TempDir(), "token_key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(testSymmetricKey+"\n"), 0o600))
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("s3cret\n"), 0o600))
+	t.Setenv("TOKEN_SYMMETRIC_KEY_FILE", keyFile)
+	t.Setenv("PASSWORD_FILE", passwordFile)
+
+	config, err := LoadConfig(dir)
+	require.NoError(t, err)
+	// the trailing newline is not part of the secret
+	require.Equal(t, testSymmetricKey, config.TokenSymmetricKey)
+	// the file overrides the config file
+	require.Equal(t, "s3cret", config.Password)
+}
+
+func TestLoadConfigSecretFileConflict(t *testing.T) {
+	dir := writeConfigFile(t, "DATABASE=userDB\n")
+
+	keyFile := filepath.Join(t.TempDir(), "token_key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(testSymmetricKey), 0o600))
+	t.Setenv("TOKEN_SYMMETRIC_KEY", testSymmetricKey)
+	t.Setenv("TOKEN_SYMMETRIC_KEY_FILE", keyFile)
+
+	_, err := LoadConfig(dir)
+	require.EqualError(t, err, "TOKEN_SYMMETRIC_KEY and TOKEN_SYMMETRIC_KEY_FILE cannot both be set")
+
+	t.Setenv("TOKEN_SYMMETRIC_KEY", "")
+	t.Setenv("TOKEN_SYMMETRIC_KEY_FILE", filepath.Join(t.TempDir(), "missing"))
+	_, err = LoadConfig(dir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestValidateConfig(t *testing.T) {
+	config := Config{
+		TokenSymmetricKey:    "short",
+		AccessTokenDuration:  time.Hour,
+		RefreshTokenDuration: time.Minute,
+		HTTPServerAddress:    "8080",
+		MongoURL:             "localhost:27017",
+		PosterStorage:        "s3",
+		PurgeInterval:        -time.Hour,
+		TLSCertFile:          "cert.pem",
+		Tenants:              []string{"acme", "Globex"},
+		TrustedProxies:       []string{"10.0.0.0/8", "proxy.example.com"},
+	}
+
+	// every problem is reported at once
+	err := config.Validate()
+	require.Error(t, err)
+	for _, message := range []string{
+		"TOKEN_SYMMETRIC_KEY must be exactly 32 characters, got 5",
+		"REFRESH_TOKEN_DURATION must not be shorter than ACCESS_TOKEN_DURATION",
+		"HTTP_SERVER_ADDRESS must be host:port",
+		"MONGO_URL must be a mongodb:// or mongodb+srv:// URL",
+		"DATABASE is required",
+		`POSTER_STORAGE must be local or gridfs, got "s3"`,
+		"PURGE_INTERVAL must be positive",
+		"SEAT_HOLD_DURATION must be positive",
+		"POSTER_MAX_SIZE must be positive",
+		"TENANT_HEADER is required with TENANTS",
+		"LOGIN_MAX_FAILURES must be positive",
+		`TENANTS must have up to 32 lowercase letters, digits and dashes per tenant, got "Globex"`,
+		"TLS_CERT_FILE and TLS_KEY_FILE must be set together",
+		`TRUSTED_PROXIES must list IP addresses or CIDR ranges, got "proxy.example.com"`,
+	} {
+		require.ErrorContains(t, err, message)
+	}
+
+	config = Config{
+		TokenSymmetricKey:    testSymmetricKey,
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenDuration: 24 * time.Hour,
+		HTTPServerAddress:    "0.0.0.0:8080",
+		MongoURL:             "mongodb+srv://cluster.example.com",
+		Database:             "userDB",
+		PosterStorage:        "local",
+		PosterDir:            "posters",
+		PosterMaxSize:        5 << 20,
+		TenantHeader:         "X-Tenant-ID",
+		LoginAttemptsPerIP:   20,
+		LoginAttemptsPerUser: 5,
+		LoginMaxFailures:     5,
+		LoginLockout:         15 * time.Minute,
+		ShutdownTimeout:      30 * time.Second,
+		SoftDeleteRetention:  30 * 24 * time.Hour,
+		PurgeInterval:        24 * time.Hour,
+		SeatHoldDuration:     10 * time.Minute,
+		Tenants:              []string{"acme", "globex-2"},
+		TrustedProxies:       []string{"10.0.0.1", "fd00::/8"},
+	}
+	require.NoError(t, config.Validate())
+}
+
+func TestLoadConfigAppEnv(t *testing.T) {
+	// the config shipped with the repository must stay valid
+	_, err := LoadConfig("..")
+	require.NoError(t, err)
+}
@@ -34,3 +34,12 @@ func IsValidUsername(username string) bool {
 	re := regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
 	return re.MatchString(username)
 }
+
+// Age returns the age in full years on the given date of someone born on dob
+func Age(dob time.Time, on time.Time) int {
+	years := on.Year() - dob.Year()
+	if on.Month() < dob.Month() || (on.Month() == dob.Month() && on.Day() < dob.Day()) {
+		years--
+	}
+	return years
+}
@@ -0,0 +1,17 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAge(t *testing.T) {
+	dob := time.Date(2000, time.March, 10, 0, 0, 0, 0, time.UTC)
+
+	require.Equal(t, 23, Age(dob, time.Date(2024, time.March, 9, 0, 0, 0, 0, time.UTC)))
+	require.Equal(t, 24, Age(dob, time.Date(2024, time.March, 10, 0, 0, 0, 0, time.UTC)))
+	require.Equal(t, 24, Age(dob, time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)))
+	require.Equal(t, 0, Age(dob, dob))
+}
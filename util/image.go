@@ -0,0 +1,25 @@
+package util
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// ResizeImage scales the image to the given width, keeping its aspect ratio.
+// Images that are already narrower are returned unchanged.
+func ResizeImage(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() <= width {
+		return img
+	}
+
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
@@ -36,7 +36,8 @@ func RandomOwner() string {
 	return RandomString(6)
 }
 func RandomHall() string {
-	return fmt.Sprintf("Sala%d", RandomInt(5, 100))
+	// hall names are unique, so the number alone would soon repeat
+	return fmt.Sprintf("Sala%d %s", RandomInt(5, 100), RandomString(6))
 }
 
 // RandomMoney generates a random amount of money
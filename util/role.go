@@ -3,4 +3,22 @@ package util
 const (
 	UserRole  = "user"
 	AdminRole = "admin"
+	// CinemaAdminRole is scoped to a single cinema, see CinemaRole
+	CinemaAdminRole = "cinema_admin"
 )
+
+// HasRole reports whether role is one of roles
+func HasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// CinemaRole scopes a role to the cinema with the given ID, as it is kept in
+// the roles of a user
+func CinemaRole(role string, cinemaID string) string {
+	return role + ":" + cinemaID
+}
@@ -0,0 +1,135 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+var cyrillicToLatin = map[rune]string{
+	'А': "A", 'Б': "B", 'В': "V", 'Г': "G", 'Д': "D", 'Ђ': "Đ", 'Е': "E", 'Ж': "Ž",
+	'З': "Z", 'И': "I", 'Ј': "J", 'К': "K", 'Л': "L", 'Љ': "Lj", 'М': "M", 'Н': "N",
+	'Њ': "Nj", 'О': "O", 'П': "P", 'Р': "R", 'С': "S", 'Т': "T", 'Ћ': "Ć", 'У': "U",
+	'Ф': "F", 'Х': "H", 'Ц': "C", 'Ч': "Č", 'Џ': "Dž", 'Ш': "Š",
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'ђ': "đ", 'е': "e", 'ж': "ž",
+	'з': "z", 'и': "i", 'ј': "j", 'к': "k", 'л': "l", 'љ': "lj", 'м': "m", 'н': "n",
+	'њ': "nj", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'ћ': "ć", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "c", 'ч': "č", 'џ': "dž", 'ш': "š",
+}
+
+var latinToCyrillic = map[rune]rune{
+	'A': 'А', 'B': 'Б', 'V': 'В', 'G': 'Г', 'D': 'Д', 'Đ': 'Ђ', 'E': 'Е', 'Ž': 'Ж',
+	'Z': 'З', 'I': 'И', 'J': 'Ј', 'K': 'К', 'L': 'Л', 'M': 'М', 'N': 'Н', 'O': 'О',
+	'P': 'П', 'R': 'Р', 'S': 'С', 'T': 'Т', 'Ć': 'Ћ', 'U': 'У', 'F': 'Ф', 'H': 'Х',
+	'C': 'Ц', 'Č': 'Ч', 'Š': 'Ш',
+	'a': 'а', 'b': 'б', 'v': 'в', 'g': 'г', 'd': 'д', 'đ': 'ђ', 'e': 'е', 'ž': 'ж',
+	'z': 'з', 'i': 'и', 'j': 'ј', 'k': 'к', 'l': 'л', 'm': 'м', 'n': 'н', 'o': 'о',
+	'p': 'п', 'r': 'р', 's': 'с', 't': 'т', 'ć': 'ћ', 'u': 'у', 'f': 'ф', 'h': 'х',
+	'c': 'ц', 'č': 'ч', 'š': 'ш',
+}
+
+// latinDigraphs are the Serbian Latin letters written with two characters
+var latinDigraphs = map[string]rune{
+	"lj": 'љ', "nj": 'њ', "dž": 'џ',
+	"Lj": 'Љ', "Nj": 'Њ', "Dž": 'Џ',
+	"LJ": 'Љ', "NJ": 'Њ', "DŽ": 'Џ',
+}
+
+// splitDigraphs are word beginnings in which the two letters of a digraph
+// belong to different morphemes, split by "-", e.g. nad-živeti. Such letters
+// are transliterated one by one: надживети, not наџивети.
+var splitDigraphs = []string{
+	"nad-ž", "od-žal", "pod-ž", "pred-ž",
+	"in-jek", "kon-jug", "kon-junk", "van-jez",
+}
+
+// IsSerbianLatin reports whether every letter of s belongs to the Serbian
+// Latin alphabet. Text with other letters, e.g. the x of "The Matrix", is not
+// Serbian and must not be transliterated.
+func IsSerbianLatin(s string) bool {
+	for _, r := range s {
+		if _, ok := latinToCyrillic[r]; !ok && unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ToLatin transliterates Serbian Cyrillic text to Latin script. Characters
+// that are not Serbian Cyrillic letters are left as they are.
+func ToLatin(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i, r := range runes {
+		latin, ok := cyrillicToLatin[r]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+
+		// Љ, Њ and Џ are written in upper case entirely inside upper case words
+		if len([]rune(latin)) == 2 && unicode.IsUpper(r) && upperNeighbour(runes, i) {
+			latin = strings.ToUpper(latin)
+		}
+		b.WriteString(latin)
+	}
+
+	return b.String()
+}
+
+// upperNeighbour reports whether the letter next to position i is upper case
+func upperNeighbour(runes []rune, i int) bool {
+	if i+1 < len(runes) && unicode.IsLetter(runes[i+1]) {
+		return unicode.IsUpper(runes[i+1])
+	}
+	if i > 0 && unicode.IsLetter(runes[i-1]) {
+		return unicode.IsUpper(runes[i-1])
+	}
+	return false
+}
+
+// ToCyrillic transliterates Serbian Latin text to Cyrillic script. Characters
+// that are not Serbian Latin letters are left as they are.
+func ToCyrillic(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s) * 2)
+
+	for i := 0; i < len(runes); i++ {
+		if i+1 < len(runes) && !splitDigraph(runes, i) {
+			if cyrillic, ok := latinDigraphs[string(runes[i:i+2])]; ok {
+				b.WriteRune(cyrillic)
+				i++
+				continue
+			}
+		}
+
+		if cyrillic, ok := latinToCyrillic[runes[i]]; ok {
+			b.WriteRune(cyrillic)
+			continue
+		}
+		b.WriteRune(runes[i])
+	}
+
+	return b.String()
+}
+
+// splitDigraph reports whether the letters at i and i+1 are split by a
+// morpheme boundary listed in splitDigraphs
+func splitDigraph(runes []rune, i int) bool {
+	start := i
+	for start > 0 && unicode.IsLetter(runes[start-1]) {
+		start--
+	}
+	before := strings.ToLower(string(runes[start : i+1]))
+	after := strings.ToLower(string(runes[i+1:]))
+
+	for _, split := range splitDigraphs {
+		prefix, rest, _ := strings.Cut(split, "-")
+		if before == prefix && strings.HasPrefix(after, rest) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToCyrillic(t *testing.T) {
+	require.Equal(t, "Љубав и џем", ToCyrillic("Ljubav i džem"))
+	require.Equal(t, "ЊЕГОШ", ToCyrillic("NJEGOŠ"))
+	require.Equal(t, "Ђурђевдан 2", ToCyrillic("Đurđevdan 2"))
+	require.Equal(t, "Већ ћирилица", ToCyrillic("Већ ћирилица"))
+	require.Equal(t, "Надживети оџак", ToCyrillic("Nadživeti odžak"))
+	require.Equal(t, "ИНЈЕКЦИЈА и коњ", ToCyrillic("INJEKCIJA i konj"))
+}
+
+func TestIsSerbianLatin(t *testing.T) {
+	require.True(t, IsSerbianLatin("Maratonci trče počasni krug 2"))
+	require.True(t, IsSerbianLatin("Đurđevdan!"))
+	require.False(t, IsSerbianLatin("The Matrix"))
+	require.False(t, IsSerbianLatin("Amélie"))
+	require.False(t, IsSerbianLatin("Маратонци"))
+}
+
+func TestToLatin(t *testing.T) {
+	require.Equal(t, "Ljubav i džem", ToLatin("Љубав и џем"))
+	require.Equal(t, "NJEGOŠ", ToLatin("ЊЕГОШ"))
+	require.Equal(t, "Njegoš", ToLatin("Његош"))
+	require.Equal(t, "Lj", ToLatin("Љ"))
+	require.Equal(t, "Already latin", ToLatin("Already latin"))
+}
+
+func TestTransliterationRoundTrip(t *testing.T) {
+	text := "Čuvari formule, Ko to tamo peva, Maratonci trče počasni krug"
+	require.Equal(t, text, ToLatin(ToCyrillic(text)))
+}
@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+)
+
+// Purger periodically removes movies, halls and repertoires that were soft
+// deleted longer than the retention window ago
+type Purger struct {
+	store     repository.Store
+	retention time.Duration
+	interval  time.Duration
+	now       func() time.Time
+	running   atomic.Bool
+}
+
+// NewPurger creates a purger that removes the records deleted longer than
+// retention ago on every interval, which must be positive
+func NewPurger(store repository.Store, retention, interval time.Duration) *Purger {
+	return &Purger{
+		store:     store,
+		retention: retention,
+		interval:  interval,
+		now:       time.Now,
+	}
+}
+
+// Run purges once right away and then on every interval until ctx is done
+func (p *Purger) Run(ctx context.Context) {
+	p.running.Store(true)
+	defer p.running.Store(false)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.PurgeOnce(ctx); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("cannot purge deleted records")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Running reports whether Run is running
+func (p *Purger) Running() bool {
+	return p.running.Load()
+}
+
+// PurgeOnce removes the records deleted before the retention window
+func (p *Purger) PurgeOnce(ctx context.Context) (repository.PurgeResult, error) {
+	before := p.now().UTC().Add(-p.retention)
+
+	result, err := p.store.PurgeDeleted(ctx, before)
+	if err != nil {
+		return result, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Time("before", before).
+		Int64("movies", result.Movies).
+		Int64("halls", result.Halls).
+		Int64("repertoires", result.Repertoires).
+		Msg("purged deleted records")
+
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	mockdb "github.com/tijanadmi/movieginmongoapi/db/mock"
+	"github.com/tijanadmi/movieginmongoapi/repository"
+)
+
+func TestPurgeOnce(t *testing.T) {
+	now := time.Date(2024, time.June, 30, 12, 0, 0, 0, time.UTC)
+	want := repository.PurgeResult{Movies: 1, Halls: 0, Repertoires: 3}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		PurgeDeleted(gomock.Any(), gomock.Eq(now.Add(-7*24*time.Hour))).
+		Times(1).
+		Return(want, nil)
+
+	purger := NewPurger(store, 7*24*time.Hour, time.Hour)
+	purger.now = func() time.Time { return now }
+
+	result, err := purger.PurgeOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, want, result)
+}
+
+func TestPurgerRunStopsOnCancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	store := mockdb.NewMockStore(ctrl)
+	purger := NewPurger(store, time.Hour, time.Hour)
+	store.EXPECT().
+		PurgeDeleted(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(context.Context, time.Time) (repository.PurgeResult, error) {
+			require.True(t, purger.Running())
+			cancel()
+			return repository.PurgeResult{}, errors.New("connection lost")
+		})
+
+	done := make(chan struct{})
+	go func() {
+		purger.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("purger did not stop after the context was canceled")
+	}
+	require.False(t, purger.Running())
+}